@@ -1,10 +1,16 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // Base de fusos embutida, ausente na imagem alpine
 
 	"github.com/jeffemart/PontoGo/app/internal/config"
 	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/services/pontomais"
 	"github.com/jeffemart/PontoGo/app/internal/services/telegram"
 	"github.com/jeffemart/PontoGo/app/internal/utils"
 )
@@ -25,30 +31,48 @@ func init() {
 		utils.Logger.Fatalf("Erro ao carregar as configurações: %v", err)
 	}
 	utils.Logger.Println("Configurações carregadas com sucesso")
+
+	// Aplica TIME_ZONE a todo o processo: "hoje" e "ontem" nos comandos, os
+	// relatórios diários e os horários dos agendamentos usam o fuso local
+	if cfg.TimeZone != "" {
+		location, err := time.LoadLocation(cfg.TimeZone)
+		if err != nil {
+			utils.Logger.Fatalf("Erro ao carregar o fuso horário: %v", err)
+		}
+		time.Local = location
+	}
 }
 
 func main() {
-	// Exibir as configurações carregadas
-	fmt.Println("Configurações carregadas:")
-	fmt.Println("PontoMais Token:", cfg.PontoMaisToken)
-	fmt.Println("PontoMais Base URL:", cfg.PontoMaisBaseURL)
-	fmt.Println("Telegram Bot Token:", cfg.TelegramBotToken)
-	fmt.Println("Telegram Hosts:", cfg.TelegramHosts)
-	fmt.Println("Debug:", cfg.Debug)
+	// Registra as configurações carregadas, sem os tokens
+	utils.Logger.Printf("Configurações: PontoMais Base URL=%s, Timeout=%s, Rate Limit=%v (burst %d), Telegram Hosts=%v, Workers=%d, "+
+		"Conversation Timeout=%s, Digest Time=%q (rules %v, tolerance %s), Schedules File=%q (chat %d), Time Zone=%s, Debug=%t",
+		cfg.PontoMaisBaseURL, cfg.PontoMaisTimeout, cfg.PontoMaisRateLimit, cfg.PontoMaisRateBurst, cfg.TelegramHosts, cfg.TelegramWorkers,
+		cfg.ConversationTimeout, cfg.DigestTime, cfg.DigestRules, cfg.DigestTolerance, cfg.SchedulesFile, cfg.ScheduleChat, time.Local, cfg.Debug)
 
 	// Verificar se o token do bot está definido
 	if cfg.TelegramBotToken == "" {
 		utils.Logger.Fatal("Erro: TELEGRAM_BOT_TOKEN não definido.")
 	}
 
+	// Inicializa o cliente da API do Ponto Mais, compartilhado por todo o bot
+	client, err := pontomais.NewClient(cfg, pontomais.WithLogger(utils.Logger))
+	if err != nil {
+		utils.Logger.Fatalf("Erro ao inicializar o cliente do Ponto Mais: %v", err)
+	}
+
 	// Inicializa o bot do Telegram
-	bot, err := telegram.NewBot(cfg)
+	bot, err := telegram.NewBot(cfg, client)
 	if err != nil {
 		utils.Logger.Fatalf("Erro ao inicializar o bot do Telegram: %v", err)
 	}
 	utils.Logger.Println("Bot do Telegram inicializado com sucesso")
 
+	// Encerra o bot de forma ordenada ao receber SIGINT ou SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Inicia o bot
 	utils.Logger.Println("Iniciando o bot do Telegram...")
-	bot.Start()
+	bot.Start(ctx)
 }
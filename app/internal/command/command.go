@@ -0,0 +1,365 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+)
+
+// Kind é o tipo de valor de um parâmetro
+type Kind int
+
+const (
+	String   Kind = iota
+	Int           // Número inteiro, como IDs
+	Bool          // true/false, sim/não; como flag, basta a presença (--debito)
+	Date          // AAAA-MM-DD, DD/MM/AAAA, DD/MM, hoje ou ontem, no fuso local (TIME_ZONE)
+	Duration      // Quantidade de tempo em segundos (ver duration.Parse)
+)
+
+// Param descreve um parâmetro de comando. Ele pode ser informado na ordem
+// (posicional) ou pelo nome, como flag: --data=2025-03-18, --data 2025-03-18
+// ou, para Bool, apenas --debito.
+type Param struct {
+	Name     string
+	Aliases  []string // Outros nomes aceitos como flag
+	Kind     Kind
+	Optional bool
+	Default  string // Valor usado quando o parâmetro opcional não é informado, ex.: "hoje"
+	Rest     bool   // Recebe todos os argumentos restantes, unidos por espaço (só no último parâmetro String)
+	Help     string
+}
+
+// Spec é o esquema de um comando: seus parâmetros e textos de ajuda
+type Spec struct {
+	Name     string // Sem a barra, ex.: "criar"
+	Summary  string
+	Params   []Param
+	Examples []string
+}
+
+// Args são os valores já convertidos dos parâmetros de um comando
+type Args struct {
+	values map[string]interface{}
+}
+
+// Has indica se o parâmetro foi informado ou tem valor padrão
+func (a Args) Has(name string) bool {
+	_, ok := a.values[name]
+	return ok
+}
+
+// String devolve o valor de um parâmetro String
+func (a Args) String(name string) string {
+	value, _ := a.values[name].(string)
+	return value
+}
+
+// Int devolve o valor de um parâmetro Int
+func (a Args) Int(name string) int {
+	value, _ := a.values[name].(int)
+	return value
+}
+
+// Bool devolve o valor de um parâmetro Bool
+func (a Args) Bool(name string) bool {
+	value, _ := a.values[name].(bool)
+	return value
+}
+
+// Date devolve o valor de um parâmetro Date
+func (a Args) Date(name string) time.Time {
+	value, _ := a.values[name].(time.Time)
+	return value
+}
+
+// Duration devolve o valor em segundos de um parâmetro Duration
+func (a Args) Duration(name string) float64 {
+	value, _ := a.values[name].(float64)
+	return value
+}
+
+// Parse interpreta os argumentos de um comando (o texto após o /comando).
+// Os parâmetros posicionais são preenchidos na ordem do esquema; um parâmetro
+// opcional que não seja String é pulado quando o argumento não é do seu tipo
+// (ou está entre aspas), o que permite omitir, por exemplo, a data: /criar 1487972 2h "Horas extras".
+// Um argumento com cara de data (31/02/2025) nunca pula o parâmetro Date: se
+// for inválido, é um erro, e não o início da observação.
+func (s Spec) Parse(text string) (Args, error) {
+	tokens, err := Tokenize(text)
+	if err != nil {
+		return Args{}, err
+	}
+
+	raw := make(map[string]string)
+	var positional []Token
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		name, value, hasValue, isFlag := splitFlag(token)
+		if !isFlag {
+			positional = append(positional, token)
+			continue
+		}
+
+		param, ok := s.param(name)
+		if !ok {
+			return Args{}, fmt.Errorf("opção desconhecida: --%s", name)
+		}
+		if !hasValue {
+			if param.Kind == Bool {
+				value = "true"
+			} else if i+1 < len(tokens) {
+				i++
+				value = tokens[i].Value
+			} else {
+				return Args{}, fmt.Errorf("falta o valor de --%s", param.Name)
+			}
+		}
+		if _, dup := raw[param.Name]; dup {
+			return Args{}, fmt.Errorf("parâmetro %s informado mais de uma vez", param.Name)
+		}
+		raw[param.Name] = value
+	}
+
+	args := Args{values: make(map[string]interface{})}
+	for name, value := range raw {
+		param, _ := s.param(name)
+		parsed, err := parseValue(param, value)
+		if err != nil {
+			return Args{}, err
+		}
+		args.values[name] = parsed
+	}
+
+	next := 0
+	for i, token := range positional {
+		if next < len(s.Params) && s.Params[next].Rest {
+			if _, given := raw[s.Params[next].Name]; !given {
+				var rest []string
+				for _, t := range positional[i:] {
+					rest = append(rest, t.Value)
+				}
+				args.values[s.Params[next].Name] = strings.Join(rest, " ")
+				break
+			}
+		}
+
+		assigned := false
+		for next < len(s.Params) {
+			param := s.Params[next]
+			next++
+			if _, given := raw[param.Name]; given {
+				continue
+			}
+			skippable := param.Optional && param.Kind != String
+			if skippable && token.Quoted {
+				continue
+			}
+			parsed, err := parseValue(param, token.Value)
+			if err != nil {
+				if skippable && !(param.Kind == Date && dateLikeRe.MatchString(token.Value)) {
+					continue
+				}
+				return Args{}, err
+			}
+			args.values[param.Name] = parsed
+			assigned = true
+			break
+		}
+		if !assigned {
+			if !token.Quoted {
+				return Args{}, fmt.Errorf("argumento a mais: '%s' (use aspas em textos com espaços)", token.Value)
+			}
+			return Args{}, fmt.Errorf("argumento a mais: '%s'", token.Value)
+		}
+	}
+
+	for _, param := range s.Params {
+		if args.Has(param.Name) {
+			continue
+		}
+		if !param.Optional {
+			return Args{}, fmt.Errorf("falta o parâmetro %s", param.Name)
+		}
+		if param.Default != "" {
+			parsed, err := parseValue(param, param.Default)
+			if err != nil {
+				return Args{}, err
+			}
+			args.values[param.Name] = parsed
+		}
+	}
+	return args, nil
+}
+
+// param busca um parâmetro pelo nome ou apelido, ignorando acentos e caixa
+func (s Spec) param(name string) (Param, bool) {
+	name = normalizeName(name)
+	for _, param := range s.Params {
+		if normalizeName(param.Name) == name {
+			return param, true
+		}
+		for _, alias := range param.Aliases {
+			if normalizeName(alias) == name {
+				return param, true
+			}
+		}
+	}
+	return Param{}, false
+}
+
+// splitFlag reconhece as flags --nome e --nome=valor. Textos entre aspas nunca
+// são flags, e os travessões que os teclados de celular colocam no lugar de
+// "--" são aceitos.
+func splitFlag(token Token) (name, value string, hasValue, ok bool) {
+	if token.Quoted {
+		return "", "", false, false
+	}
+	text := token.Value
+	switch {
+	case strings.HasPrefix(text, "--"):
+		text = text[len("--"):]
+	case strings.HasPrefix(text, "—"):
+		text = text[len("—"):]
+	case strings.HasPrefix(text, "–"):
+		text = text[len("–"):]
+	default:
+		return "", "", false, false
+	}
+	if text == "" {
+		return "", "", false, false
+	}
+	name, value, hasValue = strings.Cut(text, "=")
+	return name, value, hasValue, true
+}
+
+// dateLikeRe reconhece argumentos escritos como data (18/03, 2025-03-18,
+// 31/02/2025), válidos ou não
+var dateLikeRe = regexp.MustCompile(`^\d{1,4}[/-]\d{1,2}(?:[/-]\d{1,4})?$`)
+
+// nameReplacer remove os acentos dos nomes de parâmetros
+var nameReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ç", "c",
+)
+
+// normalizeName padroniza um nome de parâmetro para comparação
+func normalizeName(name string) string {
+	return nameReplacer.Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// parseValue converte o valor de um parâmetro para o seu tipo
+func parseValue(param Param, value string) (interface{}, error) {
+	switch param.Kind {
+	case Int:
+		n, err := strconv.Atoi(strings.TrimPrefix(value, "#"))
+		if err != nil {
+			return nil, fmt.Errorf("%s inválido '%s': informe um número", param.Name, value)
+		}
+		return n, nil
+	case Bool:
+		b, ok := ParseBool(value)
+		if !ok {
+			return nil, fmt.Errorf("%s inválido '%s': use true/false ou sim/não", param.Name, value)
+		}
+		return b, nil
+	case Date:
+		date, err := ParseDate(value, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("%s inválida '%s': use AAAA-MM-DD, DD/MM/AAAA, DD/MM, hoje ou ontem", param.Name, value)
+		}
+		return date, nil
+	case Duration:
+		seconds, err := duration.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		return seconds, nil
+	default:
+		return value, nil
+	}
+}
+
+// ParseBool interpreta true/false, sim/não, s/n e 1/0
+func ParseBool(value string) (bool, bool) {
+	switch normalizeName(value) {
+	case "true", "sim", "s", "1":
+		return true, true
+	case "false", "nao", "n", "0":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// ParseDate interpreta AAAA-MM-DD, DD/MM/AAAA, DD/MM (no ano de now), "hoje" e
+// "ontem". A data devolvida é a meia-noite no fuso de now. Os comandos passam
+// time.Now(), cujo fuso é o de TIME_ZONE (ver config.LoadConfig), então "hoje"
+// e "ontem" seguem o calendário da empresa, não o do servidor.
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch normalizeName(value) {
+	case "hoje":
+		return today, nil
+	case "ontem":
+		return today.AddDate(0, 0, -1), nil
+	}
+	for _, layout := range []string{"2006-01-02", "2/1/2006"} {
+		if date, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return date, nil
+		}
+	}
+	if date, err := time.Parse("2/1", value); err == nil {
+		return time.Date(now.Year(), date.Month(), date.Day(), 0, 0, 0, 0, now.Location()), nil
+	}
+	return time.Time{}, fmt.Errorf("data inválida '%s'", value)
+}
+
+// Usage devolve a linha de uso do comando, ex.: "/criar <funcionario> <quantidade> [data]"
+func (s Spec) Usage() string {
+	parts := []string{"/" + s.Name}
+	for _, param := range s.Params {
+		name := param.Name
+		if param.Rest {
+			name += "..."
+		}
+		if param.Optional {
+			parts = append(parts, "["+name+"]")
+		} else {
+			parts = append(parts, "<"+name+">")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Help devolve a ajuda detalhada do comando: uso, parâmetros e exemplos
+func (s Spec) Help() string {
+	var text strings.Builder
+	text.WriteString(s.Usage())
+	text.WriteString("\n")
+	text.WriteString(s.Summary)
+
+	if len(s.Params) > 0 {
+		text.WriteString("\n\nParâmetros:")
+		for _, param := range s.Params {
+			text.WriteString(fmt.Sprintf("\n- %s: %s", param.Name, param.Help))
+			if param.Default != "" {
+				text.WriteString(fmt.Sprintf(" (padrão: %s)", param.Default))
+			}
+		}
+		text.WriteString(fmt.Sprintf("\n\nOs parâmetros também podem ser informados pelo nome, em qualquer ordem (ex.: --%s=valor). Textos com espaços devem estar entre aspas.", s.Params[0].Name))
+	}
+
+	if len(s.Examples) > 0 {
+		text.WriteString("\n\nExemplos:")
+		for _, example := range s.Examples {
+			text.WriteString("\n" + example)
+		}
+	}
+	return text.String()
+}
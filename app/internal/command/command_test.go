@@ -0,0 +1,124 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Mesmo esquema do /criar
+var createSpec = Spec{Name: "criar", Params: []Param{
+	{Name: "funcionario", Kind: Int},
+	{Name: "quantidade", Kind: Duration},
+	{Name: "data", Kind: Date, Optional: true, Default: "hoje"},
+	{Name: "observacao", Aliases: []string{"obs"}, Kind: String},
+	{Name: "debito", Aliases: []string{"retirada"}, Kind: Bool, Optional: true, Default: "false"},
+}}
+
+func TestSpecParse(t *testing.T) {
+	today := time.Now()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
+	march18 := time.Date(2025, 3, 18, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		text    string
+		date    time.Time
+		obs     string
+		seconds float64
+		debit   bool
+		wantErr string
+	}{
+		{"completo", `1487972 2h 2025-03-18 "Horas extras" true`, march18, "Horas extras", 7200, true, ""},
+		{"sem data", `1487972 90min "Ajuste de ponto"`, today, "Ajuste de ponto", 5400, false, ""},
+		{"data DD/MM/AAAA", `1487972 2h 18/03/2025 obs`, march18, "obs", 7200, false, ""},
+		{"flags", `1487972 1h30m --data=2025-03-18 --obs="Folga compensada" --debito`, march18, "Folga compensada", 5400, true, ""},
+		{"flag com valor separado", `1487972 2h --obs Ajuste --retirada=nao`, today, "Ajuste", 7200, false, ""},
+		{"travessão do celular", `1487972 2h —obs=Ajuste`, today, "Ajuste", 7200, false, ""},
+		{"data entre aspas é texto", `1487972 2h "18/03/2025"`, today, "18/03/2025", 7200, false, ""},
+		{"data inexistente", `123 2h 31/02/2025 obs`, time.Time{}, "", 0, false, "data inválida"},
+		{"data com mês inválido", `123 2h 2025-13-01 obs`, time.Time{}, "", 0, false, "data inválida"},
+		{"falta a observação", `1487972 2h`, time.Time{}, "", 0, false, "falta o parâmetro observacao"},
+		{"quantidade ambígua", `1487972 2 obs`, time.Time{}, "", 0, false, "ambígua"},
+		{"argumento a mais", `1487972 2h obs false extra`, time.Time{}, "", 0, false, "argumento a mais"},
+		{"opção desconhecida", `1487972 2h obs --foo=1`, time.Time{}, "", 0, false, "opção desconhecida"},
+		{"parâmetro repetido", `1487972 2h --obs=a --obs=b`, time.Time{}, "", 0, false, "mais de uma vez"},
+		{"flag sem valor", `1487972 2h obs --data`, time.Time{}, "", 0, false, "falta o valor"},
+	}
+	for _, tt := range tests {
+		args, err := createSpec.Parse(tt.text)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: erro = %v, esperado %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: erro inesperado: %v", tt.name, err)
+			continue
+		}
+		if args.Int("funcionario") != 1487972 || args.Duration("quantidade") != tt.seconds {
+			t.Errorf("%s: funcionario = %d, quantidade = %v", tt.name, args.Int("funcionario"), args.Duration("quantidade"))
+		}
+		if !args.Date("data").Equal(tt.date) {
+			t.Errorf("%s: data = %s, esperado %s", tt.name, args.Date("data"), tt.date)
+		}
+		if args.String("observacao") != tt.obs || args.Bool("debito") != tt.debit {
+			t.Errorf("%s: observacao = %q, debito = %v", tt.name, args.String("observacao"), args.Bool("debito"))
+		}
+	}
+}
+
+func TestSpecParseRest(t *testing.T) {
+	spec := Spec{Name: "agendar", Params: []Param{
+		{Name: "tarefa", Kind: String, Optional: true},
+		{Name: "quando", Kind: String, Optional: true, Rest: true},
+		{Name: "aba", Kind: String, Optional: true},
+	}}
+	args, err := spec.Parse(`importacao 01/11/2026 08:00 --aba=Novembro`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args.String("tarefa") != "importacao" || args.String("quando") != "01/11/2026 08:00" || args.String("aba") != "Novembro" {
+		t.Errorf("argumentos = %q, %q, %q", args.String("tarefa"), args.String("quando"), args.String("aba"))
+	}
+	if args, err = spec.Parse(""); err != nil || args.Has("tarefa") || args.Has("quando") {
+		t.Errorf("sem argumentos: %v, %v", args, err)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	location := time.FixedZone("BRT", -3*3600)
+	// 01:30 do dia 18 em Brasília ainda é dia 18, embora já seja 04:30 em UTC
+	now := time.Date(2025, 3, 18, 1, 30, 0, 0, location)
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, location)
+	}
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"hoje", day(2025, 3, 18), false},
+		{"Hoje", day(2025, 3, 18), false},
+		{"ontem", day(2025, 3, 17), false},
+		{"2025-03-01", day(2025, 3, 1), false},
+		{"1/3/2025", day(2025, 3, 1), false},
+		{"01/03/2025", day(2025, 3, 1), false},
+		{"25/12", day(2025, 12, 25), false},
+		{"31/02/2025", time.Time{}, true},
+		{"2025-02-30", time.Time{}, true},
+		{"amanha", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.value, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDate(%q): erro = %v, esperava erro: %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (!got.Equal(tt.want) || got.Location() != location) {
+			t.Errorf("ParseDate(%q) = %s, esperado %s", tt.value, got, tt.want)
+		}
+	}
+}
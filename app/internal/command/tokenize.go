@@ -0,0 +1,128 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Token é um argumento já separado. Quoted indica que ele veio entre aspas e,
+// por isso, nunca é tratado como flag.
+type Token struct {
+	Value  string
+	Quoted bool
+}
+
+// closingQuotes associa cada aspa de abertura às aspas que a fecham. As aspas
+// tipográficas (“”, „“, ‘’, «») são as que os teclados de celular inserem;
+// alguns as fecham com o mesmo caractere da abertura, por isso as duas formas
+// são aceitas.
+var closingQuotes = map[rune]string{
+	'"':  `"`,
+	'\'': `'`,
+	'“':  "”“",
+	'”':  "”“",
+	'„':  "“”",
+	'‘':  "’‘",
+	'’':  "’‘",
+	'«':  "»",
+}
+
+// Tokenize separa os argumentos por espaços (de qualquer tipo e quantidade).
+// Um argumento que começa com aspas vai até a aspa de fechamento
+// correspondente seguida de espaço ou do fim do texto, então aspas no meio de
+// uma palavra (d'água) e aspas de outro tipo são mantidas. Aspas iguais às de
+// abertura no início de uma palavra abrem um trecho interno, que precisa ser
+// fechado antes: "Ajuste "extra" de ponto". O valor de uma flag também pode
+// vir entre aspas (--obs="Folga compensada"). Dentro das aspas, \" (ou \
+// seguido da aspa de fechamento) e \\ representam os próprios caracteres.
+func Tokenize(text string) ([]Token, error) {
+	var tokens []Token
+	var current strings.Builder
+	inToken, quoted := false, false
+	var opener rune
+	closers := "" // Aspas que fecham o trecho atual; vazio fora das aspas
+	depth := 0    // Trechos entre aspas abertos no argumento atual, contando os internos
+
+	runes := []rune(text)
+	isQuote := func(r rune) bool { return r == opener || strings.ContainsRune(closers, r) }
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case closers != "":
+			if r == '\\' && i+1 < len(runes) && (runes[i+1] == '\\' || strings.ContainsRune(closers, runes[i+1])) {
+				i++
+				current.WriteRune(runes[i])
+				break
+			}
+			if !isQuote(r) {
+				current.WriteRune(r)
+				break
+			}
+
+			// Uma sequência de aspas seguida de espaço ou do fim do texto fecha
+			// os trechos abertos, do mais interno ao externo; as aspas que
+			// sobram no início da sequência são texto
+			end := i
+			for end < len(runes) && isQuote(runes[end]) {
+				end++
+			}
+			if end < len(runes) && !unicode.IsSpace(runes[end]) {
+				if i > 0 && unicode.IsSpace(runes[i-1]) {
+					depth++
+				}
+				current.WriteString(string(runes[i:end]))
+				i = end - 1
+				break
+			}
+			literal := end - i - depth
+			for k := i; k < end; k++ {
+				switch {
+				case k < i+literal:
+					current.WriteRune(runes[k])
+				case depth > 1:
+					current.WriteRune(runes[k])
+					depth--
+				default:
+					depth, closers = 0, ""
+				}
+			}
+			i = end - 1
+
+		case unicode.IsSpace(r):
+			if inToken {
+				tokens = append(tokens, Token{Value: current.String(), Quoted: quoted})
+				current.Reset()
+				inToken, quoted = false, false
+			}
+
+		case !inToken && closingQuotes[r] != "":
+			inToken, quoted = true, true
+			opener, closers, depth = r, closingQuotes[r], 1
+
+		case inToken && !quoted && closingQuotes[r] != "" && isFlagAssignment(current.String()):
+			// Valor de flag entre aspas: --obs="Folga compensada". O argumento
+			// continua sendo uma flag, por isso não é marcado como Quoted.
+			opener, closers, depth = r, closingQuotes[r], 1
+
+		default:
+			inToken = true
+			current.WriteRune(r)
+		}
+	}
+
+	if closers != "" {
+		return nil, fmt.Errorf("aspas não fechadas")
+	}
+	if inToken {
+		tokens = append(tokens, Token{Value: current.String(), Quoted: quoted})
+	}
+	return tokens, nil
+}
+
+// isFlagAssignment indica se o texto é o início de uma flag com valor, como
+// "--obs=", ainda sem o valor
+func isFlagAssignment(text string) bool {
+	_, value, hasValue, ok := splitFlag(Token{Value: text})
+	return ok && hasValue && value == ""
+}
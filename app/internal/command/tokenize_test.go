@@ -0,0 +1,72 @@
+package command
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	q := func(value string) Token { return Token{Value: value, Quoted: true} }
+	p := func(value string) Token { return Token{Value: value} }
+	tests := []struct {
+		text    string
+		want    []Token
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"  1487972 \t 2h\n", []Token{p("1487972"), p("2h")}, false},
+		{`1487972 2h "Horas extras"`, []Token{p("1487972"), p("2h"), q("Horas extras")}, false},
+		{
+			`1487972 7200.0 2025-03-18 "Ajuste "extra" de ponto" false`,
+			[]Token{p("1487972"), p("7200.0"), p("2025-03-18"), q(`Ajuste "extra" de ponto`), p("false")},
+			false,
+		},
+		{`"Ajuste "extra""`, []Token{q(`Ajuste "extra"`)}, false},
+		{`"a "b c" d" e`, []Token{q(`a "b c" d`), p("e")}, false},
+		{`“Ajuste "extra" de ponto” x`, []Token{q(`Ajuste "extra" de ponto`), p("x")}, false},
+		{`“Ajuste “extra” de ponto”`, []Token{q("Ajuste “extra” de ponto")}, false},
+		{`„Ajuste“ x`, []Token{q("Ajuste"), p("x")}, false},
+		{`'Caixa d'água'`, []Token{q("Caixa d'água")}, false},
+		{`"a" "b"`, []Token{q("a"), q("b")}, false},
+		{`""`, []Token{q("")}, false},
+		{`"Ajuste \"extra\""`, []Token{q(`Ajuste "extra"`)}, false},
+		{`"C:\\temp"`, []Token{q(`C:\temp`)}, false},
+		{`--obs="Folga compensada" --debito`, []Token{p("--obs=Folga compensada"), p("--debito")}, false},
+		{`—obs=“Ajuste "extra"”`, []Token{p(`—obs=Ajuste "extra"`)}, false},
+		{`--obs="sem fim`, nil, true},
+		{`a="b c"`, []Token{p(`a="b`), p(`c"`)}, false},
+		{`"sem fim`, nil, true},
+		{`"Ajuste "extra de ponto"`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := Tokenize(tt.text)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Tokenize(%q): erro = %v, esperava erro: %v", tt.text, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %+v, esperado %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseInnerQuotes(t *testing.T) {
+	// Mesmo esquema do /editar
+	spec := Spec{Name: "editar", Params: []Param{
+		{Name: "id", Kind: Int},
+		{Name: "quantidade", Kind: Duration},
+		{Name: "data", Kind: Date},
+		{Name: "observacao", Kind: String},
+		{Name: "debito", Kind: Bool, Optional: true},
+	}}
+	args, err := spec.Parse(`1487972 7200.0 2025-03-18 "Ajuste "extra" de ponto" false`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := args.String("observacao"); got != `Ajuste "extra" de ponto` {
+		t.Errorf("observacao = %q", got)
+	}
+	if !args.Has("debito") || args.Bool("debito") {
+		t.Errorf("debito = %v", args.Bool("debito"))
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/timecards"
 	"github.com/joho/godotenv"
 )
 
@@ -36,13 +38,110 @@ func LoadConfig() (*models.Config, error) {
 		debug = false
 	}
 
+	// Converter PONTOMAIS_TIMEOUT (ex.: "30s") para time.Duration
+	pontoMaisTimeout, erro := durationEnv("PONTOMAIS_TIMEOUT")
+	if erro != nil {
+		return nil, erro
+	}
+
+	// Converter as configurações de retentativa das chamadas ao Ponto Mais.
+	// Valores não definidos ficam zerados e o cliente usa os padrões.
+	maxAttempts, erro := intEnv("PONTOMAIS_MAX_ATTEMPTS")
+	if erro != nil {
+		return nil, erro
+	}
+	retryBaseDelay, erro := durationEnv("PONTOMAIS_RETRY_BASE_DELAY")
+	if erro != nil {
+		return nil, erro
+	}
+	retryMaxDelay, erro := durationEnv("PONTOMAIS_RETRY_MAX_DELAY")
+	if erro != nil {
+		return nil, erro
+	}
+
+	// Converter o limite de requisições ao Ponto Mais (ex.: "5" ou "2.5" por segundo)
+	rateLimit, erro := floatEnv("PONTOMAIS_RATE_LIMIT")
+	if erro != nil {
+		return nil, erro
+	}
+	rateBurst, erro := intEnv("PONTOMAIS_RATE_BURST")
+	if erro != nil {
+		return nil, erro
+	}
+
+	// Converter TELEGRAM_WORKERS, a quantidade de chats atendidos em paralelo
+	telegramWorkers, erro := intEnv("TELEGRAM_WORKERS")
+	if erro != nil {
+		return nil, erro
+	}
+
+	// Converter CONVERSATION_TIMEOUT (ex.: "10m"), o prazo de resposta nos assistentes
+	conversationTimeout, erro := durationEnv("CONVERSATION_TIMEOUT")
+	if erro != nil {
+		return nil, erro
+	}
+
+	// Converter as configurações do relatório diário de ocorrências nas batidas
+	digestTime := strings.TrimSpace(os.Getenv("DIGEST_TIME"))
+	if digestTime != "" {
+		if _, err := time.Parse("15:04", digestTime); err != nil {
+			return nil, fmt.Errorf("erro ao converter DIGEST_TIME: use HH:MM, ex.: 08:00")
+		}
+	}
+	digestRules := os.Getenv("DIGEST_RULES")
+	if digestRules == "" {
+		digestRules = "batida_faltando,sem_batidas,hora_extra"
+	}
+	digestKinds, erro := timecards.ParseIssueKinds(digestRules)
+	if erro != nil {
+		return nil, fmt.Errorf("erro ao converter DIGEST_RULES: %v", erro)
+	}
+	digestTolerance, erro := durationEnv("DIGEST_TOLERANCE")
+	if erro != nil {
+		return nil, erro
+	}
+
+	// Converter TIME_ZONE, o fuso usado nas datas relativas (hoje, ontem) e nos
+	// horários dos agendamentos. Vazio mantém o fuso do processo (variável TZ).
+	timeZone := strings.Trim(strings.TrimSpace(os.Getenv("TIME_ZONE")), `'"`)
+	if timeZone != "" {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			return nil, fmt.Errorf("erro ao converter TIME_ZONE: fuso desconhecido %q, use o nome IANA, ex.: America/Sao_Paulo", timeZone)
+		}
+	}
+
+	// Converter SCHEDULE_CHAT, o chat que recebe o resultado das tarefas agendadas
+	var scheduleChat int64
+	if value := strings.TrimSpace(os.Getenv("SCHEDULE_CHAT")); value != "" {
+		scheduleChat, erro = strconv.ParseInt(value, 10, 64)
+		if erro != nil {
+			return nil, fmt.Errorf("erro ao converter SCHEDULE_CHAT: %v", erro)
+		}
+	}
+
 	// Criar a configuração
 	cfg := &models.Config{
-		PontoMaisToken:   os.Getenv("PONTOMAIS_TOKEN"),
-		PontoMaisBaseURL: os.Getenv("PONTOMAIS_BASE_URL"),
-		TelegramBotToken: os.Getenv("TELEGRAM_BOT_TOKEN"),
-		TelegramHosts:    telegramHosts,
-		Debug:            debug,
+		PontoMaisToken:          os.Getenv("PONTOMAIS_TOKEN"),
+		PontoMaisBaseURL:        os.Getenv("PONTOMAIS_BASE_URL"),
+		PontoMaisTimeout:        pontoMaisTimeout,
+		PontoMaisMaxAttempts:    maxAttempts,
+		PontoMaisRetryBaseDelay: retryBaseDelay,
+		PontoMaisRetryMaxDelay:  retryMaxDelay,
+		PontoMaisRateLimit:      rateLimit,
+		PontoMaisRateBurst:      rateBurst,
+		TelegramBotToken:        os.Getenv("TELEGRAM_BOT_TOKEN"),
+		TelegramHosts:           telegramHosts,
+		TelegramWorkers:         telegramWorkers,
+		ConversationTimeout:     conversationTimeout,
+		DataDir:                 os.Getenv("DATA_DIR"),
+		ImportColumnsFile:       os.Getenv("IMPORT_COLUMNS_FILE"),
+		ImportSheet:             os.Getenv("IMPORT_SHEET"),
+		DigestTime:              digestTime,
+		DigestTolerance:         digestTolerance,
+		SchedulesFile:           os.Getenv("SCHEDULES_FILE"),
+		ScheduleChat:            scheduleChat,
+		TimeZone:                timeZone,
+		Debug:                   debug,
 	}
 
 	// Validação básica das configurações
@@ -63,5 +162,70 @@ func LoadConfig() (*models.Config, error) {
 		cfg.PontoMaisBaseURL = "https://api.pontomais.com.br/external_api/v1"
 	}
 
+	// Quantidade padrão de chats atendidos em paralelo
+	if cfg.TelegramWorkers <= 0 {
+		cfg.TelegramWorkers = 8
+	}
+
+	// Ocorrências do relatório diário, já validadas
+	for _, kind := range digestKinds {
+		cfg.DigestRules = append(cfg.DigestRules, string(kind))
+	}
+
+	// Tolerância padrão do relatório diário
+	if cfg.DigestTolerance <= 0 {
+		cfg.DigestTolerance = timecards.DefaultRules.Tolerance
+	}
+
+	// Chat padrão para o resultado das tarefas agendadas
+	if cfg.ScheduleChat == 0 {
+		cfg.ScheduleChat = cfg.TelegramHosts[0]
+	}
+
+	// Diretório padrão para os dados persistidos pelo bot
+	if cfg.DataDir == "" {
+		cfg.DataDir = "data"
+	}
+
 	return cfg, nil
 }
+
+// durationEnv lê uma variável de ambiente no formato de time.ParseDuration (ex.: "30s").
+// Retorna zero se a variável não estiver definida.
+func durationEnv(name string) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao converter %s: %v", name, err)
+	}
+	return d, nil
+}
+
+// intEnv lê uma variável de ambiente inteira. Retorna zero se a variável não estiver definida.
+func intEnv(name string) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("erro ao converter %s: %v", name, err)
+	}
+	return n, nil
+}
+
+// floatEnv lê uma variável de ambiente decimal. Retorna zero se a variável não estiver definida.
+func floatEnv(name string) (float64, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao converter %s: %v", name, err)
+	}
+	return f, nil
+}
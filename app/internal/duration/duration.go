@@ -0,0 +1,225 @@
+package duration
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MinBareSeconds é o menor número sem unidade aceito. Valores menores quase
+// sempre são horas digitadas sem o "h" (ex.: 2 querendo dizer 2 horas), então
+// são recusados como ambíguos.
+const MinBareSeconds = 60
+
+// Formatos aceitos por Parse
+var (
+	// 7200, 7200.5, 7.200,5
+	bareRe = regexp.MustCompile(`^\d+(?:[.,]\d+)*$`)
+	// 7.200, 12.600: ponto de milhar sem parte decimal, como se escreve em pt-BR
+	thousandsRe = regexp.MustCompile(`^\d{1,3}(?:\.\d{3})+$`)
+	// Relógio com minutos ou segundos fora do intervalo, como 1:90
+	clockOverflowRe = regexp.MustCompile(`^\d+:\d+(?::\d+(?:[.,]\d+)?)?$`)
+	// 02:30, 2:30:15, 26:00 (o Excel exibe durações acima de 24h assim)
+	clockRe = regexp.MustCompile(`^(\d+):([0-5]\d)(?::([0-5]\d)(?:[.,]\d+)?)?$`)
+	// Cada parte de 2h, 2h30, 2h30m, 1h30m15s, 90min, 45s, 2,5h
+	unitPartRe = regexp.MustCompile(`(\d+(?:[.,]\d+)?)([a-z]*)`)
+	// Duração ISO 8601, usada nas células de hora do .ods: PT02H30M00S
+	isoRe = regexp.MustCompile(`^PT(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?$`)
+)
+
+// Parse interpreta uma quantidade de tempo e devolve o total em segundos.
+// Regras:
+//   - número sem unidade: segundos (7200), a partir de MinBareSeconds;
+//     aceita vírgula decimal e ponto de milhar (7.200,5). Um ponto seguido de
+//     exatamente três dígitos, sem vírgula, é de milhar: "7.200" são 7200
+//     segundos, não 7,2
+//   - h, m/min e s: horas, minutos e segundos, combináveis (2h, 2h30m,
+//     2h30, 90min, 45s); "2h30" lê os 30 como minutos. Depois de uma unidade
+//     maior, minutos e segundos precisam ser menores que 60 ("1h90" é recusado)
+//   - HH:MM ou HH:MM:SS: horas e minutos, como nas células de hora do Excel
+//     (02:30); minutos e segundos vão de 00 a 59
+//   - PTnHnMnS: duração ISO 8601, como nas células de hora do .ods
+//
+// O valor precisa ser maior que zero.
+func Parse(value string) (float64, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	s = strings.ReplaceAll(s, " ", "")
+	if s == "" {
+		return 0, fmt.Errorf("quantidade vazia")
+	}
+
+	var seconds float64
+	switch {
+	case bareRe.MatchString(s):
+		number := s
+		if thousandsRe.MatchString(s) {
+			number = strings.ReplaceAll(s, ".", "")
+		}
+		n, err := parseNumber(number)
+		if err != nil {
+			return 0, fmt.Errorf("quantidade inválida '%s'", value)
+		}
+		if n < MinBareSeconds {
+			return 0, fmt.Errorf("quantidade ambígua '%s': números sem unidade são segundos; use %sh para horas, %sm para minutos ou %ss para segundos", value, s, s, s)
+		}
+		seconds = n
+
+	case clockRe.MatchString(s):
+		m := clockRe.FindStringSubmatch(s)
+		h, _ := strconv.Atoi(m[1])
+		min, _ := strconv.Atoi(m[2])
+		sec := 0
+		if m[3] != "" {
+			sec, _ = strconv.Atoi(m[3])
+		}
+		seconds = float64(h*3600 + min*60 + sec)
+
+	case clockOverflowRe.MatchString(s):
+		return 0, fmt.Errorf("quantidade inválida '%s': no formato HH:MM, minutos e segundos vão de 00 a 59", value)
+
+	case isoRe.MatchString(strings.ToUpper(s)):
+		m := isoRe.FindStringSubmatch(strings.ToUpper(s))
+		var err error
+		if seconds, err = sumUnits(m[1], m[2], m[3]); err != nil {
+			return 0, fmt.Errorf("quantidade inválida '%s'", value)
+		}
+
+	default:
+		var err error
+		seconds, err = parseUnits(s)
+		if errors.Is(err, errUnitOverflow) {
+			return 0, fmt.Errorf("quantidade inválida '%s': depois das horas, os minutos vão de 0 a 59 (e depois dos minutos, os segundos); use, por exemplo, 2h30 ou 150min", value)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("quantidade inválida '%s': use, por exemplo, 7200, 2h, 2h30m, 02:30 ou 2,5h", value)
+		}
+	}
+
+	if seconds <= 0 {
+		return 0, fmt.Errorf("a quantidade deve ser positiva ('%s')", value)
+	}
+	return seconds, nil
+}
+
+// unitSeconds associa as unidades aceitas à sua duração em segundos
+var unitSeconds = map[string]float64{
+	"h": 3600, "hr": 3600, "hrs": 3600, "hora": 3600, "horas": 3600,
+	"m": 60, "min": 60, "mins": 60, "minuto": 60, "minutos": 60,
+	"s": 1, "seg": 1, "segundo": 1, "segundos": 1,
+}
+
+// Erros de parseUnits
+var (
+	errUnitFormat   = errors.New("formato inválido")
+	errUnitOverflow = errors.New("parte maior que a unidade anterior")
+)
+
+// parseUnits interpreta partes com unidade, em ordem decrescente (horas,
+// minutos, segundos). Um número sem unidade logo após as horas vale minutos.
+// Cada parte após a primeira precisa ser menor que a unidade anterior.
+func parseUnits(s string) (float64, error) {
+	matches := unitPartRe.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return 0, errUnitFormat
+	}
+
+	var total float64
+	last := math.Inf(1) // Unidade da parte anterior, para exigir ordem decrescente
+	pos := 0
+	for i, m := range matches {
+		if m[0] != pos {
+			return 0, errUnitFormat
+		}
+		pos = m[1]
+
+		number, unit := s[m[2]:m[3]], s[m[4]:m[5]]
+		size, ok := unitSeconds[unit]
+		if unit == "" && i == len(matches)-1 && last == 3600 {
+			size, ok = 60, true
+		}
+		if !ok || size >= last {
+			return 0, errUnitFormat
+		}
+
+		n, err := parseNumber(number)
+		if err != nil {
+			return 0, errUnitFormat
+		}
+		if n*size >= last {
+			return 0, errUnitOverflow
+		}
+		last = size
+		total += n * size
+	}
+	if pos != len(s) {
+		return 0, errUnitFormat
+	}
+	return total, nil
+}
+
+// sumUnits soma horas, minutos e segundos informados como texto
+func sumUnits(hours, minutes, seconds string) (float64, error) {
+	var total float64
+	for _, part := range []struct {
+		value string
+		unit  float64
+	}{{hours, 3600}, {minutes, 60}, {seconds, 1}} {
+		if part.value == "" {
+			continue
+		}
+		n, err := parseNumber(part.value)
+		if err != nil {
+			return 0, err
+		}
+		total += n * part.unit
+	}
+	return total, nil
+}
+
+// parseNumber converte um número escrito com ponto ou vírgula decimal. Com
+// vírgula, os pontos são tratados como separadores de milhar ("1.234,5").
+func parseNumber(value string) (float64, error) {
+	if strings.Contains(value, ",") {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.Replace(value, ",", ".", 1)
+	} else if strings.Count(value, ".") > 1 {
+		value = strings.ReplaceAll(value, ".", "")
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// Format exibe uma quantidade de segundos como HH:MM, acrescentando os
+// segundos quando não for um número inteiro de minutos (ex.: "02:30", "00:00:45")
+func Format(seconds float64) string {
+	sign := ""
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	total := int64(math.Round(seconds))
+	h, m, s := total/3600, total%3600/60, total%60
+	if s != 0 {
+		return fmt.Sprintf("%s%02d:%02d:%02d", sign, h, m, s)
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, h, m)
+}
+
+// FormatSigned exibe a quantidade como Format, sempre com o sinal, como nos
+// saldos: "+02:30", "-01:15". Zero fica sem sinal ("00:00").
+func FormatSigned(seconds float64) string {
+	switch {
+	case math.Round(seconds) == 0:
+		return Format(0)
+	case seconds > 0:
+		return "+" + Format(seconds)
+	}
+	return Format(seconds)
+}
+
+// Describe exibe a quantidade em HH:MM e em segundos, ex.: "02:30 (9000 segundos)"
+func Describe(seconds float64) string {
+	return fmt.Sprintf("%s (%s segundos)", Format(seconds), strconv.FormatFloat(seconds, 'f', -1, 64))
+}
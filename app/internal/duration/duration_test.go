@@ -0,0 +1,94 @@
+package duration
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{"7200", 7200, false},
+		{"7200.5", 7200.5, false},
+		{"7.200,5", 7200.5, false},
+		{"1.234.567", 1234567, false},
+		{"60", 60, false},
+		{"2", 0, true},
+		{"59", 0, true},
+		{"2h", 7200, false},
+		{"2H", 7200, false},
+		{"2 h 30 m", 9000, false},
+		{"2h30", 9000, false},
+		{"2h30m", 9000, false},
+		{"1h30m15s", 5415, false},
+		{"90min", 5400, false},
+		{"45s", 45, false},
+		{"2,5h", 9000, false},
+		{"2.5horas", 9000, false},
+		{"02:30", 9000, false},
+		{"2:30:15", 9015, false},
+		{"26:00", 93600, false},
+		{"2:00:00.5", 7200, false},
+		{"PT02H30M00S", 9000, false},
+		{"pt1h", 3600, false},
+		{"PT0.5H", 1800, false},
+		{"", 0, true},
+		{"abc", 0, true},
+		{"30m2h", 0, true},
+		{"2h2h", 0, true},
+		{"2x", 0, true},
+		{"02:75", 0, true},
+		{"0h", 0, true},
+		{"00:00", 0, true},
+		{"-2h", 0, true},
+		// Minutos e segundos depois de uma unidade maior
+		{"1h90", 0, true},
+		{"1h60m", 0, true},
+		{"1h59", 7140, false},
+		{"2m75s", 0, true},
+		{"1h30m60s", 0, true},
+		{"1h0,5m", 3630, false},
+		{"1:90", 0, true},
+		{"1:30:75", 0, true},
+		{"150min", 9000, false},
+		{"1,5h", 5400, false},
+		// Ponto de milhar sem vírgula
+		{"7.200", 7200, false},
+		{"12.600", 12600, false},
+		{"1.234.567", 1234567, false},
+		{"7200.500", 7200.5, false},
+		{"7.20", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q): erro = %v, esperava erro: %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("Parse(%q) = %v, esperado %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		seconds      float64
+		want, signed string
+	}{
+		{9000, "02:30", "+02:30"},
+		{45, "00:00:45", "+00:00:45"},
+		{93600, "26:00", "+26:00"},
+		{-4500, "-01:15", "-01:15"},
+		{0, "00:00", "00:00"},
+		{0.4, "00:00", "00:00"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.seconds); got != tt.want {
+			t.Errorf("Format(%v) = %q, esperado %q", tt.seconds, got, tt.want)
+		}
+		if got := FormatSigned(tt.seconds); got != tt.signed {
+			t.Errorf("FormatSigned(%v) = %q, esperado %q", tt.seconds, got, tt.signed)
+		}
+	}
+}
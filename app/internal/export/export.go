@@ -0,0 +1,161 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Table é uma tabela simples a ser exportada: uma linha de cabeçalhos seguida
+// das linhas de dados. As células podem ser textos, números ou datas.
+type Table struct {
+	Sheet   string // Nome da aba; "Planilha1" se vazio
+	Headers []string
+	Rows    [][]interface{}
+}
+
+// XLSX gera a tabela como um arquivo Excel, com o cabeçalho em negrito e fixo
+func (t *Table) XLSX() ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := t.Sheet
+	if sheet == "" {
+		sheet = "Planilha1"
+	}
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		return nil, fmt.Errorf("erro ao nomear a aba: %w", err)
+	}
+
+	headers := make([]interface{}, len(t.Headers))
+	for i, h := range t.Headers {
+		headers[i] = h
+	}
+	if err := f.SetSheetRow(sheet, "A1", &headers); err != nil {
+		return nil, fmt.Errorf("erro ao escrever o cabeçalho: %w", err)
+	}
+	for i, row := range t.Rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return nil, fmt.Errorf("erro ao escrever a linha %d: %w", i+2, err)
+		}
+	}
+
+	// Destaca e congela o cabeçalho
+	if len(t.Headers) > 0 {
+		bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+		if err != nil {
+			return nil, err
+		}
+		last, err := excelize.CoordinatesToCellName(len(t.Headers), 1)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.SetCellStyle(sheet, "A1", last, bold); err != nil {
+			return nil, err
+		}
+		if err := f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("erro ao gerar o arquivo Excel: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Formato do CSV gerado: separador e marca de ordem de bytes (BOM) que fazem o
+// Excel em português abrir o arquivo com as colunas e os acentos corretos
+const (
+	csvSeparator = ';'
+	csvBOM       = "\ufeff"
+)
+
+// CSV gera a tabela como texto separado por ponto e vírgula, em UTF-8 com BOM.
+// Números usam vírgula decimal e datas o formato DD/MM/AAAA, como no Excel em
+// português; o arquivo pode ser reenviado ao /relatorio. Textos que o Excel
+// leria como fórmula recebem um apóstrofo na frente (veja csvText).
+func (t *Table) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(csvBOM)
+
+	w := csv.NewWriter(&buf)
+	w.Comma = csvSeparator
+	if err := w.Write(t.Headers); err != nil {
+		return nil, fmt.Errorf("erro ao escrever o cabeçalho: %w", err)
+	}
+	for i, row := range t.Rows {
+		record := make([]string, len(row))
+		for j, cell := range row {
+			record[j] = csvCell(cell)
+		}
+		if err := w.Write(record); err != nil {
+			return nil, fmt.Errorf("erro ao escrever a linha %d: %w", i+2, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("erro ao gerar o arquivo CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode gera a tabela no formato informado: "xlsx" ou "csv"
+func (t *Table) Encode(format string) ([]byte, error) {
+	switch format {
+	case "xlsx":
+		return t.XLSX()
+	case "csv":
+		return t.CSV()
+	}
+	return nil, fmt.Errorf("formato de exportação desconhecido: %q", format)
+}
+
+// csvCell converte uma célula para o texto gravado no CSV
+func csvCell(cell interface{}) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return csvText(v)
+	case float64:
+		return strings.Replace(strconv.FormatFloat(v, 'f', -1, 64), ".", ",", 1)
+	case float32:
+		return strings.Replace(strconv.FormatFloat(float64(v), 'f', -1, 32), ".", ",", 1)
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case time.Time:
+		return v.Format("02/01/2006")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// signedNumberRe reconhece números e durações com sinal, como -1,5 e -01:30,
+// que o Excel não executa como fórmula
+var signedNumberRe = regexp.MustCompile(`^[+-]\d+(?:[.,:]\d+)*$`)
+
+// csvText protege um texto contra injeção de fórmulas: começando com =, +, -
+// ou @ (ou com tabulação e retorno de carro, que o Excel ignora antes deles),
+// o Excel o executaria ao abrir o arquivo, então recebe um apóstrofo na frente
+// e é exibido como texto. Números e durações com sinal ficam como estão.
+func csvText(v string) string {
+	if v == "" || !strings.ContainsRune("=+-@\t\r", rune(v[0])) || signedNumberRe.MatchString(v) {
+		return v
+	}
+	return "'" + v
+}
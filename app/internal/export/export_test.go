@@ -0,0 +1,104 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func testTable() *Table {
+	return &Table{
+		Sheet:   "Lançamentos",
+		Headers: []string{"ID", "NOME", "HORAS", "DATA", "DEBITO", "SALDO"},
+		Rows: [][]interface{}{
+			{101, "Ana Souza", 1.5, time.Date(2025, 3, 18, 0, 0, 0, 0, time.UTC), true, "-01:30"},
+			{102, "=HYPERLINK(\"http://x\")", float32(0.25), nil, false, "+00:15"},
+		},
+	}
+}
+
+func TestEncode(t *testing.T) {
+	table := testTable()
+
+	data, err := table.Encode("csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := csvBOM +
+		"ID;NOME;HORAS;DATA;DEBITO;SALDO\n" +
+		"101;Ana Souza;1,5;18/03/2025;TRUE;-01:30\n" +
+		"102;\"'=HYPERLINK(\"\"http://x\"\")\";0,25;;FALSE;+00:15\n"
+	if string(data) != want {
+		t.Errorf("CSV = %q, esperado %q", data, want)
+	}
+
+	data, err = table.Encode("xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("Lançamentos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || strings.Join(rows[0], ";") != "ID;NOME;HORAS;DATA;DEBITO;SALDO" {
+		t.Fatalf("linhas do Excel = %q", rows)
+	}
+	// No Excel a célula é gravada como texto, sem apóstrofo
+	if rows[2][1] != "=HYPERLINK(\"http://x\")" {
+		t.Errorf("nome da linha 3 = %q", rows[2][1])
+	}
+
+	if _, err := table.Encode("pdf"); err == nil || !strings.Contains(err.Error(), "formato de exportação desconhecido") {
+		t.Errorf("erro para pdf = %v", err)
+	}
+}
+
+func TestXLSXDefaultSheet(t *testing.T) {
+	data, err := (&Table{Headers: []string{"A"}}).XLSX()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if name := f.GetSheetName(0); name != "Planilha1" {
+		t.Errorf("aba = %q, esperado Planilha1", name)
+	}
+}
+
+func TestCSVText(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Ana Souza", "Ana Souza"},
+		{"", ""},
+		{"=1+1", "'=1+1"},
+		{"+55 11 9999", "'+55 11 9999"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"\t=1+1", "'\t=1+1"},
+		{"\r=1+1", "'\r=1+1"},
+		{"-", "'-"},
+		{"-01:30", "-01:30"},
+		{"+00:15", "+00:15"},
+		{"-1,5", "-1,5"},
+		{"-10", "-10"},
+		{"a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		if got := csvText(tt.value); got != tt.want {
+			t.Errorf("csvText(%q) = %q, esperado %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// RefKind é o dado usado pela planilha para identificar o colaborador
+type RefKind string
+
+const (
+	RefID           RefKind = "id"
+	RefCPF          RefKind = "cpf"
+	RefEmail        RefKind = "email"
+	RefRegistration RefKind = "matricula"
+)
+
+// refColumns lista, em ordem de preferência, as colunas que identificam o colaborador
+var refColumns = []struct {
+	Column string
+	Kind   RefKind
+}{
+	{ColumnID, RefID},
+	{ColumnCPF, RefCPF},
+	{ColumnEmail, RefEmail},
+	{ColumnRegistration, RefRegistration},
+}
+
+// EmployeeRef identifica o colaborador de uma linha antes da resolução do ID
+type EmployeeRef struct {
+	Kind  RefKind
+	Value string // Valor normalizado (CPF só com dígitos, e-mail em minúsculas)
+}
+
+// String descreve a referência nas mensagens, ex.: "CPF 12345678900"
+func (r EmployeeRef) String() string {
+	switch r.Kind {
+	case RefID:
+		return "ID " + r.Value
+	case RefCPF:
+		return "CPF " + r.Value
+	case RefEmail:
+		return "e-mail " + r.Value
+	default:
+		return "matrícula " + r.Value
+	}
+}
+
+// key é a forma usada na chave de idempotência. Para IDs é o próprio ID, o
+// que mantém as chaves já registradas no ledger.
+func (r EmployeeRef) key() string {
+	if r.Kind == RefID {
+		return r.Value
+	}
+	return string(r.Kind) + ":" + r.Value
+}
+
+// normalizeRef padroniza o valor conforme o tipo de referência
+func normalizeRef(kind RefKind, value string) string {
+	value = strings.TrimSpace(value)
+	switch kind {
+	case RefCPF:
+		return digitsOnly(value)
+	case RefEmail:
+		return strings.ToLower(value)
+	case RefRegistration:
+		return strings.ToUpper(value)
+	default:
+		return value
+	}
+}
+
+// digitsOnly remove tudo o que não for dígito
+func digitsOnly(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+// EmployeeIndex localiza colaboradores por ID, CPF, e-mail ou matrícula
+type EmployeeIndex struct {
+	byRef map[EmployeeRef]models.Employee
+}
+
+// NewEmployeeIndex indexa os colaboradores por todos os tipos de referência
+func NewEmployeeIndex(employees []models.Employee) *EmployeeIndex {
+	idx := &EmployeeIndex{byRef: make(map[EmployeeRef]models.Employee)}
+	for _, employee := range employees {
+		values := map[RefKind]string{
+			RefID:           strconv.Itoa(employee.ID),
+			RefCPF:          employee.CPF,
+			RefEmail:        employee.Email,
+			RefRegistration: employee.RegistrationNumber,
+		}
+		for kind, value := range values {
+			if value = normalizeRef(kind, value); value != "" {
+				idx.byRef[EmployeeRef{Kind: kind, Value: value}] = employee
+			}
+		}
+	}
+	return idx
+}
+
+// Lookup busca o colaborador da referência
+func (idx *EmployeeIndex) Lookup(ref EmployeeRef) (models.Employee, bool) {
+	employee, ok := idx.byRef[ref]
+	return employee, ok
+}
+
+// ResolveEmployees preenche o ID do colaborador das linhas válidas e devolve as
+// referências não encontradas, sem repetição. Com idx nil (colaboradores
+// indisponíveis), IDs são aceitos sem conferência e as demais referências
+// invalidam a linha.
+func (s *Sheet) ResolveEmployees(idx *EmployeeIndex) []EmployeeRef {
+	seen := make(map[EmployeeRef]bool)
+	var unknown []EmployeeRef
+	for i := range s.Rows {
+		row := &s.Rows[i]
+		if !row.Valid() {
+			continue
+		}
+
+		if idx == nil {
+			if row.Ref.Kind == RefID {
+				row.EmployeeID = row.Ref.Value
+			} else {
+				row.Err = fmt.Errorf("não foi possível localizar o colaborador pelo %s: a lista de colaboradores está indisponível", row.Ref)
+			}
+			continue
+		}
+
+		employee, ok := idx.Lookup(row.Ref)
+		if !ok {
+			row.Err = fmt.Errorf("colaborador com %s não encontrado entre os colaboradores ativos", row.Ref)
+			if !seen[row.Ref] {
+				seen[row.Ref] = true
+				unknown = append(unknown, row.Ref)
+			}
+			continue
+		}
+		row.EmployeeID = strconv.Itoa(employee.ID)
+		if row.EmployeeName == "" {
+			row.EmployeeName = strings.TrimSpace(employee.FirstName + " " + employee.LastName)
+		}
+	}
+	return unknown
+}
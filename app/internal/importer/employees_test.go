@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+func TestResolveEmployees(t *testing.T) {
+	employees := []models.Employee{
+		{ID: 101, FirstName: "Ana", LastName: "Souza", CPF: "123.456.789-00", Email: "Ana.Souza@Empresa.com", RegistrationNumber: "a-17"},
+		{ID: 102, FirstName: "Bia", LastName: "Lima", CPF: "98765432100", Email: "bia@empresa.com", RegistrationNumber: "B-20"},
+	}
+
+	tests := []struct {
+		name     string
+		header   string
+		values   []string // Referência de cada linha
+		nilIndex bool     // Colaboradores indisponíveis
+		wantIDs  []string // ID resolvido de cada linha; vazio se a linha ficar inválida
+		wantRefs []EmployeeRef
+	}{
+		{
+			name:    "por ID",
+			header:  "ID",
+			values:  []string{"101", "102"},
+			wantIDs: []string{"101", "102"},
+		},
+		{
+			name:    "por CPF com ou sem pontuação",
+			header:  "CPF",
+			values:  []string{"12345678900", "987.654.321-00", " 123.456.789-00 "},
+			wantIDs: []string{"101", "102", "101"},
+		},
+		{
+			name:    "por e-mail sem diferenciar caixa",
+			header:  "E-mail",
+			values:  []string{"ana.souza@empresa.com", "BIA@EMPRESA.COM"},
+			wantIDs: []string{"101", "102"},
+		},
+		{
+			name:    "por matrícula sem diferenciar caixa",
+			header:  "Matrícula",
+			values:  []string{"A-17", "b-20"},
+			wantIDs: []string{"101", "102"},
+		},
+		{
+			name:     "referências desconhecidas listadas uma vez",
+			header:   "CPF",
+			values:   []string{"111.111.111-11", "12345678900", "11111111111", "22222222222"},
+			wantIDs:  []string{"", "101", "", ""},
+			wantRefs: []EmployeeRef{{Kind: RefCPF, Value: "11111111111"}, {Kind: RefCPF, Value: "22222222222"}},
+		},
+		{
+			name:     "ID desconhecido",
+			header:   "ID",
+			values:   []string{"999"},
+			wantIDs:  []string{""},
+			wantRefs: []EmployeeRef{{Kind: RefID, Value: "999"}},
+		},
+		{
+			name:     "sem a lista, IDs aceitos sem conferência",
+			header:   "ID",
+			values:   []string{"999"},
+			nilIndex: true,
+			wantIDs:  []string{"999"},
+		},
+		{
+			name:     "sem a lista, demais referências invalidam a linha",
+			header:   "EMAIL",
+			values:   []string{"ana.souza@empresa.com"},
+			nilIndex: true,
+			wantIDs:  []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := [][]string{{tt.header, "DATA", "HORAS", "OBSERVAÇÃO", "DEBITO"}}
+			for _, value := range tt.values {
+				rows = append(rows, []string{value, "18/03/2025", "3600", "Plantão", "false"})
+			}
+			sheet, err := ParseRows(rows, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var idx *EmployeeIndex
+			if !tt.nilIndex {
+				idx = NewEmployeeIndex(employees)
+			}
+			unknown := sheet.ResolveEmployees(idx)
+			if !reflect.DeepEqual(unknown, tt.wantRefs) {
+				t.Errorf("referências desconhecidas = %v, esperado %v", unknown, tt.wantRefs)
+			}
+			for i, row := range sheet.Rows {
+				if row.EmployeeID != tt.wantIDs[i] {
+					t.Errorf("linha %d: ID = %q, esperado %q", row.Line, row.EmployeeID, tt.wantIDs[i])
+				}
+				if valid := tt.wantIDs[i] != ""; row.Valid() != valid {
+					t.Errorf("linha %d: válida = %t, esperado %t (%v)", row.Line, row.Valid(), valid, row.Err)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveEmployeesKeepsSheetName(t *testing.T) {
+	sheet, err := ParseRows([][]string{
+		{"CPF", "NOME", "DATA", "HORAS", "OBSERVAÇÃO", "DEBITO"},
+		{"12345678900", "ANA S.", "18/03/2025", "3600", "Plantão", "false"},
+		{"98765432100", "", "18/03/2025", "3600", "Plantão", "false"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet.ResolveEmployees(NewEmployeeIndex([]models.Employee{
+		{ID: 101, FirstName: "Ana", LastName: "Souza", CPF: "12345678900"},
+		{ID: 102, FirstName: "Bia", LastName: "Lima", CPF: "98765432100"},
+	}))
+
+	// O nome da planilha é mantido; sem ele, usa o nome do cadastro
+	if got := sheet.Rows[0].EmployeeName; got != "ANA S." {
+		t.Errorf("nome da linha 2 = %q, esperado %q", got, "ANA S.")
+	}
+	if got := sheet.Rows[1].EmployeeName; got != "Bia Lima" {
+		t.Errorf("nome da linha 3 = %q, esperado %q", got, "Bia Lima")
+	}
+}
@@ -0,0 +1,589 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Format é o formato de arquivo de uma planilha de lançamentos
+type Format string
+
+const (
+	FormatXLSX Format = "xlsx"
+	FormatODS  Format = "ods"
+	FormatCSV  Format = "csv"
+)
+
+// ErrUnsupportedFormat indica que o arquivo enviado não é uma planilha suportada
+var ErrUnsupportedFormat = errors.New("formato de arquivo não suportado; envie .xlsx, .ods ou .csv")
+
+// Assinaturas usadas para reconhecer o formato pelo conteúdo
+var (
+	zipMagic = []byte("PK\x03\x04")
+	oleMagic = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1} // .xls antigo (binário)
+)
+
+// mimeFormats associa os tipos MIME informados pelo Telegram aos formatos
+var mimeFormats = map[string]Format{
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": FormatXLSX,
+	"application/vnd.oasis.opendocument.spreadsheet":                    FormatODS,
+	"text/csv":                    FormatCSV,
+	"text/comma-separated-values": FormatCSV,
+	"text/plain":                  FormatCSV,
+	"application/csv":             FormatCSV,
+}
+
+// extFormats associa as extensões de arquivo aos formatos
+var extFormats = map[string]Format{
+	".xlsx": FormatXLSX,
+	".ods":  FormatODS,
+	".csv":  FormatCSV,
+	".txt":  FormatCSV,
+}
+
+// DetectFormat identifica o formato do arquivo em path. O conteúdo (assinatura
+// do arquivo) tem prioridade; o nome original e o tipo MIME, quando
+// conhecidos, só decidem entre formatos que o conteúdo não distingue.
+func DetectFormat(path, name, mimeType string) (Format, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("erro ao abrir o arquivo: %w", err)
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("erro ao ler o arquivo: %w", err)
+	}
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, oleMagic):
+		return "", fmt.Errorf("arquivos .xls antigos não são suportados; salve como .xlsx, .ods ou .csv")
+	case bytes.HasPrefix(head, zipMagic):
+		// .xlsx e .ods são arquivos zip; o .ods declara o tipo no arquivo "mimetype"
+		if isODS(path) {
+			return FormatODS, nil
+		}
+		return FormatXLSX, nil
+	}
+
+	// Sem assinatura binária: um .xlsx ou .ods declarado está corrompido; os
+	// demais arquivos são aceitos como CSV se o nome ou o MIME indicarem CSV,
+	// ou se o conteúdo parecer texto
+	if declared, ok := declaredFormat(name, mimeType); ok {
+		if declared != FormatCSV {
+			return "", fmt.Errorf("o arquivo não é um .%s válido", declared)
+		}
+		return FormatCSV, nil
+	}
+	if len(head) > 0 && !bytes.ContainsRune(head, 0) {
+		return FormatCSV, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// declaredFormat deduz o formato pela extensão do nome ou, na falta dela, pelo tipo MIME
+func declaredFormat(name, mimeType string) (Format, bool) {
+	if format, ok := extFormats[strings.ToLower(filepath.Ext(name))]; ok {
+		return format, true
+	}
+	mimeType = strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+	format, ok := mimeFormats[mimeType]
+	return format, ok
+}
+
+// isODS indica se o arquivo zip em path é uma planilha OpenDocument
+func isODS(path string) bool {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return false
+	}
+	defer r.Close()
+
+	for _, file := range r.File {
+		if file.Name != "mimetype" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return false
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(io.LimitReader(rc, 128))
+		return err == nil && strings.TrimSpace(string(data)) == "application/vnd.oasis.opendocument.spreadsheet"
+	}
+	return false
+}
+
+// readRows lê as linhas da aba escolhida do arquivo no formato informado. O
+// CSV tem uma única aba e ignora a seleção.
+func readRows(path string, format Format, sheet string) ([][]string, error) {
+	switch format {
+	case FormatXLSX:
+		return readXLSX(path, sheet)
+	case FormatODS:
+		return readODS(path, sheet)
+	case FormatCSV:
+		return readCSV(path)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// readXLSX lê as linhas de uma aba de um arquivo Excel. As células de data,
+// hora e duração são lidas pelo valor bruto, como no .ods, pois o texto
+// exibido depende do formato (AM/PM, [h]:mm, mm-dd-yy) e nem sempre é aceito
+// pelas validações.
+func readXLSX(path, sheet string) ([][]string, error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir o arquivo Excel: %w", err)
+	}
+	defer f.Close()
+
+	names := f.GetSheetList()
+	index, err := selectSheet(names, sheet)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := f.GetRows(names[index])
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler as linhas da planilha: %w", err)
+	}
+	raw, err := f.GetRows(names[index], excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler as linhas da planilha: %w", err)
+	}
+
+	kinds := make(map[int]cellKind) // Tipo de cada estilo, já consultado
+	for r := range rows {
+		if r >= len(raw) {
+			break
+		}
+		for c := range rows[r] {
+			if c >= len(raw[r]) {
+				break
+			}
+			serial, err := strconv.ParseFloat(raw[r][c], 64)
+			if err != nil {
+				continue
+			}
+			cell, err := excelize.CoordinatesToCellName(c+1, r+1)
+			if err != nil {
+				continue
+			}
+			styleID, err := f.GetCellStyle(names[index], cell)
+			if err != nil {
+				continue
+			}
+			kind, ok := kinds[styleID]
+			if !ok {
+				kind = styleKind(f, styleID)
+				kinds[styleID] = kind
+			}
+			if value, ok := excelSerialValue(serial, kind); ok {
+				rows[r][c] = value
+			}
+		}
+	}
+	return rows, nil
+}
+
+// cellKind classifica o formato numérico de uma célula do Excel
+type cellKind int
+
+const (
+	kindNumber  cellKind = iota // Número ou texto: usa o valor exibido
+	kindDate                    // Data, com ou sem hora
+	kindClock                   // Hora do dia (hh:mm, h:mm AM/PM)
+	kindElapsed                 // Duração ([h]:mm, mm:ss)
+)
+
+// Formatos internos do Excel para hora do dia e duração
+var (
+	builtinClockFormats   = map[int]bool{18: true, 19: true, 20: true, 21: true}
+	builtinElapsedFormats = map[int]bool{45: true, 46: true, 47: true}
+	builtinDateFormats    = map[int]bool{14: true, 15: true, 16: true, 17: true, 22: true}
+)
+
+// styleKind classifica o formato numérico do estilo informado
+func styleKind(f *excelize.File, styleID int) cellKind {
+	style, err := f.GetStyle(styleID)
+	if err != nil || style == nil {
+		return kindNumber
+	}
+	switch {
+	case style.CustomNumFmt != nil:
+		return formatCodeKind(*style.CustomNumFmt)
+	case builtinClockFormats[style.NumFmt]:
+		return kindClock
+	case builtinElapsedFormats[style.NumFmt]:
+		return kindElapsed
+	case builtinDateFormats[style.NumFmt]:
+		return kindDate
+	default:
+		return kindNumber
+	}
+}
+
+// formatCodeKind classifica um código de formato personalizado, como
+// "dd/mm/yyyy", "[h]:mm" ou "hh:mm AM/PM". Só a primeira seção (até o ";")
+// conta; textos entre aspas, caracteres escapados e marcadores de idioma e
+// cor são ignorados.
+func formatCodeKind(code string) cellKind {
+	var tokens strings.Builder
+	elapsed := false
+	for i := 0; i < len(code); i++ {
+		switch ch := code[i]; ch {
+		case ';':
+			i = len(code)
+		case '"':
+			if end := strings.IndexByte(code[i+1:], '"'); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(code)
+			}
+		case '\\', '_', '*':
+			i++
+		case '[':
+			end := strings.IndexByte(code[i:], ']')
+			if end < 0 {
+				i = len(code)
+				break
+			}
+			switch strings.ToLower(code[i+1 : i+end]) {
+			case "h", "hh", "m", "mm", "s", "ss":
+				elapsed = true
+			}
+			i += end
+		default:
+			tokens.WriteByte(ch)
+		}
+	}
+	lower := strings.ToLower(tokens.String())
+	switch {
+	case strings.ContainsAny(lower, "yd"):
+		return kindDate
+	case elapsed:
+		return kindElapsed
+	case strings.ContainsAny(lower, "hs"):
+		return kindClock
+	default:
+		return kindNumber
+	}
+}
+
+// excelEpoch é o dia zero das datas do Excel (sistema 1900, já descontado o
+// falso 29/02/1900)
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// excelSerialValue converte o número de dias de uma célula de data, hora ou
+// duração para um texto aceito pelas validações: AAAA-MM-DD para datas e
+// H:MM:SS para horas e durações. Na hora do dia vale só a fração do dia,
+// como o Excel exibe; na duração vale o total, mesmo acima de 24h.
+func excelSerialValue(serial float64, kind cellKind) (string, bool) {
+	if serial < 0 || math.IsNaN(serial) || math.IsInf(serial, 0) {
+		return "", false
+	}
+	switch kind {
+	case kindDate:
+		days := math.Floor(serial)
+		return excelEpoch.AddDate(0, 0, int(days)).Format("2006-01-02"), true
+	case kindClock:
+		serial -= math.Floor(serial)
+	case kindElapsed:
+	default:
+		return "", false
+	}
+	seconds := int64(math.Round(serial * 86400))
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60), true
+}
+
+// readCSV lê um arquivo CSV. O separador (ponto e vírgula, vírgula ou
+// tabulação) é deduzido do cabeçalho; arquivos que não são UTF-8 válido são
+// tratados como Latin-1, o padrão dos sistemas de folha de pagamento.
+func readCSV(path string) ([][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o arquivo CSV: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+	if !utf8.Valid(data) {
+		data = latin1ToUTF8(data)
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = csvDelimiter(data)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o arquivo CSV: %w", err)
+	}
+	return rows, nil
+}
+
+// csvDelimiter escolhe o separador mais frequente na primeira linha
+func csvDelimiter(data []byte) rune {
+	header := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		header = data[:i]
+	}
+	best, bestCount := ';', 0
+	for _, sep := range []rune{';', ',', '\t'} {
+		if count := bytes.Count(header, []byte(string(sep))); count > bestCount {
+			best, bestCount = sep, count
+		}
+	}
+	return best
+}
+
+// latin1ToUTF8 converte texto ISO-8859-1 para UTF-8
+func latin1ToUTF8(data []byte) []byte {
+	buf := make([]byte, 0, len(data)+len(data)/4)
+	for _, b := range data {
+		buf = utf8.AppendRune(buf, rune(b))
+	}
+	return buf
+}
+
+// Limite de repetições de linhas e colunas vazias do .ods, que costuma declarar
+// milhares delas para preencher a planilha até o fim
+const odsMaxRepeat = 1000
+
+// readODS lê as linhas de uma aba de um arquivo OpenDocument
+func readODS(path, sheet string) ([][]string, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir o arquivo ODS: %w", err)
+	}
+	defer r.Close()
+
+	var content *zip.File
+	for _, file := range r.File {
+		if file.Name == "content.xml" {
+			content = file
+			break
+		}
+	}
+	if content == nil {
+		return nil, fmt.Errorf("erro ao abrir o arquivo ODS: content.xml não encontrado")
+	}
+	rc, err := content.Open()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir o arquivo ODS: %w", err)
+	}
+	defer rc.Close()
+
+	tables, err := parseODSContent(rc)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler as linhas da planilha ODS: %w", err)
+	}
+
+	names := make([]string, len(tables))
+	for i, table := range tables {
+		names[i] = table.name
+	}
+	index, err := selectSheet(names, sheet)
+	if err != nil {
+		return nil, err
+	}
+	return tables[index].rows, nil
+}
+
+// Elementos e atributos do content.xml usados na leitura
+const (
+	odsTableNS  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odsOfficeNS = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odsTextNS   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+)
+
+// odsTable é uma aba de um arquivo OpenDocument
+type odsTable struct {
+	name string
+	rows [][]string
+}
+
+// parseODSContent percorre o content.xml e devolve as abas com suas linhas
+func parseODSContent(r io.Reader) ([]odsTable, error) {
+	decoder := xml.NewDecoder(r)
+
+	var (
+		tables     []odsTable
+		name       string
+		rows       [][]string
+		row        []string
+		rowRepeat  int
+		cell       strings.Builder
+		cellValue  string
+		cellRepeat int
+		inCell     bool
+		paragraphs int
+		tableDepth int
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == odsTableNS && t.Name.Local == "table":
+				tableDepth++
+				if tableDepth == 1 {
+					name = odsAttr(t, "name")
+					rows = nil
+				}
+			case tableDepth != 1:
+				// Ignora as tabelas aninhadas
+			case t.Name.Space == odsTableNS && t.Name.Local == "table-row":
+				row = nil
+				rowRepeat = odsRepeat(t, "number-rows-repeated")
+			case t.Name.Space == odsTableNS && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell"):
+				inCell = true
+				cell.Reset()
+				paragraphs = 0
+				cellRepeat = odsRepeat(t, "number-columns-repeated")
+				cellValue = odsCellValue(t)
+			case inCell && t.Name.Space == odsTextNS && t.Name.Local == "p":
+				if paragraphs > 0 {
+					cell.WriteString("\n")
+				}
+				paragraphs++
+			case inCell && t.Name.Space == odsTextNS && t.Name.Local == "s":
+				cell.WriteString(strings.Repeat(" ", odsRepeat(t, "c")))
+			}
+		case xml.CharData:
+			if inCell && tableDepth == 1 {
+				cell.Write(t)
+			}
+		case xml.EndElement:
+			switch {
+			case t.Name.Space == odsTableNS && t.Name.Local == "table":
+				tableDepth--
+				if tableDepth == 0 {
+					tables = append(tables, odsTable{name: name, rows: trimODSRows(rows)})
+				}
+			case tableDepth != 1:
+			case t.Name.Space == odsTableNS && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell"):
+				inCell = false
+				value := cellValue
+				if value == "" {
+					value = cell.String()
+				}
+				for i := 0; i < cellRepeat; i++ {
+					row = append(row, value)
+				}
+			case t.Name.Space == odsTableNS && t.Name.Local == "table-row":
+				row = trimTrailingEmpty(row)
+				for i := 0; i < rowRepeat; i++ {
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+	return tables, nil
+}
+
+// odsAttr lê um atributo do elemento pelo nome local
+func odsAttr(el xml.StartElement, name string) string {
+	for _, attr := range el.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// odsRepeat lê um atributo de repetição, limitado a odsMaxRepeat
+func odsRepeat(el xml.StartElement, name string) int {
+	for _, attr := range el.Attr {
+		if attr.Name.Local != name {
+			continue
+		}
+		n, err := strconv.Atoi(attr.Value)
+		if err != nil || n < 1 {
+			return 1
+		}
+		if n > odsMaxRepeat {
+			return odsMaxRepeat
+		}
+		return n
+	}
+	return 1
+}
+
+// odsCellValue devolve o valor bruto de células numéricas, de data e de hora, que não
+// dependem da formatação exibida. Para os demais tipos devolve vazio e o texto
+// da célula é usado.
+func odsCellValue(el xml.StartElement) string {
+	var valueType, value, dateValue, timeValue string
+	for _, attr := range el.Attr {
+		if attr.Name.Space != odsOfficeNS {
+			continue
+		}
+		switch attr.Name.Local {
+		case "value-type":
+			valueType = attr.Value
+		case "value":
+			value = attr.Value
+		case "date-value":
+			dateValue = attr.Value
+		case "time-value":
+			timeValue = attr.Value
+		}
+	}
+	switch valueType {
+	case "float", "percentage", "currency":
+		return value
+	case "date":
+		// Mantém só a data (AAAA-MM-DD)
+		if len(dateValue) >= 10 {
+			return dateValue[:10]
+		}
+		return dateValue
+	case "time":
+		// Duração ISO 8601 (PT02H30M00S), aceita pelo duration.Parse
+		return timeValue
+	default:
+		return ""
+	}
+}
+
+// trimTrailingEmpty remove as células vazias do fim da linha
+func trimTrailingEmpty(row []string) []string {
+	for len(row) > 0 && strings.TrimSpace(row[len(row)-1]) == "" {
+		row = row[:len(row)-1]
+	}
+	return row
+}
+
+// trimODSRows remove as linhas vazias do fim da planilha
+func trimODSRows(rows [][]string) [][]string {
+	for len(rows) > 0 && len(rows[len(rows)-1]) == 0 {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
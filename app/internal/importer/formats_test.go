@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestFormatCodeKind(t *testing.T) {
+	tests := []struct {
+		code string
+		want cellKind
+	}{
+		{"dd/mm/yyyy", kindDate},
+		{"[$-416]d-mmm-yy;@", kindDate},
+		{"dd/mm/yyyy hh:mm", kindDate},
+		{"hh:mm", kindClock},
+		{"h:mm AM/PM", kindClock},
+		{"[Red]hh:mm:ss", kindClock},
+		{"[h]:mm", kindElapsed},
+		{"[hh]:mm:ss", kindElapsed},
+		{"[mm]:ss", kindElapsed},
+		{"0.00", kindNumber},
+		{"#,##0.00\" horas\"", kindNumber},
+		{"0\\h", kindNumber},
+		{"@", kindNumber},
+		{"0;[h]:mm", kindNumber},
+	}
+	for _, tt := range tests {
+		if got := formatCodeKind(tt.code); got != tt.want {
+			t.Errorf("formatCodeKind(%q) = %d, esperado %d", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestExcelSerialValue(t *testing.T) {
+	tests := []struct {
+		serial float64
+		kind   cellKind
+		want   string
+		ok     bool
+	}{
+		{0.0833333333, kindClock, "2:00:00", true},
+		{0.6041666667, kindClock, "14:30:00", true},
+		{45734.0833333333, kindClock, "2:00:00", true},
+		{1.0833333333, kindElapsed, "26:00:00", true},
+		{0.0006944444, kindElapsed, "0:01:00", true},
+		{45734, kindDate, "2025-03-18", true},
+		{45734.75, kindDate, "2025-03-18", true},
+		{7200, kindNumber, "", false},
+		{-1, kindElapsed, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := excelSerialValue(tt.serial, tt.kind)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("excelSerialValue(%v, %d) = %q, %v; esperado %q, %v", tt.serial, tt.kind, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestReadXLSXRawValues(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := f.GetSheetName(0)
+
+	styles := map[string]*excelize.Style{
+		"B2": {NumFmt: 14},
+		"C2": {NumFmt: 18},
+		"C3": {CustomNumFmt: stringPtr("[h]:mm")},
+		"C4": {},
+	}
+	values := map[string]interface{}{
+		"A1": "ID", "B1": "DATA", "C1": "HORAS",
+		"A2": 1487972, "B2": 45734, "C2": 0.0833333333,
+		"A3": 1487972, "B3": "18/03/2025", "C3": 1.0833333333,
+		"A4": 1487972, "B4": "18/03/2025", "C4": 7200,
+	}
+	for cell, value := range values {
+		if err := f.SetCellValue(sheet, cell, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for cell, style := range styles {
+		id, err := f.NewStyle(style)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.SetCellStyle(sheet, cell, cell, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "planilha.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := readXLSX(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"ID", "DATA", "HORAS"},
+		{"1487972", "2025-03-18", "2:00:00"},
+		{"1487972", "18/03/2025", "26:00:00"},
+		{"1487972", "18/03/2025", "7200"},
+	}
+	for r := range want {
+		for c := range want[r] {
+			if rows[r][c] != want[r][c] {
+				t.Errorf("linha %d, coluna %d = %q, esperado %q", r+1, c+1, rows[r][c], want[r][c])
+			}
+		}
+	}
+}
+
+func stringPtr(s string) *string { return &s }
@@ -0,0 +1,378 @@
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// Nomes das colunas da planilha de lançamentos. Os cabeçalhos aceitos para
+// cada uma estão no Mapping.
+const (
+	ColumnID           = "ID"
+	ColumnCPF          = "CPF"
+	ColumnEmail        = "EMAIL"
+	ColumnRegistration = "MATRICULA"
+	ColumnName         = "NOME"
+	ColumnDate         = "DATA"
+	ColumnHours        = "HORAS"
+	ColumnObservation  = "OBSERVAÇÃO"
+	ColumnDebit        = "DEBITO"
+)
+
+// RequiredColumns lista as colunas que a planilha precisa ter, além de uma
+// coluna que identifique o colaborador (ID, CPF, EMAIL ou MATRICULA)
+var RequiredColumns = []string{ColumnDate, ColumnHours, ColumnObservation, ColumnDebit}
+
+// Options controla a leitura de um arquivo de lançamentos
+type Options struct {
+	Sheet   string  // Nome ou posição (a partir de 1) da aba; vazio usa a primeira
+	Mapping Mapping // Apelidos dos cabeçalhos; nil usa DefaultMapping
+}
+
+// Row é uma linha da planilha já interpretada. Se Err não for nil a linha é
+// inválida e não deve ser lançada.
+type Row struct {
+	Line         int         // Número da linha na planilha (o cabeçalho é a linha 1)
+	Ref          EmployeeRef // Como a planilha identifica o colaborador
+	EmployeeID   string      // ID no Ponto Mais, preenchido por ResolveEmployees
+	EmployeeName string
+	Date         time.Time
+	Seconds      float64
+	Observation  string
+	Withdraw     bool
+	Values       []string // Valores originais da linha
+	Key          string   // Chave de idempotência da linha (ver IdempotencyKey)
+	Applied      bool     // Linha já lançada anteriormente, segundo o Ledger
+	RepeatOf     int      // Linha anterior do arquivo com os mesmos dados; zero se não houver
+	Err          error
+}
+
+// Valid indica se a linha pode ser lançada
+func (r Row) Valid() bool {
+	return r.Err == nil
+}
+
+// IdempotencyKey calcula a chave que identifica a linha de forma única: um hash
+// do colaborador, data, quantidade, observação, retirada e do checksum do arquivo.
+// Reenviar o mesmo arquivo gera as mesmas chaves.
+func (r Row) IdempotencyKey(fileChecksum string) string {
+	data := fmt.Sprintf("%s|%s|%.3f|%s|%t|%s",
+		r.Ref.key(), r.Date.Format("2006-01-02"), r.Seconds, r.Observation, r.Withdraw, fileChecksum)
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// Label identifica a linha nas mensagens, ex.: "Linha 3 (FULANO)"
+func (r Row) Label() string {
+	switch {
+	case r.EmployeeName != "":
+		return fmt.Sprintf("Linha %d (%s)", r.Line, r.EmployeeName)
+	case r.Ref.Value != "":
+		return fmt.Sprintf("Linha %d (%s)", r.Line, r.Ref)
+	default:
+		return fmt.Sprintf("Linha %d", r.Line)
+	}
+}
+
+// Entry converte a linha no lançamento enviado à API
+func (r Row) Entry() models.TimeBalanceEntry {
+	return models.TimeBalanceEntry{
+		Amount:      r.Seconds,
+		Date:        r.Date.Format("02/01/2006"),
+		EmployeeID:  r.EmployeeID,
+		Observation: r.Observation,
+		Withdraw:    r.Withdraw,
+	}
+}
+
+// Sheet é o conteúdo interpretado de uma planilha de lançamentos
+type Sheet struct {
+	Headers  []string
+	Rows     []Row
+	Checksum string // SHA-256 do arquivo de origem
+}
+
+// PendingRows devolve as linhas válidas que ainda não foram lançadas
+func (s *Sheet) PendingRows() []Row {
+	var rows []Row
+	for _, row := range s.Rows {
+		if row.Valid() && !row.Applied {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// MarkApplied marca as linhas já lançadas segundo o ledger
+func (s *Sheet) MarkApplied(ledger *Ledger) {
+	for i := range s.Rows {
+		row := &s.Rows[i]
+		if row.Valid() && ledger.Applied(row.Key) {
+			row.Applied = true
+		}
+	}
+}
+
+// ParseFile lê e valida uma aba de um arquivo .xlsx, .ods ou .csv e calcula as
+// chaves de idempotência das linhas. O formato é detectado pelo conteúdo do
+// arquivo (ver DetectFormat). Os colaboradores ainda precisam ser resolvidos
+// com ResolveEmployees.
+func ParseFile(path string, opts Options) (*Sheet, error) {
+	format, err := DetectFormat(path, path, "")
+	if err != nil {
+		return nil, err
+	}
+
+	checksum, err := fileChecksum(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := readRows(path, format, opts.Sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet, err := ParseRows(rows, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	sheet.assignKeys(checksum)
+	return sheet, nil
+}
+
+// fileChecksum calcula o SHA-256 do arquivo
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("erro ao abrir o arquivo: %w", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("erro ao ler o arquivo: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// assignKeys calcula a chave de idempotência de cada linha válida. Linhas com
+// os mesmos dados de uma linha anterior do arquivo (ex.: dois créditos iguais
+// no mesmo dia) são lançadas normalmente, com uma chave que inclui a ocorrência,
+// e apontadas em RepeatOf para o aviso no resumo.
+func (s *Sheet) assignKeys(checksum string) {
+	s.Checksum = checksum
+	firstLine := make(map[string]int)
+	occurrences := make(map[string]int)
+	for i := range s.Rows {
+		row := &s.Rows[i]
+		if !row.Valid() {
+			continue
+		}
+		key := row.IdempotencyKey(checksum)
+		occurrences[key]++
+		row.Key = key
+		if line, ok := firstLine[key]; ok {
+			row.RepeatOf = line
+			row.Key = occurrenceKey(key, occurrences[key])
+			continue
+		}
+		firstLine[key] = row.Line
+	}
+}
+
+// occurrenceKey deriva a chave da n-ésima linha com os mesmos dados. A primeira
+// ocorrência mantém a chave de IdempotencyKey, então reenvios continuam idempotentes.
+func occurrenceKey(key string, n int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", key, n)))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseRows valida o cabeçalho e interpreta cada linha de dados. Erros de uma
+// linha ficam em Row.Err; apenas problemas na planilha como um todo (falta de
+// dados ou de colunas) são retornados como erro.
+func ParseRows(rows [][]string, mapping Mapping) (*Sheet, error) {
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("o arquivo não contém dados suficientes")
+	}
+	if mapping == nil {
+		mapping = DefaultMapping()
+	}
+
+	// Identifica os índices das colunas com base nos cabeçalhos
+	headers := rows[0]
+	headerMap := mapping.resolve(headers)
+
+	// Verifica se todas as colunas necessárias estão presentes
+	var missingHeaders []string
+	hasRef := false
+	for _, ref := range refColumns {
+		if _, exists := headerMap[ref.Column]; exists {
+			hasRef = true
+		}
+	}
+	if !hasRef {
+		missingHeaders = append(missingHeaders, "ID, CPF, EMAIL ou MATRICULA")
+	}
+	for _, header := range RequiredColumns {
+		if _, exists := headerMap[header]; !exists {
+			missingHeaders = append(missingHeaders, header)
+		}
+	}
+	if len(missingHeaders) > 0 {
+		return nil, fmt.Errorf("colunas obrigatórias não encontradas: %s", strings.Join(missingHeaders, ", "))
+	}
+
+	sheet := &Sheet{Headers: headers}
+	for i, values := range rows[1:] {
+		// Ignora linhas totalmente vazias, comuns no fim das planilhas
+		if isBlank(values) {
+			continue
+		}
+		sheet.Rows = append(sheet.Rows, parseRow(i+2, values, headerMap, len(headers)))
+	}
+
+	if len(sheet.Rows) == 0 {
+		return nil, fmt.Errorf("o arquivo não contém dados suficientes")
+	}
+	return sheet, nil
+}
+
+// parseRow interpreta uma linha de dados
+func parseRow(line int, values []string, headerMap map[string]int, columns int) Row {
+	// Completa as células vazias do fim da linha, que o Excel não grava. As
+	// colunas obrigatórias vazias são rejeitadas nas validações abaixo.
+	if len(values) < columns {
+		padded := make([]string, columns)
+		copy(padded, values)
+		values = padded
+	}
+	row := Row{Line: line, Values: values}
+
+	// Extrai os dados da linha usando os índices do mapa de cabeçalhos
+	cell := func(column string) string {
+		if i, ok := headerMap[column]; ok {
+			return strings.TrimSpace(values[i])
+		}
+		return ""
+	}
+	row.EmployeeName = cell(ColumnName)
+	dateStr := cell(ColumnDate)
+	secondsStr := cell(ColumnHours)
+	row.Observation = cell(ColumnObservation)
+	debitStr := strings.ToLower(cell(ColumnDebit))
+
+	// Usa a primeira coluna de identificação preenchida na linha
+	for _, ref := range refColumns {
+		if value := normalizeRef(ref.Kind, cell(ref.Column)); value != "" {
+			row.Ref = EmployeeRef{Kind: ref.Kind, Value: value}
+			break
+		}
+	}
+	if row.Ref.Value == "" {
+		row.Err = fmt.Errorf("colaborador não identificado (ID, CPF, e-mail ou matrícula vazio)")
+		return row
+	}
+
+	// Converte a data, aceitando DD/MM/AAAA ou AAAA-MM-DD
+	date, err := time.Parse("02/01/2006", dateStr)
+	if err != nil {
+		date, err = time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			row.Err = fmt.Errorf("formato de data inválido '%s'", dateStr)
+			return row
+		}
+	}
+	row.Date = date
+
+	// Converte a quantidade: segundos, 2h30m, 02:30 etc. (ver duration.Parse)
+	seconds, err := duration.Parse(secondsStr)
+	if err != nil {
+		row.Err = err
+		return row
+	}
+	row.Seconds = seconds
+
+	// Determina se é uma retirada
+	row.Withdraw = debitStr == "true" || debitStr == "sim" || debitStr == "s"
+	return row
+}
+
+// isBlank indica se todos os valores da linha estão vazios
+func isBlank(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// EmployeeTotals soma os lançamentos válidos de um colaborador
+type EmployeeTotals struct {
+	EmployeeID string
+	Name       string
+	Rows       int
+	Credited   float64 // Segundos creditados
+	Debited    float64 // Segundos debitados (retiradas)
+}
+
+// Summary resume uma planilha antes do lançamento
+type Summary struct {
+	TotalRows      int
+	ValidRows      int // Linhas válidas que serão lançadas
+	AlreadyApplied int // Linhas válidas ignoradas por já terem sido lançadas
+	Invalid        []Row
+	Repeated       []Row            // Linhas a lançar com os mesmos dados de uma linha anterior
+	Employees      []EmployeeTotals // Totais por colaborador, ordenados por nome
+}
+
+// Summarize calcula o resumo da planilha
+func (s *Sheet) Summarize() Summary {
+	summary := Summary{TotalRows: len(s.Rows)}
+	totals := make(map[string]*EmployeeTotals)
+
+	for _, row := range s.Rows {
+		if !row.Valid() {
+			summary.Invalid = append(summary.Invalid, row)
+			continue
+		}
+		if row.Applied {
+			summary.AlreadyApplied++
+			continue
+		}
+		summary.ValidRows++
+		if row.RepeatOf != 0 {
+			summary.Repeated = append(summary.Repeated, row)
+		}
+
+		t, ok := totals[row.EmployeeID]
+		if !ok {
+			t = &EmployeeTotals{EmployeeID: row.EmployeeID, Name: row.EmployeeName}
+			totals[row.EmployeeID] = t
+		}
+		t.Rows++
+		if row.Withdraw {
+			t.Debited += row.Seconds
+		} else {
+			t.Credited += row.Seconds
+		}
+	}
+
+	for _, t := range totals {
+		summary.Employees = append(summary.Employees, *t)
+	}
+	sort.Slice(summary.Employees, func(i, j int) bool {
+		return summary.Employees[i].Name < summary.Employees[j].Name
+	})
+	return summary
+}
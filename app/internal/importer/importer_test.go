@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	base := Row{
+		Ref:         EmployeeRef{Kind: RefID, Value: "1487972"},
+		Date:        time.Date(2025, 3, 18, 0, 0, 0, 0, time.UTC),
+		Seconds:     7200,
+		Observation: "Horas extras",
+	}
+	key := base.IdempotencyKey("abc")
+	if key != base.IdempotencyKey("abc") {
+		t.Fatal("a chave não é determinística")
+	}
+
+	tests := []struct {
+		name     string
+		change   func(r *Row)
+		checksum string
+	}{
+		{"outro colaborador", func(r *Row) { r.Ref.Value = "1" }, "abc"},
+		{"outra data", func(r *Row) { r.Date = r.Date.AddDate(0, 0, 1) }, "abc"},
+		{"outra quantidade", func(r *Row) { r.Seconds = 3600 }, "abc"},
+		{"outra observação", func(r *Row) { r.Observation = "Ajuste" }, "abc"},
+		{"retirada", func(r *Row) { r.Withdraw = true }, "abc"},
+		{"outro arquivo", func(r *Row) {}, "def"},
+	}
+	for _, tt := range tests {
+		row := base
+		tt.change(&row)
+		if row.IdempotencyKey(tt.checksum) == key {
+			t.Errorf("%s: a chave não mudou", tt.name)
+		}
+	}
+
+	// A linha e o nome não fazem parte da chave
+	row := base
+	row.Line = 10
+	row.EmployeeName = "FULANO"
+	if row.IdempotencyKey("abc") != key {
+		t.Error("a linha e o nome não deveriam alterar a chave")
+	}
+}
+
+func TestAssignKeysRepeatedRows(t *testing.T) {
+	sheet, err := ParseRows([][]string{
+		{"ID", "NOME", "DATA", "HORAS", "OBSERVAÇÃO", "DEBITO"},
+		{"1487972", "FULANO", "18/03/2025", "7200", "Horas extras", "false"},
+		{"1487972", "FULANO", "18/03/2025", "7200", "Horas extras", "false"},
+		{"1487972", "FULANO", "19/03/2025", "7200", "Horas extras", "false"},
+		{"1487972", "FULANO", "18/03/2025", "7200", "Horas extras", "false"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet.assignKeys("abc")
+
+	keys := make(map[string]int)
+	for _, row := range sheet.Rows {
+		if !row.Valid() {
+			t.Fatalf("linha %d inválida: %v", row.Line, row.Err)
+		}
+		if line, dup := keys[row.Key]; dup {
+			t.Errorf("linhas %d e %d com a mesma chave", line, row.Line)
+		}
+		keys[row.Key] = row.Line
+	}
+
+	wantRepeat := map[int]int{2: 0, 3: 2, 4: 0, 5: 2}
+	for _, row := range sheet.Rows {
+		if row.RepeatOf != wantRepeat[row.Line] {
+			t.Errorf("linha %d: RepeatOf = %d, esperado %d", row.Line, row.RepeatOf, wantRepeat[row.Line])
+		}
+	}
+	if first := sheet.Rows[0]; first.Key != first.IdempotencyKey("abc") {
+		t.Error("a primeira ocorrência deveria manter a chave de IdempotencyKey")
+	}
+
+	// Reprocessar o mesmo arquivo gera as mesmas chaves
+	again, _ := ParseRows([][]string{
+		{"ID", "NOME", "DATA", "HORAS", "OBSERVAÇÃO", "DEBITO"},
+		{"1487972", "FULANO", "18/03/2025", "7200", "Horas extras", "false"},
+		{"1487972", "FULANO", "18/03/2025", "7200", "Horas extras", "false"},
+	}, nil)
+	again.assignKeys("abc")
+	if again.Rows[1].Key != sheet.Rows[1].Key {
+		t.Error("a chave da linha repetida mudou entre dois processamentos")
+	}
+
+	summary := sheet.Summarize()
+	if summary.ValidRows != 4 || len(summary.Repeated) != 2 || len(summary.Invalid) != 0 {
+		t.Errorf("resumo = %d válidas, %d repetidas, %d inválidas", summary.ValidRows, len(summary.Repeated), len(summary.Invalid))
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	invalid := errors.New("data inválida")
+	ana := func(line int, seconds float64, withdraw bool) Row {
+		return Row{Line: line, EmployeeID: "1", EmployeeName: "ANA", Seconds: seconds, Withdraw: withdraw}
+	}
+	bia := func(line int, seconds float64, withdraw bool) Row {
+		return Row{Line: line, EmployeeID: "2", EmployeeName: "BIA", Seconds: seconds, Withdraw: withdraw}
+	}
+	with := func(row Row, change func(r *Row)) Row {
+		change(&row)
+		return row
+	}
+
+	tests := []struct {
+		name      string
+		rows      []Row
+		valid     int
+		invalid   int
+		applied   int
+		repeated  int
+		employees []EmployeeTotals
+	}{
+		{"planilha vazia", nil, 0, 0, 0, 0, nil},
+		{
+			name:  "todas válidas",
+			rows:  []Row{bia(2, 3600, false), ana(3, 7200, false), ana(4, 1800, true)},
+			valid: 3,
+			employees: []EmployeeTotals{
+				{EmployeeID: "1", Name: "ANA", Rows: 2, Credited: 7200, Debited: 1800},
+				{EmployeeID: "2", Name: "BIA", Rows: 1, Credited: 3600},
+			},
+		},
+		{
+			name:      "inválidas ficam fora dos totais",
+			rows:      []Row{ana(2, 3600, false), with(bia(3, 3600, false), func(r *Row) { r.Err = invalid })},
+			valid:     1,
+			invalid:   1,
+			employees: []EmployeeTotals{{EmployeeID: "1", Name: "ANA", Rows: 1, Credited: 3600}},
+		},
+		{
+			name:      "já lançadas",
+			rows:      []Row{with(ana(2, 3600, false), func(r *Row) { r.Applied = true }), bia(3, 600, true)},
+			valid:     1,
+			applied:   1,
+			employees: []EmployeeTotals{{EmployeeID: "2", Name: "BIA", Rows: 1, Debited: 600}},
+		},
+		{
+			name:      "repetidas continuam válidas",
+			rows:      []Row{ana(2, 3600, false), with(ana(3, 3600, false), func(r *Row) { r.RepeatOf = 2 })},
+			valid:     2,
+			repeated:  1,
+			employees: []EmployeeTotals{{EmployeeID: "1", Name: "ANA", Rows: 2, Credited: 7200}},
+		},
+		{
+			name: "repetida já lançada conta só como lançada",
+			rows: []Row{
+				with(ana(2, 3600, false), func(r *Row) { r.Applied = true }),
+				with(ana(3, 3600, false), func(r *Row) { r.Applied, r.RepeatOf = true, 2 }),
+			},
+			applied: 2,
+		},
+		{
+			name: "inválida repetida conta só como inválida",
+			rows: []Row{
+				with(ana(2, 3600, false), func(r *Row) { r.Err = invalid }),
+				with(ana(3, 3600, false), func(r *Row) { r.Err, r.RepeatOf = invalid, 2 }),
+			},
+			invalid: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet := &Sheet{Rows: tt.rows}
+			summary := sheet.Summarize()
+			if summary.TotalRows != len(tt.rows) {
+				t.Errorf("TotalRows = %d, esperado %d", summary.TotalRows, len(tt.rows))
+			}
+			if summary.ValidRows != tt.valid || len(summary.Invalid) != tt.invalid || summary.AlreadyApplied != tt.applied || len(summary.Repeated) != tt.repeated {
+				t.Errorf("resumo = %d válidas, %d inválidas, %d já lançadas, %d repetidas; esperado %d, %d, %d, %d",
+					summary.ValidRows, len(summary.Invalid), summary.AlreadyApplied, len(summary.Repeated),
+					tt.valid, tt.invalid, tt.applied, tt.repeated)
+			}
+			if !reflect.DeepEqual(summary.Employees, tt.employees) {
+				t.Errorf("totais = %+v, esperado %+v", summary.Employees, tt.employees)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Estados de uma chave no ledger
+const (
+	LedgerStarted   = "iniciado"   // A requisição foi enviada, mas a confirmação não foi gravada
+	LedgerApplied   = "lancado"    // O lançamento foi criado no Ponto Mais
+	LedgerDiscarded = "descartado" // O envio falhou; a linha pode ser enviada novamente
+)
+
+// ErrInProgress indica que a linha está sendo lançada por outra importação em
+// andamento neste processo
+var ErrInProgress = errors.New("a linha está sendo lançada por outra importação em andamento")
+
+// LedgerRecord é uma linha do ledger de importações
+type LedgerRecord struct {
+	Key       string    `json:"key"`
+	State     string    `json:"state"`
+	EntryID   int       `json:"entry_id,omitempty"`
+	Line      int       `json:"line"`
+	Checksum  string    `json:"checksum"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Ledger guarda as chaves de idempotência das linhas já lançadas, para que
+// reenviar a mesma planilha (ou retomar uma importação interrompida) não crie
+// lançamentos duplicados. Os registros são acrescentados a um arquivo JSON Lines
+// e sincronizados em disco a cada gravação. É seguro para uso concorrente: cada
+// chave é reservada com Claim pela importação que vai lançá-la.
+type Ledger struct {
+	path    string
+	mu      sync.Mutex
+	records map[string]LedgerRecord
+	claimed map[string]bool // Chaves reservadas por uma importação em andamento
+	started map[string]bool // Chaves iniciadas neste processo, ainda sem Commit ou Discard
+}
+
+// OpenLedger abre (ou cria) o ledger no caminho informado
+func OpenLedger(path string) (*Ledger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("erro ao criar o diretório de dados: %w", err)
+	}
+
+	l := &Ledger{
+		path:    path,
+		records: make(map[string]LedgerRecord),
+		claimed: make(map[string]bool),
+		started: make(map[string]bool),
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir o ledger de importações: %w", err)
+	}
+	defer f.Close()
+
+	// O último registro de cada chave prevalece
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record LedgerRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// Uma linha truncada por queda do processo é ignorada
+			continue
+		}
+		l.index(record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler o ledger de importações: %w", err)
+	}
+	return l, nil
+}
+
+// Lookup devolve o registro de uma chave, se existir
+func (l *Ledger) Lookup(key string) (LedgerRecord, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	record, ok := l.records[key]
+	return record, ok
+}
+
+// Claim reserva a chave para a importação que vai lançá-la, até Release, e
+// devolve o registro atual, se houver. Devolve ErrInProgress se outra
+// importação deste processo já reservou a chave ou tem um envio iniciado com
+// ela; assim, um registro LedgerStarted devolvido por Claim sobrou de uma
+// execução anterior do bot ou de um envio deixado sem resposta (ver Abandon).
+func (l *Ledger) Claim(key string) (LedgerRecord, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.claimed[key] || l.started[key] {
+		return LedgerRecord{}, false, ErrInProgress
+	}
+	l.claimed[key] = true
+	record, ok := l.records[key]
+	return record, ok, nil
+}
+
+// Release libera a chave reservada por Claim
+func (l *Ledger) Release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.claimed, key)
+}
+
+// HasEntry indica se o lançamento informado já foi registrado para alguma chave
+func (l *Ledger) HasEntry(entryID int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, record := range l.records {
+		if record.State == LedgerApplied && record.EntryID == entryID {
+			return true
+		}
+	}
+	return false
+}
+
+// Applied indica se a linha com a chave informada já foi lançada
+func (l *Ledger) Applied(key string) bool {
+	record, ok := l.Lookup(key)
+	return ok && record.State == LedgerApplied
+}
+
+// Start registra que a linha está prestes a ser enviada à API. Se o processo cair
+// antes de Commit, a chave fica no estado LedgerStarted e precisa ser conferida
+// no Ponto Mais antes de uma nova tentativa. Devolve ErrInProgress se a chave
+// já foi iniciada neste processo e ainda não foi confirmada nem descartada.
+func (l *Ledger) Start(row Row, checksum string) error {
+	return l.append(LedgerRecord{Key: row.Key, State: LedgerStarted, Line: row.Line, Checksum: checksum})
+}
+
+// Commit registra que a linha foi lançada com o ID informado
+func (l *Ledger) Commit(row Row, checksum string, entryID int) error {
+	return l.append(LedgerRecord{Key: row.Key, State: LedgerApplied, EntryID: entryID, Line: row.Line, Checksum: checksum})
+}
+
+// Discard remove o registro de início de uma linha cujo envio falhou
+func (l *Ledger) Discard(row Row, checksum string) error {
+	return l.append(LedgerRecord{Key: row.Key, State: LedgerDiscarded, Line: row.Line, Checksum: checksum})
+}
+
+// Abandon encerra um envio iniciado neste processo sem confirmação nem descarte,
+// quando não se sabe se o lançamento chegou a ser criado. A chave continua no
+// estado LedgerStarted e a próxima reserva a trata como sobra de um envio
+// interrompido, conferindo o Ponto Mais antes de reenviar.
+func (l *Ledger) Abandon(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.started, key)
+}
+
+// append grava um registro no fim do arquivo e atualiza o índice em memória
+func (l *Ledger) append(record LedgerRecord) error {
+	record.Timestamp = time.Now()
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar o registro do ledger: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if record.State == LedgerStarted && l.started[record.Key] {
+		return ErrInProgress
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir o ledger de importações: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("erro ao gravar o ledger de importações: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("erro ao gravar o ledger de importações: %w", err)
+	}
+
+	l.index(record)
+	if record.State == LedgerStarted {
+		l.started[record.Key] = true
+	} else {
+		delete(l.started, record.Key)
+	}
+	return nil
+}
+
+// index atualiza o índice em memória com um registro
+func (l *Ledger) index(record LedgerRecord) {
+	if record.State == LedgerDiscarded {
+		delete(l.records, record.Key)
+		return
+	}
+	l.records[record.Key] = record
+}
@@ -0,0 +1,111 @@
+package importer
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestLedgerLifecycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	ledger, err := OpenLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied := Row{Line: 2, Key: "a"}
+	discarded := Row{Line: 3, Key: "b"}
+	interrupted := Row{Line: 4, Key: "c"}
+
+	for _, row := range []Row{applied, discarded, interrupted} {
+		if err := ledger.Start(row, "sum"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ledger.Commit(applied, "sum", 42); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.Discard(discarded, "sum"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reabrir o ledger simula um reinício do bot
+	reopened, err := OpenLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key   string
+		found bool
+		state string
+	}{
+		{"a", true, LedgerApplied},
+		{"b", false, ""},
+		{"c", true, LedgerStarted},
+		{"d", false, ""},
+	}
+	for _, tt := range tests {
+		record, found, err := reopened.Claim(tt.key)
+		if err != nil {
+			t.Fatalf("Claim(%s): %v", tt.key, err)
+		}
+		if found != tt.found || record.State != tt.state {
+			t.Errorf("Claim(%s) = %q, %v; esperado %q, %v", tt.key, record.State, found, tt.state, tt.found)
+		}
+		reopened.Release(tt.key)
+	}
+	if !reopened.Applied("a") || reopened.Applied("c") {
+		t.Error("Applied não corresponde aos registros")
+	}
+	if !reopened.HasEntry(42) || reopened.HasEntry(7) {
+		t.Error("HasEntry não corresponde aos registros")
+	}
+
+	// O Started de uma execução anterior pode ser retomado após um reinício
+	if err := reopened.Start(interrupted, "sum"); err != nil {
+		t.Errorf("Start de uma chave iniciada em outra execução: %v", err)
+	}
+}
+
+func TestLedgerClaimInProcess(t *testing.T) {
+	ledger, err := OpenLedger(filepath.Join(t.TempDir(), "ledger.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := Row{Line: 2, Key: "a"}
+
+	if _, _, err := ledger.Claim("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ledger.Claim("a"); !errors.Is(err, ErrInProgress) {
+		t.Fatalf("segunda reserva = %v, esperado ErrInProgress", err)
+	}
+	if err := ledger.Start(row, "sum"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.Start(row, "sum"); !errors.Is(err, ErrInProgress) {
+		t.Fatalf("segundo Start = %v, esperado ErrInProgress", err)
+	}
+
+	// Mesmo liberada, a chave iniciada neste processo não é reservada de novo
+	// até a confirmação, para não ser tratada como sobra de outra execução
+	ledger.Release("a")
+	if _, _, err := ledger.Claim("a"); !errors.Is(err, ErrInProgress) {
+		t.Fatalf("reserva de chave iniciada = %v, esperado ErrInProgress", err)
+	}
+
+	// Um envio sem resposta libera a chave, que volta como LedgerStarted
+	ledger.Abandon("a")
+	record, found, err := ledger.Claim("a")
+	if err != nil || !found || record.State != LedgerStarted {
+		t.Fatalf("após o Abandon: %+v, %v, %v", record, found, err)
+	}
+
+	if err := ledger.Commit(row, "sum", 1); err != nil {
+		t.Fatal(err)
+	}
+	ledger.Release("a")
+	record, found, err = ledger.Claim("a")
+	if err != nil || !found || record.State != LedgerApplied {
+		t.Errorf("após o Commit: %+v, %v, %v", record, found, err)
+	}
+}
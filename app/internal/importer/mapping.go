@@ -0,0 +1,201 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Mapping associa cada coluna da planilha (ver as constantes Column*) aos
+// nomes de cabeçalho aceitos para ela. A comparação ignora acentos, caixa,
+// espaços e pontuação: "Observação", "OBSERVACAO" e "observacao " são iguais.
+type Mapping map[string][]string
+
+// columnOrder é a ordem fixa em que as colunas do mapeamento são conferidas
+var columnOrder = []string{
+	ColumnID, ColumnCPF, ColumnEmail, ColumnRegistration, ColumnName,
+	ColumnDate, ColumnHours, ColumnObservation, ColumnDebit,
+}
+
+// DefaultMapping devolve os apelidos reconhecidos sem configuração adicional
+func DefaultMapping() Mapping {
+	return Mapping{
+		ColumnID:           {"ID", "ID_FUNCIONARIO", "ID_COLABORADOR"},
+		ColumnCPF:          {"CPF"},
+		ColumnEmail:        {"EMAIL", "E-MAIL"},
+		ColumnRegistration: {"MATRICULA", "REGISTRO", "REGISTRATION_NUMBER"},
+		ColumnName:         {"NOME", "COLABORADOR", "FUNCIONARIO"},
+		ColumnDate:         {"DATA", "DIA"},
+		ColumnHours:        {"HORAS", "QUANTIDADE", "SEGUNDOS"},
+		ColumnObservation:  {"OBSERVAÇÃO", "OBS", "MOTIVO", "DESCRIÇÃO"},
+		ColumnDebit:        {"DEBITO", "RETIRADA"},
+	}
+}
+
+// LoadMapping lê apelidos adicionais de um arquivo JSON no formato
+// {"HORAS": ["TEMPO", "BANCO"], ...} e os acrescenta aos padrões. Sem path,
+// devolve apenas os padrões. Um apelido que apareça em mais de uma coluna
+// (inclusive nos padrões) é rejeitado.
+func LoadMapping(path string) (Mapping, error) {
+	mapping := DefaultMapping()
+	if path == "" {
+		return mapping, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o mapeamento de colunas: %w", err)
+	}
+	var extra map[string][]string
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar o mapeamento de colunas: %w", err)
+	}
+
+	// As chaves do arquivo também são normalizadas, então "OBSERVACAO" vale para "OBSERVAÇÃO"
+	columns := make(map[string]string, len(mapping))
+	for column := range mapping {
+		columns[NormalizeHeader(column)] = column
+	}
+	for key, aliases := range extra {
+		column, ok := columns[NormalizeHeader(key)]
+		if !ok {
+			return nil, fmt.Errorf("coluna desconhecida no mapeamento de colunas: %s", key)
+		}
+		mapping[column] = append(mapping[column], aliases...)
+	}
+	if err := mapping.validate(); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// columns devolve as colunas do mapeamento na ordem de columnOrder, seguidas
+// das demais em ordem alfabética
+func (m Mapping) columns() []string {
+	known := make(map[string]bool, len(columnOrder))
+	var columns []string
+	for _, column := range columnOrder {
+		known[column] = true
+		if _, ok := m[column]; ok {
+			columns = append(columns, column)
+		}
+	}
+	var others []string
+	for column := range m {
+		if !known[column] {
+			others = append(others, column)
+		}
+	}
+	sort.Strings(others)
+	return append(columns, others...)
+}
+
+// validate confere que nenhum apelido (nem o nome de uma coluna) corresponde a
+// mais de uma coluna
+func (m Mapping) validate() error {
+	owners := make(map[string]string)
+	for _, column := range m.columns() {
+		for _, name := range append([]string{column}, m[column]...) {
+			alias := NormalizeHeader(name)
+			if alias == "" {
+				return fmt.Errorf("apelido vazio na coluna %s do mapeamento de colunas", column)
+			}
+			if owner, ok := owners[alias]; ok && owner != column {
+				return fmt.Errorf("o apelido %q aparece nas colunas %s e %s do mapeamento de colunas", name, owner, column)
+			}
+			owners[alias] = column
+		}
+	}
+	return nil
+}
+
+// resolve localiza as colunas do mapeamento nos cabeçalhos da planilha. Se
+// mais de um cabeçalho corresponder a uma coluna, vale o primeiro; se um
+// apelido corresponder a mais de uma coluna, vale a primeira de columnOrder.
+func (m Mapping) resolve(headers []string) map[string]int {
+	aliases := make(map[string]string)
+	for _, column := range m.columns() {
+		for _, name := range append([]string{column}, m[column]...) {
+			if alias := NormalizeHeader(name); aliases[alias] == "" {
+				aliases[alias] = column
+			}
+		}
+	}
+
+	indexes := make(map[string]int)
+	for i, header := range headers {
+		column, ok := aliases[NormalizeHeader(header)]
+		if !ok {
+			continue
+		}
+		if _, seen := indexes[column]; !seen {
+			indexes[column] = i
+		}
+	}
+	return indexes
+}
+
+// NormalizeHeader remove acentos, espaços e pontuação e converte para
+// maiúsculas, para comparar cabeçalhos escritos de formas diferentes
+func NormalizeHeader(header string) string {
+	var b strings.Builder
+	for _, r := range header {
+		r = unaccent(r)
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// accents mapeia as letras acentuadas do português (e vizinhas) para a letra base
+var accents = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n',
+}
+
+// unaccent devolve a letra sem acento
+func unaccent(r rune) rune {
+	if base, ok := accents[unicode.ToLower(r)]; ok {
+		if unicode.IsUpper(r) {
+			return unicode.ToUpper(base)
+		}
+		return base
+	}
+	return r
+}
+
+// ErrSheetNotFound indica que a aba pedida não existe no arquivo
+var ErrSheetNotFound = errors.New("aba não encontrada")
+
+// selectSheet escolhe a aba pelo nome (sem diferenciar acentos e caixa) ou
+// pela posição, começando em 1. Sem seleção, usa a primeira aba.
+func selectSheet(names []string, selection string) (int, error) {
+	selection = strings.TrimSpace(selection)
+	if selection == "" {
+		if len(names) == 0 {
+			return 0, ErrSheetNotFound
+		}
+		return 0, nil
+	}
+
+	wanted := NormalizeHeader(selection)
+	for i, name := range names {
+		if NormalizeHeader(name) == wanted {
+			return i, nil
+		}
+	}
+	if position, err := strconv.Atoi(selection); err == nil && position >= 1 && position <= len(names) {
+		return position - 1, nil
+	}
+	return 0, fmt.Errorf("%w: %q (abas disponíveis: %s)", ErrSheetNotFound, selection, strings.Join(names, ", "))
+}
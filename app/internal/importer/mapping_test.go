@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeHeader(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"Observação", "OBSERVACAO"},
+		{"OBSERVAÇÃO", "OBSERVACAO"},
+		{" observacao ", "OBSERVACAO"},
+		{"E-mail", "EMAIL"},
+		{"Matrícula", "MATRICULA"},
+		{"ID_Funcionário", "IDFUNCIONARIO"},
+		{"Débito (S/N)", "DEBITOSN"},
+		{"Horas 2025", "HORAS2025"},
+		{"Ñandú", "NANDU"},
+		{"", ""},
+		{"---", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeHeader(tt.header); got != tt.want {
+			t.Errorf("NormalizeHeader(%q) = %q, esperado %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestUnaccent(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want rune
+	}{
+		{'á', 'a'}, {'Á', 'A'}, {'ã', 'a'}, {'Ã', 'A'}, {'ç', 'c'}, {'Ç', 'C'},
+		{'ê', 'e'}, {'Ô', 'O'}, {'ü', 'u'}, {'a', 'a'}, {'Z', 'Z'}, {'1', '1'}, {'ß', 'ß'},
+	}
+	for _, tt := range tests {
+		if got := unaccent(tt.r); got != tt.want {
+			t.Errorf("unaccent(%q) = %q, esperado %q", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestLoadMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string // Conteúdo do arquivo; vazio usa apenas os padrões
+		want    map[string][]string
+		wantErr string
+	}{
+		{name: "sem arquivo"},
+		{
+			name:    "apelidos adicionais",
+			content: `{"HORAS": ["TEMPO", "BANCO"], "observacao": ["NOTA"]}`,
+			want:    map[string][]string{ColumnHours: {"TEMPO", "BANCO"}, ColumnObservation: {"NOTA"}},
+		},
+		{
+			name:    "apelido repetido na mesma coluna",
+			content: `{"HORAS": ["QUANTIDADE", "Tempo", "TEMPO"]}`,
+			want:    map[string][]string{ColumnHours: {"QUANTIDADE", "Tempo", "TEMPO"}},
+		},
+		{name: "coluna desconhecida", content: `{"SALARIO": ["VALOR"]}`, wantErr: "coluna desconhecida"},
+		{name: "JSON inválido", content: `{"HORAS": "TEMPO"}`, wantErr: "erro ao interpretar"},
+		{name: "apelido de outra coluna", content: `{"HORAS": ["Dia"]}`, wantErr: "aparece nas colunas DATA e HORAS"},
+		{name: "nome de outra coluna", content: `{"NOME": ["Matrícula"]}`, wantErr: "aparece nas colunas MATRICULA e NOME"},
+		{name: "mesmo apelido em duas colunas do arquivo", content: `{"HORAS": ["VALOR"], "DEBITO": ["valor"]}`, wantErr: "aparece nas colunas HORAS e DEBITO"},
+		{name: "apelido vazio", content: `{"HORAS": ["--"]}`, wantErr: "apelido vazio"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			if tt.content != "" {
+				path = filepath.Join(t.TempDir(), "colunas.json")
+				if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			mapping, err := LoadMapping(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("erro = %v, esperado %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := DefaultMapping()
+			for column, aliases := range tt.want {
+				want[column] = append(want[column], aliases...)
+			}
+			if !reflect.DeepEqual(mapping, want) {
+				t.Errorf("mapeamento = %v, esperado %v", mapping, want)
+			}
+		})
+	}
+
+	if _, err := LoadMapping(filepath.Join(t.TempDir(), "inexistente.json")); err == nil {
+		t.Error("arquivo inexistente aceito, esperado erro")
+	}
+}
+
+func TestMappingResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping Mapping
+		headers []string
+		want    map[string]int
+	}{
+		{
+			name:    "apelidos com acentos, caixa e pontuação",
+			mapping: DefaultMapping(),
+			headers: []string{"Id_Colaborador", "Dia", "quantidade", "Descrição", "Retirada", "Colaborador"},
+			want:    map[string]int{ColumnID: 0, ColumnDate: 1, ColumnHours: 2, ColumnObservation: 3, ColumnDebit: 4, ColumnName: 5},
+		},
+		{
+			name:    "e-mail e matrícula",
+			mapping: DefaultMapping(),
+			headers: []string{"E-mail", "Matrícula", "cpf"},
+			want:    map[string]int{ColumnEmail: 0, ColumnRegistration: 1, ColumnCPF: 2},
+		},
+		{
+			name:    "primeiro cabeçalho da coluna vale",
+			mapping: DefaultMapping(),
+			headers: []string{"HORAS", "SEGUNDOS", "OBS", "MOTIVO"},
+			want:    map[string]int{ColumnHours: 0, ColumnObservation: 2},
+		},
+		{
+			name:    "cabeçalhos desconhecidos ignorados",
+			mapping: DefaultMapping(),
+			headers: []string{"SETOR", "", "DATA"},
+			want:    map[string]int{ColumnDate: 2},
+		},
+		{
+			// LoadMapping rejeita esse mapeamento, mas um job antigo pode trazê-lo
+			name:    "apelido em duas colunas vale a primeira da ordem fixa",
+			mapping: Mapping{ColumnDebit: {"X"}, ColumnName: {"X"}, ColumnID: {"X"}},
+			headers: []string{"X"},
+			want:    map[string]int{ColumnID: 0},
+		},
+	}
+	for _, tt := range tests {
+		// A resolução não pode depender da ordem de iteração do mapa
+		for i := 0; i < 20; i++ {
+			if got := tt.mapping.resolve(tt.headers); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("%s: resolve(%q) = %v, esperado %v", tt.name, tt.headers, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/store"
+)
+
+// State é a situação de um job de importação
+type State string
+
+const (
+	StateAwaitingConfirmation State = "aguardando_confirmacao"
+	StateRunning              State = "executando"
+	StateDone                 State = "concluido"
+	StateCancelled            State = "cancelado"
+	StateFailed               State = "falhou"
+)
+
+// Finished indica se o job não será mais processado
+func (s State) Finished() bool {
+	return s == StateDone || s == StateCancelled || s == StateFailed
+}
+
+// RowStatus é a situação de uma linha do job
+type RowStatus string
+
+const (
+	RowPending        RowStatus = "pendente"
+	RowCreated        RowStatus = "lancado"
+	RowAlreadyApplied RowStatus = "ja_lancado"
+	RowFailed         RowStatus = "erro"
+	RowInvalid        RowStatus = "invalida"
+)
+
+// Row guarda o progresso de uma linha da planilha
+type Row struct {
+	Line       int       `json:"line"`        // Número da linha na planilha
+	EmployeeID string    `json:"employee_id"` // Colaborador resolvido na validação
+	Status     RowStatus `json:"status"`
+	EntryID    int       `json:"entry_id,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Job é uma importação de planilha persistida em disco, que pode ser retomada
+// após um reinício do bot
+type Job struct {
+	ID              int                 `json:"id"`
+	ChatID          int64               `json:"chat_id"`
+	User            string              `json:"user"`
+	FileName        string              `json:"file_name"`         // Nome original do arquivo enviado
+	FilePath        string              `json:"file_path"`         // Cópia do arquivo no diretório de dados
+	Sheet           string              `json:"sheet,omitempty"`   // Aba escolhida no /relatorio
+	Mapping         map[string][]string `json:"mapping,omitempty"` // Apelidos de colunas da validação, reusados na execução; vazio em jobs antigos
+	Checksum        string              `json:"checksum"`
+	State           State               `json:"state"`
+	CancelRequested bool                `json:"cancel_requested,omitempty"`
+	Cursor          int                 `json:"cursor"` // Índice da próxima linha a processar
+	Rows            []Row               `json:"rows"`
+	Error           string              `json:"error,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+	FinishedAt      *time.Time          `json:"finished_at,omitempty"`
+}
+
+// Counts soma as linhas do job por situação
+func (j *Job) Counts() map[RowStatus]int {
+	counts := make(map[RowStatus]int)
+	for _, row := range j.Rows {
+		counts[row.Status]++
+	}
+	return counts
+}
+
+// clone copia o job, para que os chamadores não compartilhem memória com o Store
+func (j *Job) clone() *Job {
+	c := *j
+	c.Rows = append([]Row(nil), j.Rows...)
+	return &c
+}
+
+// ErrNotFound indica que o job não existe
+var ErrNotFound = errors.New("job não encontrado")
+
+// Store guarda os jobs de importação, um arquivo JSON por job, e as cópias das
+// planilhas enviadas. É seguro para uso concorrente.
+type Store struct {
+	dir    string
+	mu     sync.Mutex
+	jobs   map[int]*Job
+	files  map[int]*store.JSONFile
+	nextID int
+}
+
+// OpenStore abre (ou cria) o diretório de jobs e carrega os jobs existentes
+func OpenStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "uploads"), 0o755); err != nil {
+		return nil, fmt.Errorf("erro ao criar o diretório de jobs: %w", err)
+	}
+
+	s := &Store{dir: dir, jobs: make(map[int]*Job), files: make(map[int]*store.JSONFile), nextID: 1}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "job-*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar os jobs: %w", err)
+	}
+	for _, path := range paths {
+		file, err := store.NewJSONFile(path)
+		if err != nil {
+			return nil, err
+		}
+		job := &Job{}
+		if err := file.Load(job); err != nil {
+			return nil, err
+		}
+		s.jobs[job.ID] = job
+		s.files[job.ID] = file
+		if job.ID >= s.nextID {
+			s.nextID = job.ID + 1
+		}
+	}
+	return s, nil
+}
+
+// Create registra um novo job, copiando a planilha em srcPath para o diretório
+// de dados. O ID, as datas e o caminho do arquivo são preenchidos pelo Store.
+func (s *Store) Create(job *Job, srcPath string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job.ID = s.nextID
+	s.nextID++
+
+	ext := strings.ToLower(filepath.Ext(job.FileName))
+	job.FilePath = filepath.Join(s.dir, "uploads", "job-"+strconv.Itoa(job.ID)+ext)
+	if err := copyFile(srcPath, job.FilePath); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	file, err := store.NewJSONFile(filepath.Join(s.dir, "job-"+strconv.Itoa(job.ID)+".json"))
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Save(job); err != nil {
+		return nil, err
+	}
+
+	stored := job.clone()
+	s.jobs[job.ID] = stored
+	s.files[job.ID] = file
+	return stored.clone(), nil
+}
+
+// Get devolve uma cópia do job
+func (s *Store) Get(id int) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return job.clone(), nil
+}
+
+// Update aplica fn ao job e grava o resultado. Se fn retornar erro, nada é gravado.
+func (s *Store) Update(id int, fn func(*Job) error) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	updated := job.clone()
+	if err := fn(updated); err != nil {
+		return nil, err
+	}
+	updated.UpdatedAt = time.Now()
+	if updated.State.Finished() && updated.FinishedAt == nil {
+		finishedAt := updated.UpdatedAt
+		updated.FinishedAt = &finishedAt
+	}
+
+	if err := s.files[id].Save(updated); err != nil {
+		return nil, err
+	}
+	s.jobs[id] = updated
+	return updated.clone(), nil
+}
+
+// List devolve os jobs do chat, do mais recente para o mais antigo
+func (s *Store) List(chatID int64, limit int) []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobs []*Job
+	for _, job := range s.jobs {
+		if job.ChatID == chatID {
+			jobs = append(jobs, job.clone())
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID > jobs[j].ID })
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs
+}
+
+// Running devolve os jobs que estavam em execução, para serem retomados
+func (s *Store) Running() []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobs []*Job
+	for _, job := range s.jobs {
+		if job.State == StateRunning {
+			jobs = append(jobs, job.clone())
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs
+}
+
+// Prune remove, junto com suas planilhas, os jobs finalizados há mais de
+// finishedAge e os que aguardam confirmação sem alteração há mais de pendingAge
+func (s *Store) Prune(finishedAge, pendingAge time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, job := range s.jobs {
+		switch {
+		case job.FinishedAt != nil:
+			if job.FinishedAt.After(now.Add(-finishedAge)) {
+				continue
+			}
+		case job.State == StateAwaitingConfirmation:
+			if job.UpdatedAt.After(now.Add(-pendingAge)) {
+				continue
+			}
+		default:
+			continue
+		}
+		if err := os.Remove(job.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("erro ao remover a planilha do job %d: %w", id, err)
+		}
+		if err := os.Remove(s.files[id].Path()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("erro ao remover o job %d: %w", id, err)
+		}
+		delete(s.jobs, id)
+		delete(s.files, id)
+	}
+	return nil
+}
+
+// copyFile copia o arquivo src para dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir a planilha: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar a planilha: %w", err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("erro ao salvar a planilha: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("erro ao salvar a planilha: %w", err)
+	}
+	return nil
+}
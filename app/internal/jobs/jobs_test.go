@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(dir, "planilha.csv")
+	if err := os.WriteFile(src, []byte("ID;DATA;HORAS\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	minutes := time.Now().Add(-10 * time.Minute)
+	tests := []struct {
+		name     string
+		state    State
+		updated  time.Time
+		finished *time.Time
+		kept     bool
+	}{
+		{"concluído antigo", StateDone, old, &old, false},
+		{"concluído recente", StateDone, recent, &recent, true},
+		{"cancelado antigo", StateCancelled, old, &old, false},
+		{"aguardando antigo", StateAwaitingConfirmation, old, nil, false},
+		{"aguardando há uma hora", StateAwaitingConfirmation, recent, nil, false},
+		{"aguardando recente", StateAwaitingConfirmation, minutes, nil, true},
+		{"em execução antigo", StateRunning, old, nil, true},
+	}
+	ids := make([]int, len(tests))
+	paths := make([]string, len(tests))
+	for i, tt := range tests {
+		job, err := s.Create(&Job{FileName: "planilha.csv", State: tt.state}, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i], paths[i] = job.ID, job.FilePath
+		s.jobs[job.ID].UpdatedAt = tt.updated
+		s.jobs[job.ID].FinishedAt = tt.finished
+	}
+
+	if err := s.Prune(24*time.Hour, 30*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	for i, tt := range tests {
+		_, err := s.Get(ids[i])
+		if kept := err == nil; kept != tt.kept {
+			t.Errorf("%s: mantido = %v, esperado %v", tt.name, kept, tt.kept)
+		}
+		if _, err := os.Stat(paths[i]); (err == nil) != tt.kept {
+			t.Errorf("%s: planilha mantida = %v, esperado %v", tt.name, err == nil, tt.kept)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package journal
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/store"
+)
+
+// maxOperations limita quantas operações ficam guardadas no diário
+const maxOperations = 1000
+
+// OperationKind identifica o tipo de operação feita pelo bot no banco de horas
+type OperationKind string
+
+const (
+	OperationCreate OperationKind = "criar"
+	OperationUpdate OperationKind = "editar"
+	OperationDelete OperationKind = "excluir"
+)
+
+// Operation registra uma alteração feita pelo bot no banco de horas, com os
+// dados necessários para desfazê-la
+type Operation struct {
+	ID         int                      `json:"id"`
+	Kind       OperationKind            `json:"kind"`
+	ChatID     int64                    `json:"chat_id"`
+	User       string                   `json:"user"`
+	EntryID    int                      `json:"entry_id"`
+	Previous   *models.TimeBalanceEntry `json:"previous,omitempty"` // Valores antes de editar ou excluir
+	Current    *models.TimeBalanceEntry `json:"current,omitempty"`  // Valores enviados ao criar ou editar
+	CreatedAt  time.Time                `json:"created_at"`
+	RevertedAt *time.Time               `json:"reverted_at,omitempty"`
+	RevertedBy string                   `json:"reverted_by,omitempty"`
+}
+
+// Reverted indica se a operação já foi desfeita
+func (op Operation) Reverted() bool {
+	return op.RevertedAt != nil
+}
+
+// Journal é o diário de operações do bot, persistido em disco para sobreviver
+// a reinícios. É seguro para uso concorrente.
+type Journal struct {
+	file *store.JSONFile
+	mu   sync.Mutex
+	data journalData
+}
+
+// journalData é o conteúdo persistido do diário
+type journalData struct {
+	NextID     int         `json:"next_id"`
+	Operations []Operation `json:"operations"`
+}
+
+// Open abre (ou cria) o diário no caminho informado
+func Open(path string) (*Journal, error) {
+	file, err := store.NewJSONFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	j := &Journal{file: file}
+	if err := file.Load(&j.data); err != nil {
+		return nil, err
+	}
+	if j.data.NextID == 0 {
+		j.data.NextID = 1
+	}
+	return j, nil
+}
+
+// Record adiciona uma operação ao diário e devolve a operação com ID e data preenchidos
+func (j *Journal) Record(op Operation) (Operation, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	op.ID = j.data.NextID
+	if op.CreatedAt.IsZero() {
+		op.CreatedAt = time.Now()
+	}
+	j.data.NextID++
+	j.data.Operations = append(j.data.Operations, op)
+
+	// Descarta as operações mais antigas quando o limite é atingido
+	if len(j.data.Operations) > maxOperations {
+		j.data.Operations = j.data.Operations[len(j.data.Operations)-maxOperations:]
+	}
+
+	if err := j.file.Save(j.data); err != nil {
+		return op, err
+	}
+	return op, nil
+}
+
+// Get busca uma operação pelo ID
+func (j *Journal) Get(id int) (Operation, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, op := range j.data.Operations {
+		if op.ID == id {
+			return op, true
+		}
+	}
+	return Operation{}, false
+}
+
+// Recent devolve até limit operações ainda não desfeitas do chat, da mais recente para a mais antiga
+func (j *Journal) Recent(chatID int64, limit int) []Operation {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var ops []Operation
+	for i := len(j.data.Operations) - 1; i >= 0 && len(ops) < limit; i-- {
+		op := j.data.Operations[i]
+		if op.ChatID == chatID && !op.Reverted() {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// MarkReverted marca a operação como desfeita
+func (j *Journal) MarkReverted(id int, user string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.data.Operations {
+		op := &j.data.Operations[i]
+		if op.ID != id {
+			continue
+		}
+		if op.Reverted() {
+			return fmt.Errorf("a operação %d já foi desfeita", id)
+		}
+		now := time.Now()
+		op.RevertedAt = &now
+		op.RevertedBy = user
+		return j.file.Save(j.data)
+	}
+	return fmt.Errorf("operação %d não encontrada", id)
+}
@@ -0,0 +1,150 @@
+package journal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+func openTestJournal(t *testing.T) (*Journal, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "journal.json")
+	j, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return j, path
+}
+
+func TestRecordAndReopen(t *testing.T) {
+	j, path := openTestJournal(t)
+
+	previous := &models.TimeBalanceEntry{EmployeeID: "12", Amount: 3600, Date: "10/03/2025"}
+	first, err := j.Record(Operation{Kind: OperationDelete, ChatID: 1, User: "Ana", EntryID: 5, Previous: previous})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := j.Record(Operation{Kind: OperationCreate, ChatID: 1, User: "Ana", EntryID: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Fatalf("IDs = %d e %d, esperado 1 e 2", first.ID, second.ID)
+	}
+	if first.CreatedAt.IsZero() {
+		t.Error("data da operação não preenchida")
+	}
+	if err := j.MarkReverted(first.ID, "Bia"); err != nil {
+		t.Fatal(err)
+	}
+
+	// O diário reaberto mantém as operações, o estado desfeito e a numeração
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op, ok := reopened.Get(first.ID)
+	if !ok {
+		t.Fatalf("operação %d não encontrada após reabrir", first.ID)
+	}
+	if !op.Reverted() || op.RevertedBy != "Bia" {
+		t.Errorf("operação reaberta = %+v, esperado desfeita por Bia", op)
+	}
+	if op.Previous == nil || *op.Previous != *previous {
+		t.Errorf("valores anteriores = %+v, esperado %+v", op.Previous, previous)
+	}
+	third, err := reopened.Record(Operation{Kind: OperationUpdate, ChatID: 1, EntryID: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.ID != 3 {
+		t.Errorf("ID após reabrir = %d, esperado 3", third.ID)
+	}
+}
+
+func TestRecent(t *testing.T) {
+	j, _ := openTestJournal(t)
+	for _, op := range []Operation{
+		{Kind: OperationCreate, ChatID: 1, EntryID: 10},
+		{Kind: OperationCreate, ChatID: 2, EntryID: 20},
+		{Kind: OperationUpdate, ChatID: 1, EntryID: 11},
+		{Kind: OperationDelete, ChatID: 1, EntryID: 12},
+		{Kind: OperationCreate, ChatID: 1, EntryID: 13},
+	} {
+		if _, err := j.Record(op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := j.MarkReverted(4, "Ana"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		chatID  int64
+		limit   int
+		entries []int
+	}{
+		{"mais recentes primeiro, sem as desfeitas", 1, 10, []int{13, 11, 10}},
+		{"limite", 1, 2, []int{13, 11}},
+		{"outro chat", 2, 10, []int{20}},
+		{"chat sem operações", 3, 10, nil},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, op := range j.Recent(tt.chatID, tt.limit) {
+			got = append(got, op.EntryID)
+		}
+		if len(got) != len(tt.entries) {
+			t.Errorf("%s: lançamentos = %v, esperado %v", tt.name, got, tt.entries)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.entries[i] {
+				t.Errorf("%s: lançamentos = %v, esperado %v", tt.name, got, tt.entries)
+				break
+			}
+		}
+	}
+}
+
+func TestMarkReverted(t *testing.T) {
+	j, _ := openTestJournal(t)
+	op, err := j.Record(Operation{Kind: OperationCreate, ChatID: 1, EntryID: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := j.MarkReverted(op.ID, "Ana"); err != nil {
+		t.Fatalf("primeira reversão: %v", err)
+	}
+	if err := j.MarkReverted(op.ID, "Bia"); err == nil {
+		t.Error("segunda reversão aceita, esperado erro")
+	}
+	if got, _ := j.Get(op.ID); got.RevertedBy != "Ana" {
+		t.Errorf("desfeita por %q, esperado Ana", got.RevertedBy)
+	}
+	if err := j.MarkReverted(99, "Ana"); err == nil {
+		t.Error("reversão de operação inexistente aceita, esperado erro")
+	}
+}
+
+func TestRecordLimit(t *testing.T) {
+	j, _ := openTestJournal(t)
+	for i := 0; i < maxOperations+5; i++ {
+		if _, err := j.Record(Operation{Kind: OperationCreate, ChatID: 1, EntryID: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, ok := j.Get(5); ok {
+		t.Error("operação 5 mantida além do limite, esperado descarte")
+	}
+	if _, ok := j.Get(6); !ok {
+		t.Error("operação 6 descartada, esperado mantida")
+	}
+	if ops := j.Recent(1, maxOperations*2); len(ops) != maxOperations {
+		t.Errorf("%d operações guardadas, esperado %d", len(ops), maxOperations)
+	}
+}
@@ -1,12 +1,38 @@
 package models
 
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
 // Estrutura para armazenar as variáveis de ambiente
 type Config struct {
 	PontoMaisToken   string
 	PontoMaisBaseURL string
-	TelegramBotToken string
-	TelegramHosts    []int64
-	Debug            bool
+	PontoMaisTimeout time.Duration
+	// Retentativas de chamadas à API do Ponto Mais
+	PontoMaisMaxAttempts    int
+	PontoMaisRetryBaseDelay time.Duration
+	PontoMaisRetryMaxDelay  time.Duration
+	PontoMaisRateLimit      float64 // Requisições por segundo permitidas à API
+	PontoMaisRateBurst      int     // Requisições permitidas de uma vez após um período ocioso
+	TelegramBotToken        string
+	TelegramHosts           []int64
+	TelegramWorkers         int           // Quantidade de chats atendidos simultaneamente
+	ConversationTimeout     time.Duration // Tempo sem resposta após o qual os assistentes são encerrados
+	DataDir                 string        // Diretório onde o bot persiste seus dados
+	ImportColumnsFile       string        // Arquivo JSON com apelidos extras para as colunas das planilhas
+	ImportSheet             string        // Aba lida das planilhas quando o /relatorio não indicar outra
+	// Relatório diário de ocorrências nas batidas de ponto
+	DigestTime      string        // Horário de envio, "HH:MM"; vazio desativa
+	DigestRules     []string      // Ocorrências relatadas (ver timecards.IssueKinds)
+	DigestTolerance time.Duration // Diferença em relação à jornada que não gera ocorrência
+	// Agendador de tarefas recorrentes
+	SchedulesFile string // Arquivo JSON com os agendamentos fixos
+	ScheduleChat  int64  // Chat que recebe o resultado das execuções; zero usa o primeiro de TelegramHosts
+	TimeZone      string // Fuso IANA do processo (ex.: America/Sao_Paulo); vazio mantém o do sistema
+	Debug         bool
 }
 
 // Estrutura para armazenar os dados do colaborador
@@ -33,7 +59,31 @@ type Employee struct {
 
 // Struct para mapear a resposta da API
 type EmployeesResponse struct {
-	Employees []Employee `json:"employees"`
+	Employees []Employee      `json:"employees"`
+	Meta      *PaginationMeta `json:"meta,omitempty"`
+}
+
+// PaginationMeta representa os metadados de paginação devolvidos pela API
+type PaginationMeta struct {
+	CurrentPage int `json:"current_page"`
+	TotalPages  int `json:"total_pages"`
+	TotalCount  int `json:"total_count"`
+	PerPage     int `json:"per_page"`
+}
+
+// HasNextPage indica se existe uma página depois de page. Quando a API não envia
+// metadados (m é nil), uma página cheia indica que pode haver mais registros.
+func (m *PaginationMeta) HasNextPage(page, perPage, received int) bool {
+	if received == 0 {
+		return false
+	}
+	if m != nil && m.TotalPages > 0 {
+		return page < m.TotalPages
+	}
+	if m != nil && m.TotalCount > 0 {
+		return page*perPage < m.TotalCount
+	}
+	return received >= perPage
 }
 
 // TimeBalanceEntry representa os dados para atualização do banco de horas
@@ -44,3 +94,133 @@ type TimeBalanceEntry struct {
 	Withdraw    bool    `json:"withdraw"`
 	EmployeeID  string  `json:"employee_id,omitempty"`
 }
+
+// TimeBalanceEntryRecord representa um lançamento do banco de horas retornado pela API
+type TimeBalanceEntryRecord struct {
+	ID          int       `json:"id"`
+	EmployeeID  int       `json:"employee_id"`
+	Employee    *Employee `json:"employee,omitempty"`
+	Amount      float64   `json:"amount"`
+	Date        string    `json:"date"`
+	Observation string    `json:"observation"`
+	Withdraw    bool      `json:"withdraw"`
+	CreatedAt   string    `json:"created_at,omitempty"`
+}
+
+// SignedAmount devolve a quantidade em segundos, negativa quando o lançamento é uma retirada
+func (r TimeBalanceEntryRecord) SignedAmount() float64 {
+	if r.Withdraw {
+		return -r.Amount
+	}
+	return r.Amount
+}
+
+// ParsedDate converte a data do lançamento, aceitando os formatos usados pela API
+func (r TimeBalanceEntryRecord) ParsedDate() (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02/01/2006", time.RFC3339} {
+		if date, err := time.Parse(layout, r.Date); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data do lançamento em formato desconhecido: %q", r.Date)
+}
+
+// Entry converte o registro nos dados usados para criar ou atualizar um lançamento
+func (r TimeBalanceEntryRecord) Entry() TimeBalanceEntry {
+	date := r.Date
+	if parsed, err := r.ParsedDate(); err == nil {
+		date = parsed.Format("02/01/2006")
+	}
+	entry := TimeBalanceEntry{
+		Amount:      r.Amount,
+		Date:        date,
+		Observation: r.Observation,
+		Withdraw:    r.Withdraw,
+	}
+	if r.EmployeeID != 0 {
+		entry.EmployeeID = strconv.Itoa(r.EmployeeID)
+	}
+	return entry
+}
+
+// TimeBalanceEntriesResponse mapeia a resposta da listagem de lançamentos
+type TimeBalanceEntriesResponse struct {
+	TimeBalanceEntries []TimeBalanceEntryRecord `json:"time_balance_entries"`
+	Meta               *PaginationMeta          `json:"meta,omitempty"`
+}
+
+// TimeBalanceEntryResponse mapeia a resposta com um único lançamento
+type TimeBalanceEntryResponse struct {
+	TimeBalanceEntry TimeBalanceEntryRecord `json:"time_balance_entry"`
+}
+
+// TimeBalanceHistoryItem é um lançamento acompanhado do saldo acumulado até ele, em segundos
+type TimeBalanceHistoryItem struct {
+	Entry   TimeBalanceEntryRecord
+	Balance float64
+}
+
+// TimeBalanceStatement é o extrato do banco de horas de um colaborador em um
+// período. As quantidades são em segundos; os débitos são positivos.
+type TimeBalanceStatement struct {
+	EmployeeID     int
+	Start          time.Time
+	End            time.Time
+	OpeningBalance float64                  // Saldo antes do início do período
+	Credits        float64                  // Créditos do período
+	Debits         float64                  // Débitos do período
+	Items          []TimeBalanceHistoryItem // Lançamentos do período, com o saldo total após cada um
+}
+
+// ClosingBalance devolve o saldo ao fim do período
+func (s TimeBalanceStatement) ClosingBalance() float64 {
+	return s.OpeningBalance + s.Credits - s.Debits
+}
+
+// TimeBalanceSummary resume o banco de horas de um colaborador. As quantidades
+// são em segundos; os débitos do período são positivos.
+type TimeBalanceSummary struct {
+	EmployeeID    int
+	Balance       float64 // Saldo de todos os lançamentos
+	Entries       int     // Quantidade total de lançamentos
+	PeriodStart   time.Time
+	PeriodEnd     time.Time
+	PeriodCredits float64
+	PeriodDebits  float64
+	Recent        []TimeBalanceEntryRecord // Últimos lançamentos, do mais recente ao mais antigo
+}
+
+// TimeCard representa uma batida de ponto retornada pela API
+type TimeCard struct {
+	ID         int       `json:"id"`
+	EmployeeID int       `json:"employee_id"`
+	Employee   *Employee `json:"employee,omitempty"`
+	Date       string    `json:"date"`
+	Time       string    `json:"time"`
+	Source     string    `json:"source,omitempty"`
+}
+
+// ParsedTime combina a data e a hora da batida, aceitando os formatos usados
+// pela API. A hora também pode vir completa, em RFC 3339.
+func (c TimeCard) ParsedTime() (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, c.Time); err == nil {
+		return t, nil
+	}
+	record := TimeBalanceEntryRecord{Date: c.Date}
+	date, err := record.ParsedDate()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data da batida em formato desconhecido: %q", c.Date)
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if clock, err := time.Parse(layout, c.Time); err == nil {
+			return date.Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute + time.Duration(clock.Second())*time.Second), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("hora da batida em formato desconhecido: %q", c.Time)
+}
+
+// TimeCardsResponse mapeia a resposta da listagem de batidas
+type TimeCardsResponse struct {
+	TimeCards []TimeCard      `json:"time_cards"`
+	Meta      *PaginationMeta `json:"meta,omitempty"`
+}
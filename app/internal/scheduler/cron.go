@@ -0,0 +1,163 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronAliases são os atalhos aceitos no lugar das cinco posições
+var cronAliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// cronField descreve uma das cinco posições de uma expressão cron
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [5]cronField{
+	{"minuto", 0, 59},
+	{"hora", 0, 23},
+	{"dia do mês", 1, 31},
+	{"mês", 1, 12},
+	{"dia da semana", 0, 7}, // 0 e 7 são domingo
+}
+
+// Cron é uma expressão no formato do cron: minuto, hora, dia do mês, mês e dia
+// da semana, ex.: "0 8 * * 1-5" (às 8h, de segunda a sexta)
+type Cron struct {
+	expr   string
+	fields [5]uint64 // Valores permitidos em cada posição, como bits
+	// Como no cron, quando o dia do mês e o da semana são restritos, basta um
+	// deles corresponder
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+// ParseCron interpreta uma expressão cron de cinco posições. Cada posição
+// aceita *, números, listas (1,15), intervalos (1-5) e passos (*/15, 8-18/2).
+// Também aceita os atalhos @hourly, @daily, @weekly, @monthly e @yearly.
+func ParseCron(expr string) (*Cron, error) {
+	expr = strings.Join(strings.Fields(expr), " ")
+	spec := expr
+	if alias, ok := cronAliases[strings.ToLower(expr)]; ok {
+		spec = alias
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("expressão cron inválida '%s': são necessárias 5 posições (minuto hora dia mês dia-da-semana)", expr)
+	}
+
+	c := &Cron{expr: expr, anyDayOfMonth: parts[2] == "*", anyDayOfWeek: parts[4] == "*"}
+	for i, part := range parts {
+		bits, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("expressão cron inválida '%s': %w", expr, err)
+		}
+		c.fields[i] = bits
+	}
+	// Domingo pode ser 0 ou 7
+	if c.fields[4]&(1<<7) != 0 {
+		c.fields[4] |= 1
+	}
+	return c, nil
+}
+
+// parseCronField interpreta uma posição da expressão
+func parseCronField(part string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(part, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("passo inválido '%s' no %s", stepPart, field.name)
+			}
+			step = n
+		}
+
+		low, high := field.min, field.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err1, err2 error
+			low, err1 = strconv.Atoi(from)
+			high, err2 = strconv.Atoi(to)
+			if err1 != nil || err2 != nil || low > high {
+				return 0, fmt.Errorf("intervalo inválido '%s' no %s", rangePart, field.name)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("valor inválido '%s' no %s", rangePart, field.name)
+			}
+			low = n
+			if !hasStep {
+				high = n
+			}
+		}
+		if low < field.min || high > field.max {
+			return 0, fmt.Errorf("%s fora do intervalo %d-%d: '%s'", field.name, field.min, field.max, item)
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// String devolve a expressão como foi informada
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Next devolve o primeiro horário depois de after que corresponde à
+// expressão, no fuso de after. Devolve o tempo zero se não houver nenhum nos
+// próximos 5 anos (ex.: 31 de fevereiro).
+func (c *Cron) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !c.has(3, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.has(1, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !c.has(0, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// has indica se o valor é permitido na posição informada
+func (c *Cron) has(field, value int) bool {
+	return c.fields[field]&(1<<value) != 0
+}
+
+// matchesDay aplica a regra do cron para o dia do mês e o dia da semana
+func (c *Cron) matchesDay(t time.Time) bool {
+	dayOfMonth := c.has(2, t.Day())
+	dayOfWeek := c.has(4, int(t.Weekday()))
+	switch {
+	case c.anyDayOfMonth && c.anyDayOfWeek:
+		return true
+	case c.anyDayOfMonth:
+		return dayOfWeek
+	case c.anyDayOfWeek:
+		return dayOfMonth
+	}
+	return dayOfMonth || dayOfWeek
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{"0 8 * * 1-5", false},
+		{"*/15 8-18/2 1,15 * *", false},
+		{"  0   7 1 * *  ", false},
+		{"0 0 * * 7", false},
+		{"@daily", false},
+		{"@Monthly", false},
+		{"0 8 * *", true},
+		{"0 8 * * * *", true},
+		{"60 8 * * *", true},
+		{"0 24 * * *", true},
+		{"0 8 0 * *", true},
+		{"0 8 * 13 *", true},
+		{"0 8 * * 8", true},
+		{"0 8 5-1 * *", true},
+		{"*/0 8 * * *", true},
+		{"a 8 * * *", true},
+		{"@sempre", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		_, err := ParseCron(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCron(%q): erro = %v, esperava erro: %v", tt.expr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	brt := time.FixedZone("BRT", -3*3600)
+	at := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, brt)
+	}
+	// 18/03/2025 é uma terça-feira
+	tests := []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"0 8 * * *", at(2025, 3, 18, 7, 59), at(2025, 3, 18, 8, 0)},
+		{"0 8 * * *", at(2025, 3, 18, 8, 0), at(2025, 3, 19, 8, 0)},
+		{"0 8 * * *", at(2025, 3, 18, 8, 0).Add(30 * time.Second), at(2025, 3, 19, 8, 0)},
+		{"0 8 * * 1-5", at(2025, 3, 21, 9, 0), at(2025, 3, 24, 8, 0)},
+		{"*/15 * * * *", at(2025, 3, 18, 10, 7), at(2025, 3, 18, 10, 15)},
+		{"0 8-18/4 * * *", at(2025, 3, 18, 13, 0), at(2025, 3, 18, 16, 0)},
+		{"0 7 1 * *", at(2025, 3, 18, 0, 0), at(2025, 4, 1, 7, 0)},
+		{"0 0 31 * *", at(2025, 4, 1, 0, 0), at(2025, 5, 31, 0, 0)},
+		{"0 0 29 2 *", at(2025, 1, 1, 0, 0), at(2028, 2, 29, 0, 0)},
+		{"30 23 31 12 *", at(2025, 12, 31, 23, 30), at(2026, 12, 31, 23, 30)},
+		// Domingo como 7, e dia do mês ou dia da semana (regra do cron)
+		{"0 9 * * 7", at(2025, 3, 18, 0, 0), at(2025, 3, 23, 9, 0)},
+		{"0 9 20 * 0", at(2025, 3, 18, 0, 0), at(2025, 3, 20, 9, 0)},
+		{"@weekly", at(2025, 3, 18, 0, 0), at(2025, 3, 23, 0, 0)},
+		{"0 0 31 2 *", at(2025, 1, 1, 0, 0), time.Time{}},
+	}
+	for _, tt := range tests {
+		cron, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		got := cron.Next(tt.after)
+		if !got.Equal(tt.want) {
+			t.Errorf("Next(%q, %s) = %s, esperado %s", tt.expr, tt.after.Format("02/01/2006 15:04:05"), got, tt.want)
+		}
+		if !got.IsZero() && got.Location() != brt {
+			t.Errorf("Next(%q) no fuso %s, esperado o de after", tt.expr, got.Location())
+		}
+	}
+}
+
+func TestNewEntry(t *testing.T) {
+	now := time.Date(2025, 3, 18, 10, 0, 0, 0, time.Local)
+	tests := []struct {
+		task, when string
+		spec       string
+		at         time.Time
+		wantErr    bool
+	}{
+		{"Ocorrencias", "0  8 * * 1-5", "0 8 * * 1-5", time.Time{}, false},
+		{"saldos", "@monthly", "@monthly", time.Time{}, false},
+		{"importacao", "01/11/2026 08:00", "", time.Date(2026, 11, 1, 8, 0, 0, 0, time.Local), false},
+		{"importacao", "2026-11-01", "", time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local), false},
+		{"importacao", "amanha 07:30", "", time.Date(2025, 3, 19, 7, 30, 0, 0, time.Local), false},
+		{"importacao", "amanhã", "", time.Date(2025, 3, 19, 0, 0, 0, 0, time.Local), false},
+		{"importacao", "01/11/2026 25:00", "", time.Time{}, true},
+		{"saldos", "", "", time.Time{}, true},
+		{"saldos", "todo dia", "", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		entry, err := NewEntry(tt.task, tt.when, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewEntry(%q, %q): erro = %v, esperava erro: %v", tt.task, tt.when, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if entry.Task != "ocorrencias" && entry.Task != tt.task {
+			t.Errorf("NewEntry(%q): tarefa = %q", tt.task, entry.Task)
+		}
+		if entry.Spec != tt.spec {
+			t.Errorf("NewEntry(%q): spec = %q, esperado %q", tt.when, entry.Spec, tt.spec)
+		}
+		if tt.at.IsZero() != (entry.At == nil) || (entry.At != nil && !entry.At.Equal(tt.at)) {
+			t.Errorf("NewEntry(%q): at = %v, esperado %s", tt.when, entry.At, tt.at)
+		}
+	}
+}
@@ -0,0 +1,564 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/command"
+	"github.com/jeffemart/PontoGo/app/internal/store"
+)
+
+// Source indica onde o agendamento foi definido
+type Source string
+
+const (
+	SourceConfig Source = "configuracao" // SCHEDULES_FILE ou variáveis de ambiente; recriado a cada início
+	SourceChat   Source = "chat"         // Comando /agendar; persistido até ser removido
+)
+
+// Entry é uma tarefa agendada. As recorrentes têm uma expressão cron (Spec);
+// as de execução única, um horário (At) e são removidas após a execução.
+type Entry struct {
+	ID         int               `json:"id"`
+	Task       string            `json:"task"`
+	Spec       string            `json:"spec,omitempty"`
+	At         *time.Time        `json:"at,omitempty"`
+	Args       map[string]string `json:"args,omitempty"`
+	ChatID     int64             `json:"chat_id,omitempty"` // Chat que recebe o resultado; zero usa o padrão
+	Source     Source            `json:"source"`
+	CreatedBy  string            `json:"created_by,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Next       time.Time         `json:"next"` // Próxima execução; zero se não houver
+	LastRun    *time.Time        `json:"last_run,omitempty"`
+	LastResult string            `json:"last_result,omitempty"`
+	LastError  string            `json:"last_error,omitempty"`
+	Runs       int               `json:"runs"`
+}
+
+// When descreve quando a tarefa executa, ex.: "0 8 * * 1-5" ou "01/11/2026 08:00"
+func (e Entry) When() string {
+	if e.At != nil {
+		return e.At.Format("02/01/2006 15:04")
+	}
+	return e.Spec
+}
+
+// Once indica se a tarefa executa uma única vez
+func (e Entry) Once() bool {
+	return e.At != nil
+}
+
+// Task executa uma tarefa agendada e devolve um resumo do resultado
+type Task func(ctx context.Context, entry Entry) (string, error)
+
+// Result é o resultado de uma execução, entregue ao callback de OnResult
+type Result struct {
+	Entry    Entry // Agendamento após a execução, com a próxima execução atualizada
+	Output   string
+	Err      error
+	Started  time.Time
+	Finished time.Time
+	Late     bool // A execução estava atrasada por o bot estar parado no horário
+	Manual   bool // Execução pedida pelo /agendar executar
+}
+
+// ErrNotFound indica que o agendamento não existe
+var ErrNotFound = errors.New("agendamento não encontrado")
+
+// Scheduler executa as tarefas agendadas no processo do bot. Os agendamentos
+// são persistidos em disco e sobrevivem a reinícios. É seguro para uso concorrente.
+type Scheduler struct {
+	file     *store.JSONFile
+	mu       sync.Mutex
+	data     schedulerData
+	tasks    map[string]Task
+	onResult func(Result)
+	wake     chan struct{}
+	manual   []int // Execuções pedidas pelo RunNow, feitas em ordem pelo laço de Run
+}
+
+// schedulerData é o conteúdo persistido do agendador
+type schedulerData struct {
+	NextID  int     `json:"next_id"`
+	Entries []Entry `json:"entries"`
+}
+
+// Open abre (ou cria) o arquivo de agendamentos no caminho informado
+func Open(path string) (*Scheduler, error) {
+	file, err := store.NewJSONFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Scheduler{file: file, tasks: make(map[string]Task), wake: make(chan struct{}, 1)}
+	if err := file.Load(&s.data); err != nil {
+		return nil, err
+	}
+	if s.data.NextID == 0 {
+		s.data.NextID = 1
+	}
+	return s, nil
+}
+
+// Register associa uma tarefa a um nome, usado nos agendamentos
+func (s *Scheduler) Register(name string, task Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[name] = task
+}
+
+// Tasks devolve os nomes das tarefas registradas, em ordem alfabética
+func (s *Scheduler) Tasks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.tasks))
+	for name := range s.tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OnResult define a função chamada ao fim de cada execução
+func (s *Scheduler) OnResult(fn func(Result)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onResult = fn
+}
+
+// SetConfigEntries substitui os agendamentos da configuração. Os que já
+// existiam com a mesma tarefa, horário, argumentos e chat mantêm o número e o
+// histórico; os que saíram da configuração são removidos.
+func (s *Scheduler) SetConfigEntries(entries []Entry) error {
+	for _, entry := range entries {
+		if _, err := s.validate(entry); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var kept []Entry
+	used := make(map[int]bool)
+	for _, entry := range entries {
+		entry.Source = SourceConfig
+		existing, ok := s.findConfigEntry(entry, used)
+		if ok {
+			used[existing.ID] = true
+			continue
+		}
+		entry.ID = s.data.NextID
+		s.data.NextID++
+		entry.CreatedAt = time.Now()
+		entry.Next = nextRun(entry, time.Now())
+		kept = append(kept, entry)
+	}
+
+	var all []Entry
+	for _, entry := range s.data.Entries {
+		if entry.Source != SourceConfig || used[entry.ID] {
+			all = append(all, entry)
+		}
+	}
+	s.data.Entries = append(all, kept...)
+	s.notify()
+	return s.file.Save(s.data)
+}
+
+// findConfigEntry busca um agendamento da configuração equivalente ao informado
+func (s *Scheduler) findConfigEntry(entry Entry, used map[int]bool) (Entry, bool) {
+	for _, existing := range s.data.Entries {
+		if existing.Source == SourceConfig && !used[existing.ID] &&
+			existing.Task == entry.Task && existing.When() == entry.When() &&
+			existing.ChatID == entry.ChatID && maps.Equal(existing.Args, entry.Args) {
+			return existing, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Add valida e persiste um novo agendamento, devolvendo-o com o número e a
+// próxima execução preenchidos
+func (s *Scheduler) Add(entry Entry) (Entry, error) {
+	next, err := s.validate(entry)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry.ID = s.data.NextID
+	s.data.NextID++
+	if entry.Source == "" {
+		entry.Source = SourceChat
+	}
+	entry.CreatedAt = time.Now()
+	entry.Next = next
+	s.data.Entries = append(s.data.Entries, entry)
+	s.notify()
+	return entry, s.file.Save(s.data)
+}
+
+// validate confere a tarefa e o horário do agendamento e devolve a próxima execução
+func (s *Scheduler) validate(entry Entry) (time.Time, error) {
+	s.mu.Lock()
+	_, ok := s.tasks[entry.Task]
+	s.mu.Unlock()
+	if !ok {
+		return time.Time{}, fmt.Errorf("tarefa desconhecida '%s': use %s", entry.Task, strings.Join(s.Tasks(), ", "))
+	}
+
+	if entry.At != nil {
+		if !entry.At.After(time.Now()) {
+			return time.Time{}, fmt.Errorf("o horário %s já passou", entry.At.Format("02/01/2006 15:04"))
+		}
+		return *entry.At, nil
+	}
+	cron, err := ParseCron(entry.Spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := cron.Next(time.Now())
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("a expressão '%s' não corresponde a nenhum horário", entry.Spec)
+	}
+	return next, nil
+}
+
+// Validate confere a tarefa e o horário do agendamento, sem registrá-lo
+func (s *Scheduler) Validate(entry Entry) error {
+	_, err := s.validate(entry)
+	return err
+}
+
+// Remove exclui um agendamento feito pelo chat
+func (s *Scheduler) Remove(id int) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, entry := range s.data.Entries {
+		if entry.ID != id {
+			continue
+		}
+		if entry.Source == SourceConfig {
+			return Entry{}, fmt.Errorf("o agendamento #%d vem da configuração do bot e só pode ser removido nela", id)
+		}
+		s.data.Entries = append(s.data.Entries[:i:i], s.data.Entries[i+1:]...)
+		s.notify()
+		return entry, s.file.Save(s.data)
+	}
+	return Entry{}, ErrNotFound
+}
+
+// Get devolve um agendamento pelo número
+func (s *Scheduler) Get(id int) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range s.data.Entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return Entry{}, ErrNotFound
+}
+
+// List devolve os agendamentos em ordem de próxima execução
+func (s *Scheduler) List() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := append([]Entry(nil), s.data.Entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Next.IsZero() != entries[j].Next.IsZero() {
+			return !entries[i].Next.IsZero()
+		}
+		return entries[i].Next.Before(entries[j].Next)
+	})
+	return entries
+}
+
+// Run executa as tarefas nos horários agendados e as pedidas pelo RunNow até
+// ctx ser cancelado. As execuções perdidas enquanto o bot estava parado são
+// feitas uma única vez, logo no início. As tarefas executam uma de cada vez,
+// sempre nesta goroutine, então a volta de Run garante que nenhuma está em
+// andamento.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if next, ok := s.nextDue(); ok {
+			timer = time.NewTimer(time.Until(next))
+			fire = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-s.wake:
+			if timer != nil {
+				timer.Stop()
+			}
+			s.runManual(ctx)
+		case <-fire:
+			s.runDue(ctx)
+			s.runManual(ctx)
+		}
+	}
+}
+
+// RunNow pede a execução imediata de um agendamento, sem alterar a próxima
+// execução. Ela é feita pelo laço de Run, logo após a tarefa em andamento, se
+// houver; pedidos repetidos antes da execução contam uma vez só.
+func (s *Scheduler) RunNow(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	found := false
+	for _, entry := range s.data.Entries {
+		if entry.ID == id {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrNotFound
+	}
+	if !slices.Contains(s.manual, id) {
+		s.manual = append(s.manual, id)
+	}
+	s.notify()
+	return nil
+}
+
+// runManual faz as execuções pedidas pelo RunNow, na ordem dos pedidos
+func (s *Scheduler) runManual(ctx context.Context) {
+	for ctx.Err() == nil {
+		s.mu.Lock()
+		if len(s.manual) == 0 {
+			s.mu.Unlock()
+			return
+		}
+		id := s.manual[0]
+		s.manual = s.manual[1:]
+		s.mu.Unlock()
+
+		// O agendamento pode ter sido removido depois do pedido
+		if entry, err := s.Get(id); err == nil {
+			s.execute(ctx, entry, false, true)
+		}
+	}
+}
+
+// nextDue devolve o horário da próxima execução entre todos os agendamentos
+func (s *Scheduler) nextDue() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	for _, entry := range s.data.Entries {
+		if !entry.Next.IsZero() && (next.IsZero() || entry.Next.Before(next)) {
+			next = entry.Next
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// runDue executa os agendamentos cujo horário já chegou
+func (s *Scheduler) runDue(ctx context.Context) {
+	now := time.Now()
+	s.mu.Lock()
+	var due []Entry
+	for _, entry := range s.data.Entries {
+		if !entry.Next.IsZero() && !entry.Next.After(now) {
+			due = append(due, entry)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, entry := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		// Uma execução com mais de um minuto de atraso foi perdida com o bot parado
+		s.execute(ctx, entry, now.Sub(entry.Next) > time.Minute, false)
+	}
+}
+
+// execute roda a tarefa do agendamento, registra o resultado e agenda a próxima execução
+func (s *Scheduler) execute(ctx context.Context, entry Entry, late, manual bool) {
+	s.mu.Lock()
+	task := s.tasks[entry.Task]
+	s.mu.Unlock()
+
+	result := Result{Started: time.Now(), Late: late, Manual: manual}
+	if task == nil {
+		result.Err = fmt.Errorf("tarefa desconhecida '%s'", entry.Task)
+	} else {
+		result.Output, result.Err = runTask(ctx, task, entry)
+	}
+	result.Finished = time.Now()
+
+	s.mu.Lock()
+	for i := range s.data.Entries {
+		stored := &s.data.Entries[i]
+		if stored.ID != entry.ID {
+			continue
+		}
+		stored.LastRun = &result.Started
+		stored.LastResult = result.Output
+		stored.LastError = ""
+		if result.Err != nil {
+			stored.LastError = result.Err.Error()
+		}
+		stored.Runs++
+		if !manual {
+			stored.Next = nextRun(*stored, result.Finished)
+		}
+		result.Entry = *stored
+		if stored.Once() && !manual {
+			s.data.Entries = append(s.data.Entries[:i:i], s.data.Entries[i+1:]...)
+		}
+		break
+	}
+	if result.Entry.ID == 0 {
+		// Removido durante a execução
+		result.Entry = entry
+	}
+	err := s.file.Save(s.data)
+	onResult := s.onResult
+	s.mu.Unlock()
+
+	if err != nil && result.Err == nil {
+		result.Err = fmt.Errorf("tarefa executada, mas não foi possível salvar o agendamento: %w", err)
+	}
+	if onResult != nil {
+		onResult(result)
+	}
+}
+
+// runTask executa a tarefa, convertendo um panic em erro para não derrubar o bot
+func runTask(ctx context.Context, task Task, entry Entry) (output string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("erro inesperado na tarefa: %v", r)
+		}
+	}()
+	return task(ctx, entry)
+}
+
+// nextRun calcula a próxima execução do agendamento após after; zero se não houver
+func nextRun(entry Entry, after time.Time) time.Time {
+	if entry.At != nil {
+		if entry.At.After(after) {
+			return *entry.At
+		}
+		return time.Time{}
+	}
+	cron, err := ParseCron(entry.Spec)
+	if err != nil {
+		return time.Time{}
+	}
+	return cron.Next(after)
+}
+
+// notify acorda o laço de Run para recalcular a próxima execução. Deve ser
+// chamado com s.mu travado.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// fileEntry é um agendamento no arquivo de configuração (SCHEDULES_FILE)
+type fileEntry struct {
+	Task string            `json:"tarefa"`
+	When string            `json:"quando"`
+	Chat int64             `json:"chat,omitempty"`
+	Args map[string]string `json:"args,omitempty"`
+}
+
+// LoadFile lê os agendamentos de um arquivo JSON no formato
+// [{"tarefa": "ocorrencias", "quando": "0 8 * * 1-5", "chat": 123}, ...].
+// Sem path, não devolve nenhum.
+func LoadFile(path string) ([]Entry, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler os agendamentos: %w", err)
+	}
+	var items []fileEntry
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar os agendamentos: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(items))
+	for i, item := range items {
+		entry, err := NewEntry(item.Task, item.When, time.Now())
+		if err == nil && entry.Once() {
+			err = fmt.Errorf("o arquivo aceita apenas expressões cron; use o /agendar para execuções únicas")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("agendamento %d de %s: %w", i+1, path, err)
+		}
+		entry.ChatID = item.Chat
+		entry.Args = item.Args
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// NewEntry monta um agendamento a partir do nome da tarefa e de quando
+// executá-la: uma expressão cron (recorrente) ou uma data com horário opcional
+// (execução única), ex.: "01/11/2026 08:00", "2026-11-01", "amanha 07:30".
+// Sem horário, a execução única é à meia-noite.
+func NewEntry(task, when string, now time.Time) (Entry, error) {
+	entry := Entry{Task: strings.ToLower(strings.TrimSpace(task))}
+	when = strings.TrimSpace(when)
+	if when == "" {
+		return Entry{}, fmt.Errorf("informe quando executar a tarefa")
+	}
+
+	if at, ok := parseMoment(when, now); ok {
+		entry.At = &at
+		return entry, nil
+	}
+	if _, err := ParseCron(when); err != nil {
+		return Entry{}, fmt.Errorf("%v; para uma execução única, use DD/MM/AAAA HH:MM", err)
+	}
+	entry.Spec = strings.Join(strings.Fields(when), " ")
+	return entry, nil
+}
+
+// parseMoment interpreta uma data com horário opcional (HH:MM)
+func parseMoment(value string, now time.Time) (time.Time, bool) {
+	parts := strings.Fields(value)
+	if len(parts) == 0 || len(parts) > 2 {
+		return time.Time{}, false
+	}
+	var date time.Time
+	var err error
+	if strings.EqualFold(parts[0], "amanha") || strings.EqualFold(parts[0], "amanhã") {
+		date = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	} else if date, err = command.ParseDate(parts[0], now); err != nil {
+		return time.Time{}, false
+	}
+	if len(parts) == 2 {
+		clock, err := time.Parse("15:04", parts[1])
+		if err != nil {
+			return time.Time{}, false
+		}
+		date = date.Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute)
+	}
+	return date, true
+}
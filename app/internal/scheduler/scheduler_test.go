@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunNowSerialized(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "schedules.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var running, peak atomic.Int32
+	started := make(chan int, 10)
+	release := make(chan struct{})
+	s.Register("lenta", func(ctx context.Context, entry Entry) (string, error) {
+		if n := running.Add(1); n > peak.Load() {
+			peak.Store(n)
+		}
+		defer running.Add(-1)
+		started <- entry.ID
+		<-release
+		return "ok", nil
+	})
+	var results []Result
+	s.OnResult(func(result Result) { results = append(results, result) })
+
+	a, err := s.Add(Entry{Task: "lenta", Spec: "0 0 1 1 *"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.Add(Entry{Task: "lenta", Spec: "0 0 1 1 *"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RunNow(999); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("RunNow de agendamento inexistente = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	// A executa; B é pedido duas vezes e A de novo enquanto A ainda roda
+	s.RunNow(a.ID)
+	if id := <-started; id != a.ID {
+		t.Fatalf("primeira execução = #%d, esperado #%d", id, a.ID)
+	}
+	for _, id := range []int{b.ID, b.ID, a.ID} {
+		if err := s.RunNow(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	release <- struct{}{}
+	for _, want := range []int{b.ID, a.ID} {
+		if id := <-started; id != want {
+			t.Fatalf("execução = #%d, esperado #%d", id, want)
+		}
+		if want == a.ID {
+			break
+		}
+		release <- struct{}{}
+	}
+
+	// Com a última tarefa em andamento, o cancelamento espera o fim dela
+	cancel()
+	select {
+	case <-done:
+		t.Fatal("Run voltou com uma tarefa em andamento")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release <- struct{}{}
+	<-done
+
+	if p := peak.Load(); p != 1 {
+		t.Errorf("%d tarefas ao mesmo tempo, esperado 1", p)
+	}
+	if len(results) != 3 {
+		t.Fatalf("%d resultados, esperado 3", len(results))
+	}
+	for _, result := range results {
+		if !result.Manual || result.Err != nil || result.Output != "ok" {
+			t.Errorf("resultado = %+v", result)
+		}
+	}
+	if entry, _ := s.Get(a.ID); entry.Runs != 2 || !entry.Next.Equal(a.Next) {
+		t.Errorf("agendamento #%d: %d execuções, próxima %s; esperado 2 e %s", a.ID, entry.Runs, entry.Next, a.Next)
+	}
+}
@@ -0,0 +1,202 @@
+package pontomais
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// defaultTimeout é o tempo máximo de uma requisição quando a configuração não define outro
+const defaultTimeout = 30 * time.Second
+
+// Client é o cliente da API do Ponto Mais. Deve ser criado uma única vez com
+// NewClient e compartilhado entre os chamadores; é seguro para uso concorrente.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	retry      RetryPolicy
+	limiter    *rateLimiter
+	logger     *log.Logger
+}
+
+// Option personaliza o Client criado por NewClient
+type Option func(*Client)
+
+// WithHTTPClient substitui o *http.Client usado nas requisições
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithLogger define o logger usado pelo cliente. Por padrão as mensagens são descartadas.
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// NewClient cria um cliente da API do Ponto Mais a partir das configurações.
+// O token em cfg.PontoMaisToken é decodificado de Base64 uma única vez aqui.
+func NewClient(cfg *models.Config, opts ...Option) (*Client, error) {
+	if cfg.PontoMaisBaseURL == "" || cfg.PontoMaisToken == "" {
+		return nil, fmt.Errorf("variáveis de ambiente PONTOMAIS_TOKEN ou PONTOMAIS_BASE_URL não definidas corretamente")
+	}
+
+	// Decodifica o token sem depender do logger global, para que o cliente
+	// possa ser usado fora do bot
+	decodedToken, err := base64.StdEncoding.DecodeString(cfg.PontoMaisToken)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao decodificar o token do Ponto Mais: %w", err)
+	}
+
+	timeout := cfg.PontoMaisTimeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	c := &Client{
+		baseURL:    strings.TrimRight(cfg.PontoMaisBaseURL, "/"),
+		token:      string(decodedToken),
+		httpClient: &http.Client{Timeout: timeout},
+		retry: RetryPolicy{
+			MaxAttempts: cfg.PontoMaisMaxAttempts,
+			BaseDelay:   cfg.PontoMaisRetryBaseDelay,
+			MaxDelay:    cfg.PontoMaisRetryMaxDelay,
+		}.withDefaults(),
+		limiter: newRateLimiter(cfg.PontoMaisRateLimit, cfg.PontoMaisRateBurst),
+		logger:  log.New(io.Discard, "", 0),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// do executa uma requisição autenticada contra a API. Se body não for nil ele é
+// serializado como JSON; se out não for nil a resposta é deserializada nele.
+// Falhas transitórias são repetidas conforme a política de retentativas.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}, okStatus ...int) error {
+	var jsonData []byte
+	if body != nil {
+		var err error
+		jsonData, err = json.Marshal(body)
+		if err != nil {
+			c.logger.Printf("Erro ao serializar os dados: %v", err)
+			return fmt.Errorf("erro ao serializar os dados: %w", err)
+		}
+	}
+
+	if len(okStatus) == 0 {
+		okStatus = []int{http.StatusOK}
+	}
+
+	for attempt := 1; ; attempt++ {
+		// Toda tentativa passa pelo limite de requisições compartilhado
+		if err := c.limiter.wait(ctx); err != nil {
+			return err
+		}
+
+		err := c.doOnce(ctx, method, path, jsonData, out, okStatus)
+		if err == nil {
+			return nil
+		}
+
+		// Um 429 com Retry-After suspende as requisições de todos os chamadores
+		var apiErr *APIError
+		rateLimited := errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests && apiErr.RetryAfter > 0
+		if rateLimited {
+			c.limiter.pause(apiErr.RetryAfter)
+		}
+
+		if attempt >= c.retry.MaxAttempts || !shouldRetry(method, err) {
+			return err
+		}
+
+		// Respeita o Retry-After informado pela API; caso contrário usa backoff
+		wait := c.retry.backoff(attempt)
+		if apiErr != nil && apiErr.RetryAfter > 0 {
+			wait = apiErr.RetryAfter
+		}
+		if rateLimited {
+			// A espera já é feita pelo limitador na próxima tentativa
+			c.logger.Printf("Limite de requisições da API atingido em %s %s; pausando as requisições por %s", method, path, wait)
+			continue
+		}
+
+		c.logger.Printf("Tentativa %d/%d de %s %s falhou (%v); nova tentativa em %s", attempt, c.retry.MaxAttempts, method, path, err, wait)
+		if sleepErr := sleep(ctx, wait); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// doOnce executa uma única tentativa da requisição
+func (c *Client) doOnce(ctx context.Context, method, path string, jsonData []byte, out interface{}, okStatus []int) error {
+	var reader io.Reader
+	if jsonData != nil {
+		reader = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		c.logger.Printf("Erro ao criar a requisição: %v", err)
+		return fmt.Errorf("erro ao criar a requisição: %w", err)
+	}
+
+	// Adiciona os cabeçalhos necessários
+	if jsonData != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("access-token", c.token)
+
+	// Executa a requisição
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Printf("Erro ao realizar a requisição %s %s: %v", method, path, err)
+		return fmt.Errorf("erro ao realizar a requisição: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Printf("Erro ao ler o corpo da resposta: %v", err)
+		return fmt.Errorf("erro ao ler o corpo da resposta: %w", err)
+	}
+
+	if !containsStatus(okStatus, resp.StatusCode) {
+		c.logger.Printf("Erro na resposta da API. Status: %s. Resposta: %s", resp.Status, string(respBody))
+		apiErr := newAPIError(resp.StatusCode, resp.Status, respBody)
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return apiErr
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			c.logger.Printf("Erro ao deserializar os dados: %v", err)
+			return fmt.Errorf("erro ao deserializar os dados: %w", err)
+		}
+	}
+	return nil
+}
+
+// containsStatus indica se status está na lista de códigos aceitos
+func containsStatus(accepted []int, status int) bool {
+	for _, s := range accepted {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package pontomais
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// newTestClient cria um cliente apontando para o servidor de teste, com
+// esperas curtas para que as retentativas não atrasem os testes
+func newTestClient(t *testing.T, url string, attempts int) *Client {
+	t.Helper()
+	client, err := NewClient(&models.Config{
+		PontoMaisBaseURL: url,
+		PontoMaisToken:   base64.StdEncoding.EncodeToString([]byte("token")),
+	},
+		WithRetryPolicy(RetryPolicy{MaxAttempts: attempts, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestDoRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		statuses []int // Respostas do servidor, em ordem; a última se repete
+		attempts int
+		wantErr  error
+		wantCall int
+	}{
+		{"sucesso direto", http.MethodGet, []int{200}, 3, nil, 1},
+		{"503 e sucesso", http.MethodGet, []int{503, 200}, 3, nil, 2},
+		{"502 repetido no GET", http.MethodGet, []int{502, 502, 200}, 3, nil, 3},
+		{"502 não repetido no POST", http.MethodPost, []int{502, 200}, 3, ErrServer, 1},
+		{"503 repetido no POST", http.MethodPost, []int{503, 200}, 3, nil, 2},
+		{"tentativas esgotadas", http.MethodGet, []int{503}, 3, ErrServer, 3},
+		{"retentativas desativadas", http.MethodGet, []int{503}, 1, ErrServer, 1},
+		{"4xx não repetido", http.MethodGet, []int{404}, 3, ErrNotFound, 1},
+		{"validação não repetida", http.MethodPut, []int{422}, 3, ErrValidation, 1},
+		{"500 não repetido", http.MethodGet, []int{500}, 3, ErrServer, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(calls.Add(1)) - 1
+				if r.Header.Get("access-token") != "token" {
+					t.Errorf("access-token = %q", r.Header.Get("access-token"))
+				}
+				status := tt.statuses[min(n, len(tt.statuses)-1)]
+				w.WriteHeader(status)
+				if status == 200 {
+					w.Write([]byte(`{"ok": true}`))
+				} else {
+					w.Write([]byte(`{"error": "falha"}`))
+				}
+			}))
+			defer server.Close()
+
+			var out struct {
+				OK bool `json:"ok"`
+			}
+			err := newTestClient(t, server.URL, tt.attempts).do(context.Background(), tt.method, "/teste", nil, &out)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("erro inesperado: %v", err)
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("erro = %v, esperado %v", err, tt.wantErr)
+			case tt.wantErr == nil && !out.OK:
+				t.Errorf("resposta não deserializada")
+			}
+			if got := int(calls.Load()); got != tt.wantCall {
+				t.Errorf("chamadas = %d, esperado %d", got, tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestDoRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	var first time.Time
+	var waited time.Duration
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		waited = time.Since(first)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if err := newTestClient(t, server.URL, 3).do(context.Background(), http.MethodPost, "/teste", nil, nil); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("chamadas = %d, esperado 2", calls.Load())
+	}
+	if waited < 900*time.Millisecond {
+		t.Errorf("a segunda tentativa veio após %s, antes do Retry-After de 1s", waited)
+	}
+}
+
+func TestDoDecodeErrorNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"ok": tru`))
+	}))
+	defer server.Close()
+
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	err := newTestClient(t, server.URL, 3).do(context.Background(), http.MethodGet, "/teste", nil, &out)
+	if err == nil {
+		t.Fatal("esperava erro de deserialização")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("chamadas = %d, esperado 1", calls.Load())
+	}
+}
+
+func TestDoAPIErrorDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"errors": {"date": ["inválida"]}}`))
+	}))
+	defer server.Close()
+
+	err := newTestClient(t, server.URL, 3).do(context.Background(), http.MethodPost, "/teste", map[string]string{"a": "b"}, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("erro = %v, esperado *APIError", err)
+	}
+	if apiErr.StatusCode != 422 || apiErr.Fields["date"][0] != "inválida" {
+		t.Errorf("detalhes = %+v", apiErr)
+	}
+}
+
+func TestDoContextCancelled(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, 5)
+	client.retry.BaseDelay = time.Second
+	client.retry.MaxDelay = time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := client.do(ctx, http.MethodGet, "/teste", nil, nil); !errors.Is(err, ErrServer) {
+		t.Fatalf("erro = %v, esperado a última falha da API", err)
+	}
+	if calls.Load() >= 5 {
+		t.Errorf("o cancelamento não interrompeu as retentativas (%d chamadas)", calls.Load())
+	}
+}
@@ -0,0 +1,120 @@
+package pontomais
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// defaultPerPage é a quantidade de colaboradores pedida por página
+const defaultPerPage = 100
+
+// DefaultEmployeeAttributes são os atributos pedidos quando o filtro não define outros
+var DefaultEmployeeAttributes = []string{
+	"id", "first_name", "last_name", "email", "cpf", "registration_number",
+}
+
+// EmployeeFilter define os filtros da listagem de colaboradores. O valor zero
+// lista os colaboradores ativos ordenados pelo primeiro nome.
+type EmployeeFilter struct {
+	Active        *bool    // nil ou true: ativos; false: inativos
+	TeamID        int      // Filtra por equipe quando diferente de zero
+	CostCenterID  int      // Filtra por centro de custo quando diferente de zero
+	Attributes    []string // Atributos retornados; vazio usa DefaultEmployeeAttributes
+	SortProperty  string   // Campo de ordenação; padrão "first_name"
+	SortDirection string   // "asc" ou "desc"; padrão "asc"
+	PerPage       int      // Tamanho da página; padrão 100
+}
+
+// query monta a query string da página informada
+func (f EmployeeFilter) query(page int) url.Values {
+	q := url.Values{}
+
+	active := true
+	if f.Active != nil {
+		active = *f.Active
+	}
+	q.Set("active", strconv.FormatBool(active))
+
+	if f.TeamID != 0 {
+		q.Set("team_id", strconv.Itoa(f.TeamID))
+	}
+	if f.CostCenterID != 0 {
+		q.Set("cost_center_id", strconv.Itoa(f.CostCenterID))
+	}
+
+	attributes := f.Attributes
+	if len(attributes) == 0 {
+		attributes = DefaultEmployeeAttributes
+	}
+	q.Set("attributes", strings.Join(attributes, ","))
+
+	sortProperty := f.SortProperty
+	if sortProperty == "" {
+		sortProperty = "first_name"
+	}
+	sortDirection := f.SortDirection
+	if sortDirection == "" {
+		sortDirection = "asc"
+	}
+	q.Set("sort_property", sortProperty)
+	q.Set("sort_direction", sortDirection)
+
+	q.Set("page", strconv.Itoa(page))
+	q.Set("per_page", strconv.Itoa(f.perPage()))
+	return q
+}
+
+// perPage devolve o tamanho de página efetivo
+func (f EmployeeFilter) perPage() int {
+	if f.PerPage <= 0 {
+		return defaultPerPage
+	}
+	return f.PerPage
+}
+
+// GetEmployees lista todos os colaboradores que atendem ao filtro, percorrendo
+// todas as páginas da API
+func (c *Client) GetEmployees(ctx context.Context, filter EmployeeFilter) ([]models.Employee, error) {
+	var employees []models.Employee
+	for employee, err := range c.Employees(ctx, filter) {
+		if err != nil {
+			return nil, err
+		}
+		employees = append(employees, employee)
+	}
+	return employees, nil
+}
+
+// Employees percorre os colaboradores que atendem ao filtro página a página,
+// sem carregar o resultado inteiro na memória. A iteração termina no primeiro erro.
+//
+//	for employee, err := range client.Employees(ctx, filter) {
+//		if err != nil { ... }
+//	}
+func (c *Client) Employees(ctx context.Context, filter EmployeeFilter) iter.Seq2[models.Employee, error] {
+	return paginate(filter.perPage(), func(page int) ([]models.Employee, *models.PaginationMeta, error) {
+		result, err := c.getEmployeesPage(ctx, filter, page)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Employees, result.Meta, nil
+	})
+}
+
+// getEmployeesPage busca uma única página de colaboradores
+func (c *Client) getEmployeesPage(ctx context.Context, filter EmployeeFilter, page int) (*models.EmployeesResponse, error) {
+	path := "/employees?" + filter.query(page).Encode()
+
+	// Estrutura para armazenar a resposta
+	var result models.EmployeesResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
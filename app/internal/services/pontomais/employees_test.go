@@ -0,0 +1,137 @@
+package pontomais
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// employeePages responde cada página com os IDs informados e os metadados de
+// meta, quando não for nil; a página fora da lista devolve status
+func employeePages(t *testing.T, pages [][]int, meta *models.PaginationMeta, status int, calls *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil || page < 1 {
+			t.Errorf("página inválida: %q", r.URL.RawQuery)
+			return
+		}
+		if page > len(pages) {
+			w.WriteHeader(status)
+			return
+		}
+		response := models.EmployeesResponse{Meta: meta, Employees: []models.Employee{}}
+		for _, id := range pages[page-1] {
+			response.Employees = append(response.Employees, models.Employee{ID: id, FirstName: fmt.Sprint("Colaborador ", id)})
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func TestGetEmployeesPagination(t *testing.T) {
+	tests := []struct {
+		name     string
+		pages    [][]int
+		meta     *models.PaginationMeta
+		status   int // Resposta das páginas além de pages
+		wantIDs  int
+		wantCall int32
+		wantErr  error
+	}{
+		{"total de páginas", [][]int{{1, 2}, {3, 4}, {5}}, &models.PaginationMeta{TotalPages: 3}, http.StatusNotFound, 5, 3, nil},
+		{"total de registros", [][]int{{1, 2}, {3, 4}}, &models.PaginationMeta{TotalCount: 4}, http.StatusNotFound, 4, 2, nil},
+		{"sem metadados, última página incompleta", [][]int{{1, 2}, {3}}, nil, http.StatusNotFound, 3, 2, nil},
+		{"sem metadados, página vazia", [][]int{{1, 2}, {3, 4}, {}}, nil, http.StatusNotFound, 4, 3, nil},
+		{"nenhum colaborador", [][]int{{}}, nil, http.StatusNotFound, 0, 1, nil},
+		{"erro na segunda página", [][]int{{1, 2}}, nil, http.StatusBadRequest, 0, 2, ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := employeePages(t, tt.pages, tt.meta, tt.status, &calls)
+			defer server.Close()
+
+			employees, err := newTestClient(t, server.URL, 1).GetEmployees(context.Background(), EmployeeFilter{PerPage: 2})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, tt.wantErr)
+			}
+			if len(employees) != tt.wantIDs || calls.Load() != tt.wantCall {
+				t.Errorf("%d colaboradores em %d chamadas, esperado %d em %d", len(employees), calls.Load(), tt.wantIDs, tt.wantCall)
+			}
+			for i, employee := range employees {
+				if employee.ID != i+1 {
+					t.Errorf("colaborador %d com ID %d, fora de ordem", i, employee.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestEmployeesIterator(t *testing.T) {
+	var calls atomic.Int32
+	server := employeePages(t, [][]int{{1, 2}, {3, 4}}, nil, http.StatusInternalServerError, &calls)
+	defer server.Close()
+	client := newTestClient(t, server.URL, 1)
+
+	// Interromper o laço não busca as páginas seguintes
+	for employee, err := range client.Employees(context.Background(), EmployeeFilter{PerPage: 2}) {
+		if err != nil || employee.ID != 1 {
+			t.Fatalf("primeiro item = %v, %v", employee.ID, err)
+		}
+		break
+	}
+	if calls.Load() != 1 {
+		t.Errorf("chamadas após o break = %d, esperado 1", calls.Load())
+	}
+
+	// O erro de uma página chega depois dos itens das anteriores e encerra a iteração
+	calls.Store(0)
+	var ids []int
+	var errs int
+	for employee, err := range client.Employees(context.Background(), EmployeeFilter{PerPage: 2}) {
+		if err != nil {
+			errs++
+			if !errors.Is(err, ErrServer) {
+				t.Errorf("erro = %v, esperado %v", err, ErrServer)
+			}
+			continue
+		}
+		ids = append(ids, employee.ID)
+	}
+	if len(ids) != 4 || errs != 1 || calls.Load() != 3 {
+		t.Errorf("IDs = %v, %d erros em %d chamadas; esperado 4 IDs, 1 erro e 3 chamadas", ids, errs, calls.Load())
+	}
+}
+
+func TestEmployeeFilterQuery(t *testing.T) {
+	inactive := false
+	tests := []struct {
+		name   string
+		filter EmployeeFilter
+		want   map[string]string
+	}{
+		{"padrão", EmployeeFilter{}, map[string]string{
+			"active": "true", "attributes": "id,first_name,last_name,email,cpf,registration_number",
+			"sort_property": "first_name", "sort_direction": "asc", "page": "3", "per_page": "100",
+		}},
+		{"filtros", EmployeeFilter{Active: &inactive, TeamID: 7, CostCenterID: 9, Attributes: []string{"id"}, SortDirection: "desc", PerPage: 50}, map[string]string{
+			"active": "false", "team_id": "7", "cost_center_id": "9", "attributes": "id", "sort_direction": "desc", "per_page": "50",
+		}},
+	}
+	for _, tt := range tests {
+		q := tt.filter.query(3)
+		for key, want := range tt.want {
+			if got := q.Get(key); got != want {
+				t.Errorf("%s: %s = %q, esperado %q", tt.name, key, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package pontomais
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Erros sentinela que classificam as falhas da API. Use errors.Is para testá-los
+// e errors.As com *APIError para obter os detalhes da resposta.
+var (
+	ErrUnauthorized = errors.New("não autorizado pela API do Ponto Mais")
+	ErrForbidden    = errors.New("acesso negado pela API do Ponto Mais")
+	ErrNotFound     = errors.New("registro não encontrado no Ponto Mais")
+	ErrValidation   = errors.New("dados rejeitados pela API do Ponto Mais")
+	ErrRateLimited  = errors.New("limite de requisições da API do Ponto Mais excedido")
+	ErrServer       = errors.New("erro interno na API do Ponto Mais")
+	ErrUnexpected   = errors.New("resposta inesperada da API do Ponto Mais")
+)
+
+// APIError representa uma resposta de erro (não 2xx) da API do Ponto Mais
+type APIError struct {
+	StatusCode int                 // Código HTTP da resposta
+	Status     string              // Linha de status HTTP, ex.: "404 Not Found"
+	Message    string              // Mensagem principal extraída do corpo
+	Fields     map[string][]string // Mensagens de validação por campo, quando houver
+	Body       string              // Corpo bruto da resposta
+	RetryAfter time.Duration       // Espera sugerida pelo cabeçalho Retry-After, se houver
+	kind       error
+}
+
+// Error implementa a interface error
+func (e *APIError) Error() string {
+	reason := e.Reason()
+	if reason == "" {
+		return fmt.Sprintf("erro na resposta da API, status: %s", e.Status)
+	}
+	return fmt.Sprintf("erro na resposta da API, status: %s: %s", e.Status, reason)
+}
+
+// Unwrap devolve o erro sentinela correspondente ao tipo da falha
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// Reason devolve uma descrição legível do motivo informado pela API, juntando a
+// mensagem principal e as mensagens por campo
+func (e *APIError) Reason() string {
+	parts := make([]string, 0, len(e.Fields)+1)
+	if e.Message != "" {
+		parts = append(parts, e.Message)
+	}
+
+	// Ordena os campos para que a mensagem seja estável
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		parts = append(parts, fmt.Sprintf("%s: %s", field, strings.Join(e.Fields[field], ", ")))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// newAPIError cria um *APIError a partir do status e do corpo de uma resposta
+func newAPIError(statusCode int, status string, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Status:     status,
+		Body:       string(body),
+		kind:       classifyStatus(statusCode),
+	}
+	apiErr.Message, apiErr.Fields = parseErrorBody(body)
+	return apiErr
+}
+
+// classifyStatus associa um código HTTP ao erro sentinela correspondente
+func classifyStatus(statusCode int) error {
+	switch {
+	case statusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case statusCode == http.StatusForbidden:
+		return ErrForbidden
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusBadRequest, statusCode == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode >= 500:
+		return ErrServer
+	default:
+		return ErrUnexpected
+	}
+}
+
+// parseErrorBody extrai a mensagem e os erros por campo do corpo JSON de erro.
+// A API não é consistente no formato, então aceitamos as variações conhecidas:
+//
+//	{"error": "mensagem"}
+//	{"error": {"message": "mensagem"}}
+//	{"message": "mensagem"}
+//	{"errors": ["mensagem", ...]}
+//	{"errors": {"campo": ["mensagem", ...]}}
+func parseErrorBody(body []byte) (string, map[string][]string) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		// Corpo não é JSON; usa o texto puro se for curto o suficiente
+		text := strings.TrimSpace(string(body))
+		if len(text) > 200 || strings.HasPrefix(text, "<") {
+			return "", nil
+		}
+		return text, nil
+	}
+
+	var messages []string
+	for _, key := range []string{"error", "message", "mensagem"} {
+		if raw, ok := payload[key]; ok {
+			messages = append(messages, rawMessages(raw)...)
+		}
+	}
+
+	var fields map[string][]string
+	if raw, ok := payload["errors"]; ok {
+		var byField map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &byField); err == nil {
+			fields = make(map[string][]string, len(byField))
+			for field, value := range byField {
+				fields[field] = rawMessages(value)
+			}
+		} else {
+			messages = append(messages, rawMessages(raw)...)
+		}
+	}
+
+	return strings.Join(messages, "; "), fields
+}
+
+// rawMessages converte um valor JSON (texto, lista ou objeto com "message") em mensagens
+func rawMessages(raw json.RawMessage) []string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		if text == "" {
+			return nil
+		}
+		return []string{text}
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		var messages []string
+		for _, item := range list {
+			messages = append(messages, rawMessages(item)...)
+		}
+		return messages
+	}
+
+	var object struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(raw, &object); err == nil && object.Message != "" {
+		return []string{object.Message}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package pontomais
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseErrorBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		message string
+		fields  map[string][]string
+	}{
+		{"error texto", `{"error": "token inválido"}`, "token inválido", nil},
+		{"error objeto", `{"error": {"message": "sem permissão"}}`, "sem permissão", nil},
+		{"message", `{"message": "não encontrado"}`, "não encontrado", nil},
+		{"errors lista", `{"errors": ["data inválida", "quantidade inválida"]}`, "data inválida; quantidade inválida", nil},
+		{"errors por campo", `{"errors": {"date": ["inválida"], "amount": ["obrigatório", "positivo"]}}`, "",
+			map[string][]string{"date": {"inválida"}, "amount": {"obrigatório", "positivo"}}},
+		{"mensagem e campos", `{"message": "dados inválidos", "errors": {"date": ["inválida"]}}`, "dados inválidos",
+			map[string][]string{"date": {"inválida"}}},
+		{"texto puro", "Service Unavailable", "Service Unavailable", nil},
+		{"html", "<html><body>502</body></html>", "", nil},
+		{"texto longo", strings.Repeat("x", 201), "", nil},
+		{"json vazio", `{}`, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, fields := parseErrorBody([]byte(tt.body))
+			if message != tt.message {
+				t.Errorf("mensagem = %q, esperado %q", message, tt.message)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("campos = %v, esperado %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestClassifyStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{400, ErrValidation},
+		{401, ErrUnauthorized},
+		{403, ErrForbidden},
+		{404, ErrNotFound},
+		{409, ErrUnexpected},
+		{422, ErrValidation},
+		{429, ErrRateLimited},
+		{500, ErrServer},
+		{503, ErrServer},
+	}
+	for _, tt := range tests {
+		err := newAPIError(tt.status, "", nil)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("status %d classificado como %v, esperado %v", tt.status, err.kind, tt.want)
+		}
+	}
+}
+
+func TestAPIErrorReason(t *testing.T) {
+	err := newAPIError(422, "422 Unprocessable Entity", []byte(`{"message": "dados inválidos", "errors": {"date": ["inválida"], "amount": ["obrigatório"]}}`))
+	want := "dados inválidos; amount: obrigatório; date: inválida"
+	if got := err.Reason(); got != want {
+		t.Errorf("Reason() = %q, esperado %q", got, want)
+	}
+	if !strings.Contains(err.Error(), "422 Unprocessable Entity") {
+		t.Errorf("Error() sem o status: %q", err.Error())
+	}
+}
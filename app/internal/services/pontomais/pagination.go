@@ -0,0 +1,35 @@
+package pontomais
+
+import (
+	"iter"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// pageFetcher busca uma página de resultados e seus metadados de paginação
+type pageFetcher[T any] func(page int) ([]T, *models.PaginationMeta, error)
+
+// paginate transforma um pageFetcher em um iterador que percorre todas as
+// páginas sob demanda. A iteração termina no primeiro erro.
+func paginate[T any](perPage int, fetch pageFetcher[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page := 1; ; page++ {
+			items, meta, err := fetch(page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if !meta.HasNextPage(page, perPage, len(items)) {
+				return
+			}
+		}
+	}
+}
@@ -1,189 +1,267 @@
-package services
+package pontomais
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
+	"iter"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/jeffemart/PontoGo/app/internal/models"
-	"github.com/jeffemart/PontoGo/app/internal/utils"
 )
 
-// GetEmployees faz a requisição à API do Ponto Mais para listar colaboradores
-func GetEmployees(cfg *models.Config) ([]models.Employee, error) {
-	if cfg.PontoMaisBaseURL == "" || cfg.PontoMaisToken == "" {
-		utils.Logger.Println("Erro: Variáveis de ambiente PONTOMAIS_TOKEN ou PONTOMAIS_BASE_URL não estão definidas.")
-		return nil, fmt.Errorf("variáveis de ambiente não definidas corretamente")
-	}
+// TimeBalanceEntryFilter define os filtros da listagem de lançamentos do banco de horas
+type TimeBalanceEntryFilter struct {
+	EmployeeID int       // Filtra por colaborador quando diferente de zero
+	StartDate  time.Time // Data inicial (inclusiva); zero não filtra
+	EndDate    time.Time // Data final (inclusiva); zero não filtra
+	PerPage    int       // Tamanho da página; padrão 100
+}
 
-	// Decodifica o token do Ponto Mais usando a função utilitária
-	decodedToken, err := utils.DecodeBase64(cfg.PontoMaisToken)
-	if err != nil {
-		utils.Logger.Printf("Erro ao decodificar o token: %v", err)
-		return nil, err
+// query monta a query string da página informada
+func (f TimeBalanceEntryFilter) query(page int) url.Values {
+	q := url.Values{}
+	if f.EmployeeID != 0 {
+		q.Set("employee_id", strconv.Itoa(f.EmployeeID))
 	}
+	if !f.StartDate.IsZero() {
+		q.Set("start_date", f.StartDate.Format("2006-01-02"))
+	}
+	if !f.EndDate.IsZero() {
+		q.Set("end_date", f.EndDate.Format("2006-01-02"))
+	}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("per_page", strconv.Itoa(f.perPage()))
+	return q
+}
 
-	// Monta a URL correta utilizando cfg.PontoMaisBaseURL
-	url := fmt.Sprintf("%s/employees?active=true&attributes=id,first_name,last_name,email,cpf,registration_number&sort_direction=asc&sort_property=first_name", cfg.PontoMaisBaseURL)
-
-	// Cria a requisição HTTP
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		utils.Logger.Printf("Erro ao criar a requisição: %v", err)
-		return nil, err
+// perPage devolve o tamanho de página efetivo
+func (f TimeBalanceEntryFilter) perPage() int {
+	if f.PerPage <= 0 {
+		return defaultPerPage
 	}
+	return f.PerPage
+}
 
-	// Adiciona o cabeçalho com o token de autenticação decodificado
-	req.Header.Add("access-token", decodedToken)
+// TimeBalanceEntries percorre os lançamentos do banco de horas que atendem ao
+// filtro, página a página. A iteração termina no primeiro erro.
+func (c *Client) TimeBalanceEntries(ctx context.Context, filter TimeBalanceEntryFilter) iter.Seq2[models.TimeBalanceEntryRecord, error] {
+	return paginate(filter.perPage(), func(page int) ([]models.TimeBalanceEntryRecord, *models.PaginationMeta, error) {
+		path := "/time_balance_entries?" + filter.query(page).Encode()
+
+		var result models.TimeBalanceEntriesResponse
+		if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+			return nil, nil, err
+		}
+		for i := range result.TimeBalanceEntries {
+			normalizeRecord(&result.TimeBalanceEntries[i])
+		}
+		return result.TimeBalanceEntries, result.Meta, nil
+	})
+}
 
-	// Executa a requisição
-	resp, err := client.Do(req)
-	if err != nil {
-		utils.Logger.Printf("Erro ao realizar a requisição: %v", err)
-		return nil, err
+// ListTimeBalanceEntries lista todos os lançamentos do banco de horas que atendem ao filtro
+func (c *Client) ListTimeBalanceEntries(ctx context.Context, filter TimeBalanceEntryFilter) ([]models.TimeBalanceEntryRecord, error) {
+	var entries []models.TimeBalanceEntryRecord
+	for entry, err := range c.TimeBalanceEntries(ctx, filter) {
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
 	}
-	defer resp.Body.Close()
+	return entries, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body) // Lê o corpo da resposta para debugging
-		utils.Logger.Printf("Erro na resposta da API. Status: %s. Resposta: %s", resp.Status, string(body))
-		return nil, fmt.Errorf("erro na resposta da API, status: %s", resp.Status)
-	}
+// GetTimeBalanceEntry busca um lançamento do banco de horas pelo ID
+func (c *Client) GetTimeBalanceEntry(ctx context.Context, entryID string) (*models.TimeBalanceEntryRecord, error) {
+	path := fmt.Sprintf("/time_balance_entries/%s", url.PathEscape(entryID))
 
-	// Lê e processa o corpo da resposta
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		utils.Logger.Printf("Erro ao ler o corpo da resposta: %v", err)
+	var result models.TimeBalanceEntryResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
 		return nil, err
 	}
+	normalizeRecord(&result.TimeBalanceEntry)
+	return &result.TimeBalanceEntry, nil
+}
 
-	// Estrutura para armazenar a resposta
-	var result models.EmployeesResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		utils.Logger.Printf("Erro ao deserializar os dados: %v", err)
+// GetTimeBalanceHistory lista os lançamentos de um colaborador no período em
+// ordem cronológica, cada um com o saldo acumulado do período até ele
+func (c *Client) GetTimeBalanceHistory(ctx context.Context, employeeID int, start, end time.Time) ([]models.TimeBalanceHistoryItem, error) {
+	entries, err := c.ListTimeBalanceEntries(ctx, TimeBalanceEntryFilter{
+		EmployeeID: employeeID,
+		StartDate:  start,
+		EndDate:    end,
+	})
+	if err != nil {
 		return nil, err
 	}
-
-	return result.Employees, nil
+	return RunningBalance(entries), nil
 }
 
-// UpdateTimeBalanceEntry atualiza o banco de horas de um funcionário
-func UpdateTimeBalanceEntry(cfg *models.Config, entryID string, entry models.TimeBalanceEntry) error {
-	if cfg.PontoMaisBaseURL == "" || cfg.PontoMaisToken == "" {
-		utils.Logger.Println("Erro: Variáveis de ambiente PONTOMAIS_TOKEN ou PONTOMAIS_BASE_URL não estão definidas.")
-		return fmt.Errorf("variáveis de ambiente não definidas corretamente")
+// RunningBalance ordena os lançamentos por data (e ID, em caso de empate) e
+// calcula o saldo acumulado em segundos após cada um
+func RunningBalance(entries []models.TimeBalanceEntryRecord) []models.TimeBalanceHistoryItem {
+	sorted := make([]models.TimeBalanceEntryRecord, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		di, _ := sorted[i].ParsedDate()
+		dj, _ := sorted[j].ParsedDate()
+		if !di.Equal(dj) {
+			return di.Before(dj)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	history := make([]models.TimeBalanceHistoryItem, 0, len(sorted))
+	var balance float64
+	for _, entry := range sorted {
+		balance += entry.SignedAmount()
+		history = append(history, models.TimeBalanceHistoryItem{Entry: entry, Balance: balance})
 	}
+	return history
+}
 
-	// Decodifica o token do Ponto Mais usando a função utilitária
-	decodedToken, err := utils.DecodeBase64(cfg.PontoMaisToken)
+// GetTimeBalanceStatement monta o extrato do colaborador entre start e end
+// (inclusivos): o saldo anterior ao período e os lançamentos do período, cada
+// um com o saldo total após ele
+func (c *Client) GetTimeBalanceStatement(ctx context.Context, employeeID int, start, end time.Time) (*models.TimeBalanceStatement, error) {
+	entries, err := c.ListTimeBalanceEntries(ctx, TimeBalanceEntryFilter{EmployeeID: employeeID, EndDate: end})
 	if err != nil {
-		utils.Logger.Printf("Erro ao decodificar o token: %v", err)
-		return err
+		return nil, err
 	}
+	statement := BuildStatement(entries, start, end)
+	statement.EmployeeID = employeeID
+	return &statement, nil
+}
 
-	// Monta a URL para a requisição
-	url := fmt.Sprintf("%s/time_balance_entries/%s", cfg.PontoMaisBaseURL, entryID)
-
-	// Prepara o corpo da requisição
-	requestBody := map[string]models.TimeBalanceEntry{
-		"time_balance_entry": entry,
+// BuildStatement calcula o extrato do período a partir dos lançamentos. Só as
+// datas de start e end são consideradas, sem o horário e o fuso. Lançamentos
+// com data inválida entram no saldo anterior.
+func BuildStatement(entries []models.TimeBalanceEntryRecord, start, end time.Time) models.TimeBalanceStatement {
+	start = dateOnly(start)
+	end = dateOnly(end)
+	statement := models.TimeBalanceStatement{Start: start, End: end}
+	for _, item := range RunningBalance(entries) {
+		date, err := item.Entry.ParsedDate()
+		switch {
+		case err != nil || date.Before(start):
+			statement.OpeningBalance = item.Balance
+		case date.After(end):
+			continue
+		default:
+			if item.Entry.Withdraw {
+				statement.Debits += item.Entry.Amount
+			} else {
+				statement.Credits += item.Entry.Amount
+			}
+			statement.Items = append(statement.Items, item)
+		}
 	}
+	return statement
+}
 
-	jsonData, err := json.Marshal(requestBody)
-	if err != nil {
-		utils.Logger.Printf("Erro ao serializar os dados: %v", err)
-		return err
-	}
+// dateOnly devolve a data à meia-noite em UTC, como as datas dos lançamentos
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
 
-	// Cria a requisição HTTP
-	client := &http.Client{}
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+// GetTimeBalanceSummary resume o banco de horas do colaborador: o saldo de
+// todos os lançamentos, os créditos e débitos entre start e end (inclusivos) e
+// os recent lançamentos mais recentes
+func (c *Client) GetTimeBalanceSummary(ctx context.Context, employeeID int, start, end time.Time, recent int) (*models.TimeBalanceSummary, error) {
+	entries, err := c.ListTimeBalanceEntries(ctx, TimeBalanceEntryFilter{EmployeeID: employeeID})
 	if err != nil {
-		utils.Logger.Printf("Erro ao criar a requisição: %v", err)
-		return err
+		return nil, err
 	}
+	summary := SummarizeTimeBalance(entries, start, end, recent)
+	summary.EmployeeID = employeeID
+	return &summary, nil
+}
 
-	// Adiciona os cabeçalhos necessários
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("access-token", decodedToken)
-
-	// Executa a requisição
-	resp, err := client.Do(req)
-	if err != nil {
-		utils.Logger.Printf("Erro ao realizar a requisição: %v", err)
-		return err
+// SummarizeTimeBalance calcula o resumo do banco de horas a partir dos
+// lançamentos. Como no BuildStatement, só as datas de start e end são
+// consideradas. Lançamentos com data inválida entram no saldo, mas não no período.
+func SummarizeTimeBalance(entries []models.TimeBalanceEntryRecord, start, end time.Time, recent int) models.TimeBalanceSummary {
+	start = dateOnly(start)
+	end = dateOnly(end)
+	summary := models.TimeBalanceSummary{PeriodStart: start, PeriodEnd: end, Entries: len(entries)}
+	history := RunningBalance(entries)
+	for _, item := range history {
+		date, err := item.Entry.ParsedDate()
+		if err != nil || date.Before(start) || date.After(end) {
+			continue
+		}
+		if item.Entry.Withdraw {
+			summary.PeriodDebits += item.Entry.Amount
+		} else {
+			summary.PeriodCredits += item.Entry.Amount
+		}
 	}
-	defer resp.Body.Close()
-
-	// Verifica o status da resposta
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		utils.Logger.Printf("Erro na resposta da API. Status: %s. Resposta: %s", resp.Status, string(body))
-		return fmt.Errorf("erro na resposta da API, status: %s", resp.Status)
+	if len(history) > 0 {
+		summary.Balance = history[len(history)-1].Balance
 	}
-
-	utils.Logger.Printf("Banco de horas atualizado com sucesso para o ID: %s", entryID)
-	return nil
+	for i := len(history) - 1; i >= 0 && len(summary.Recent) < recent; i-- {
+		summary.Recent = append(summary.Recent, history[i].Entry)
+	}
+	return summary
 }
 
-// CreateTimeBalanceEntry cria um novo lançamento no banco de horas de um funcionário
-func CreateTimeBalanceEntry(cfg *models.Config, entry models.TimeBalanceEntry) error {
-	if cfg.PontoMaisBaseURL == "" || cfg.PontoMaisToken == "" {
-		utils.Logger.Println("Erro: Variáveis de ambiente PONTOMAIS_TOKEN ou PONTOMAIS_BASE_URL não estão definidas.")
-		return fmt.Errorf("variáveis de ambiente não definidas corretamente")
+// UpdateTimeBalanceEntry atualiza o banco de horas de um funcionário e devolve o lançamento atualizado
+func (c *Client) UpdateTimeBalanceEntry(ctx context.Context, entryID string, entry models.TimeBalanceEntry) (*models.TimeBalanceEntryRecord, error) {
+	path := fmt.Sprintf("/time_balance_entries/%s", url.PathEscape(entryID))
+
+	// Prepara o corpo da requisição
+	requestBody := map[string]models.TimeBalanceEntry{
+		"time_balance_entry": entry,
 	}
 
-	// Decodifica o token do Ponto Mais usando a função utilitária
-	decodedToken, err := utils.DecodeBase64(cfg.PontoMaisToken)
-	if err != nil {
-		utils.Logger.Printf("Erro ao decodificar o token: %v", err)
-		return err
+	var result models.TimeBalanceEntryResponse
+	if err := c.do(ctx, http.MethodPut, path, requestBody, &result); err != nil {
+		return nil, err
 	}
+	normalizeRecord(&result.TimeBalanceEntry)
 
-	// Monta a URL para a requisição
-	url := fmt.Sprintf("%s/time_balance_entries", cfg.PontoMaisBaseURL)
+	c.logger.Printf("Banco de horas atualizado com sucesso para o ID: %s", entryID)
+	return &result.TimeBalanceEntry, nil
+}
 
+// CreateTimeBalanceEntry cria um novo lançamento no banco de horas de um
+// funcionário e devolve o lançamento criado, com o ID atribuído pela API
+func (c *Client) CreateTimeBalanceEntry(ctx context.Context, entry models.TimeBalanceEntry) (*models.TimeBalanceEntryRecord, error) {
 	// Prepara o corpo da requisição
 	requestBody := map[string]models.TimeBalanceEntry{
 		"time_balance_entry": entry,
 	}
 
-	jsonData, err := json.Marshal(requestBody)
-	if err != nil {
-		utils.Logger.Printf("Erro ao serializar os dados: %v", err)
-		return err
+	var result models.TimeBalanceEntryResponse
+	if err := c.do(ctx, http.MethodPost, "/time_balance_entries", requestBody, &result, http.StatusCreated, http.StatusOK); err != nil {
+		return nil, err
 	}
+	normalizeRecord(&result.TimeBalanceEntry)
 
-	// Cria a requisição HTTP
-	client := &http.Client{}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		utils.Logger.Printf("Erro ao criar a requisição: %v", err)
-		return err
-	}
+	c.logger.Printf("Lançamento no banco de horas criado com sucesso (ID: %d)", result.TimeBalanceEntry.ID)
+	return &result.TimeBalanceEntry, nil
+}
 
-	// Adiciona os cabeçalhos necessários
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("access-token", decodedToken)
+// DeleteTimeBalanceEntry exclui um lançamento do banco de horas
+func (c *Client) DeleteTimeBalanceEntry(ctx context.Context, entryID string) error {
+	path := fmt.Sprintf("/time_balance_entries/%s", url.PathEscape(entryID))
 
-	// Executa a requisição
-	resp, err := client.Do(req)
-	if err != nil {
-		utils.Logger.Printf("Erro ao realizar a requisição: %v", err)
+	if err := c.do(ctx, http.MethodDelete, path, nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	// Verifica o status da resposta
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		utils.Logger.Printf("Erro na resposta da API. Status: %s. Resposta: %s", resp.Status, string(body))
-		return fmt.Errorf("erro na resposta da API, status: %s", resp.Status)
-	}
 
-	utils.Logger.Printf("Lançamento no banco de horas criado com sucesso")
+	c.logger.Printf("Lançamento %s excluído do banco de horas", entryID)
 	return nil
 }
+
+// normalizeRecord preenche EmployeeID a partir do colaborador aninhado, quando
+// a API devolve apenas o objeto "employee"
+func normalizeRecord(record *models.TimeBalanceEntryRecord) {
+	if record.EmployeeID == 0 && record.Employee != nil {
+		record.EmployeeID = record.Employee.ID
+	}
+}
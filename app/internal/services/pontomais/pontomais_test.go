@@ -0,0 +1,162 @@
+package pontomais
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+func TestRunningBalance(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  []models.TimeBalanceEntryRecord
+		ids      []int
+		balances []float64
+	}{
+		{"vazio", nil, []int{}, []float64{}},
+		{
+			"crédito e débito",
+			[]models.TimeBalanceEntryRecord{
+				{ID: 1, Date: "2025-03-01", Amount: 7200},
+				{ID: 2, Date: "2025-03-02", Amount: 1800, Withdraw: true},
+			},
+			[]int{1, 2}, []float64{7200, 5400},
+		},
+		{
+			"ordena por data",
+			[]models.TimeBalanceEntryRecord{
+				{ID: 1, Date: "2025-03-10", Amount: 600},
+				{ID: 2, Date: "01/03/2025", Amount: 3600, Withdraw: true},
+				{ID: 3, Date: "2025-03-05T00:00:00Z", Amount: 1200},
+			},
+			[]int{2, 3, 1}, []float64{-3600, -2400, -1800},
+		},
+		{
+			"empate de data pelo ID",
+			[]models.TimeBalanceEntryRecord{
+				{ID: 9, Date: "2025-03-01", Amount: 60},
+				{ID: 4, Date: "2025-03-01", Amount: 120, Withdraw: true},
+			},
+			[]int{4, 9}, []float64{-120, -60},
+		},
+	}
+	for _, tt := range tests {
+		history := RunningBalance(tt.entries)
+		ids := []int{}
+		balances := []float64{}
+		for _, item := range history {
+			ids = append(ids, item.Entry.ID)
+			balances = append(balances, item.Balance)
+		}
+		if !reflect.DeepEqual(ids, tt.ids) || !reflect.DeepEqual(balances, tt.balances) {
+			t.Errorf("%s: IDs = %v, saldos = %v; esperado %v e %v", tt.name, ids, balances, tt.ids, tt.balances)
+		}
+	}
+}
+
+func TestGetTimeBalanceHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("employee_id") != "42" || q.Get("start_date") != "2025-03-01" || q.Get("end_date") != "2025-03-31" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"time_balance_entries": [
+			{"id": 2, "employee": {"id": 42}, "date": "2025-03-31", "amount": 600, "withdraw": true},
+			{"id": 1, "employee_id": 42, "date": "2025-03-01", "amount": 3600}
+		]}`))
+	}))
+	defer server.Close()
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	history, err := newTestClient(t, server.URL, 1).GetTimeBalanceHistory(context.Background(), 42, start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].Entry.ID != 1 || history[1].Balance != 3000 {
+		t.Fatalf("histórico = %+v", history)
+	}
+	if history[1].Entry.EmployeeID != 42 {
+		t.Errorf("EmployeeID = %d, esperado 42 a partir do colaborador aninhado", history[1].Entry.EmployeeID)
+	}
+}
+
+func TestSummarizeTimeBalance(t *testing.T) {
+	entries := []models.TimeBalanceEntryRecord{
+		{ID: 1, Date: "2025-02-28", Amount: 3600},
+		{ID: 2, Date: "2025-03-01", Amount: 7200},
+		{ID: 3, Date: "01/03/2025", Amount: 1800, Withdraw: true},
+		{ID: 4, Date: "2025-03-31", Amount: 600},
+		{ID: 5, Date: "2025-04-01", Amount: 900},
+		{ID: 6, Date: "data inválida", Amount: 60},
+	}
+	// O período local (meia-noite em Brasília) inclui os lançamentos do
+	// primeiro e do último dia, cujas datas vêm da API sem fuso
+	brt := time.FixedZone("BRT", -3*3600)
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, brt)
+	end := time.Date(2025, 3, 31, 0, 0, 0, 0, brt)
+
+	summary := SummarizeTimeBalance(entries, start, end, 2)
+	if summary.PeriodCredits != 7800 || summary.PeriodDebits != 1800 {
+		t.Errorf("créditos = %v, débitos = %v; esperado 7800 e 1800", summary.PeriodCredits, summary.PeriodDebits)
+	}
+	if summary.Balance != 3600+7200-1800+600+900+60 || summary.Entries != len(entries) {
+		t.Errorf("saldo = %v com %d lançamentos", summary.Balance, summary.Entries)
+	}
+	if len(summary.Recent) != 2 || summary.Recent[0].ID != 5 {
+		t.Errorf("recentes = %+v", summary.Recent)
+	}
+
+	statement := BuildStatement(entries, start, end)
+	if statement.Credits != summary.PeriodCredits || statement.Debits != summary.PeriodDebits || len(statement.Items) != 3 {
+		t.Errorf("extrato = %+v, diferente do resumo", statement)
+	}
+}
+
+func TestBuildStatement(t *testing.T) {
+	entries := []models.TimeBalanceEntryRecord{
+		{ID: 4, Date: "2025-03-31", Amount: 600},
+		{ID: 1, Date: "2025-02-28", Amount: 3600},
+		{ID: 3, Date: "2025-03-15", Amount: 1800, Withdraw: true},
+		{ID: 2, Date: "2025-03-01", Amount: 7200},
+		{ID: 5, Date: "2025-04-01", Amount: 900},
+	}
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC) }
+	brt := time.FixedZone("BRT", -3*3600)
+
+	tests := []struct {
+		name                     string
+		start, end               time.Time
+		opening, credits, debits float64
+		closing                  float64
+		ids                      []int
+	}{
+		{"mês com os dois limites", day(3, 1), day(3, 31), 3600, 7800, 1800, 9600, []int{2, 3, 4}},
+		{"um dia com débito", day(3, 15), day(3, 15), 10800, 0, 1800, 9000, []int{3}},
+		{"antes do primeiro lançamento", day(1, 1), day(1, 31), 0, 0, 0, 0, nil},
+		{"depois do último lançamento", day(5, 1), day(5, 31), 10500, 0, 0, 10500, nil},
+		{"horário e fuso ignorados", time.Date(2025, 3, 1, 23, 0, 0, 0, brt), time.Date(2025, 3, 31, 23, 59, 0, 0, brt), 3600, 7800, 1800, 9600, []int{2, 3, 4}},
+	}
+	for _, tt := range tests {
+		statement := BuildStatement(entries, tt.start, tt.end)
+		if statement.OpeningBalance != tt.opening || statement.Credits != tt.credits || statement.Debits != tt.debits || statement.ClosingBalance() != tt.closing {
+			t.Errorf("%s: saldo anterior %v, créditos %v, débitos %v, saldo final %v; esperado %v, %v, %v, %v", tt.name,
+				statement.OpeningBalance, statement.Credits, statement.Debits, statement.ClosingBalance(), tt.opening, tt.credits, tt.debits, tt.closing)
+		}
+		var ids []int
+		for _, item := range statement.Items {
+			ids = append(ids, item.Entry.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.ids) {
+			t.Errorf("%s: lançamentos = %v, esperado %v", tt.name, ids, tt.ids)
+		}
+		if n := len(statement.Items); n > 0 && statement.Items[n-1].Balance != tt.closing {
+			t.Errorf("%s: saldo após o último lançamento = %v, esperado %v", tt.name, statement.Items[n-1].Balance, tt.closing)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package pontomais
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Valores padrão do limite de requisições à API
+const (
+	defaultRateLimit = 5.0 // Requisições por segundo
+	defaultRateBurst = 5
+)
+
+// WithRateLimit substitui o limite de requisições derivado da configuração.
+// rps é a taxa sustentada em requisições por segundo e burst a quantidade que
+// pode ser feita de uma vez após um período ocioso.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(rps, burst)
+	}
+}
+
+// rateLimiter é um token bucket compartilhado por todas as requisições do
+// cliente, inclusive as retentativas
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64   // Tokens repostos por segundo
+	burst  float64   // Capacidade do balde
+	tokens float64   // Tokens disponíveis; negativo quando há requisições aguardando
+	last   time.Time // Momento da última reposição; no futuro durante uma pausa imposta pela API
+}
+
+// newRateLimiter cria o limitador, usando os valores padrão para os parâmetros não definidos
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	if rps <= 0 {
+		rps = defaultRateLimit
+	}
+	if burst <= 0 {
+		burst = defaultRateBurst
+	}
+	return &rateLimiter{rate: rps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait reserva um token e espera até que ele esteja disponível ou o contexto
+// seja cancelado. Se o contexto for cancelado antes, o token é devolvido.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	if err := sleep(ctx, delay); err != nil {
+		l.release()
+		return err
+	}
+	return nil
+}
+
+// reserve retira um token e devolve quanto tempo falta, a partir de now, para
+// que ele esteja disponível
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	l.tokens--
+
+	// Durante uma pausa os tokens só voltam a ser repostos no fim dela
+	var delay time.Duration
+	if l.last.After(now) {
+		delay = l.last.Sub(now)
+	}
+	if l.tokens < 0 {
+		delay += time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	return delay
+}
+
+// release devolve um token reservado que não chegou a ser usado
+func (l *rateLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.tokens+1, l.burst)
+}
+
+// pause suspende as próximas requisições por d, quando a API indica que o
+// limite dela foi atingido
+func (l *rateLimiter) pause(d time.Duration) {
+	l.pauseAt(time.Now(), d)
+}
+
+// pauseAt suspende as requisições por d a partir de now. A reposição dos tokens
+// recomeça no fim da pausa com no máximo um token disponível, para que as
+// requisições retomem no ritmo da taxa sustentada em vez de todas de uma vez.
+func (l *rateLimiter) pauseAt(now time.Time, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	if until := now.Add(d); until.After(l.last) {
+		l.last = until
+	}
+	l.tokens = min(l.tokens, 1)
+}
+
+// refill repõe os tokens acumulados desde a última reposição
+func (l *rateLimiter) refill(now time.Time) {
+	if !now.After(l.last) {
+		return
+	}
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.burst)
+	l.last = now
+}
@@ -0,0 +1,81 @@
+package pontomais
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	start := time.Date(2025, 3, 18, 12, 0, 0, 0, time.UTC)
+	ms := time.Millisecond
+
+	tests := []struct {
+		name  string
+		rps   float64
+		burst int
+		pause time.Duration // Pausa imposta em start, antes das reservas
+		at    []time.Duration
+		want  []time.Duration // Espera de cada reserva feita em start+at[i]
+	}{
+		{
+			name: "rajada", rps: 10, burst: 3,
+			at:   []time.Duration{0, 0, 0, 0},
+			want: []time.Duration{0, 0, 0, 100 * ms},
+		},
+		{
+			name: "taxa sustentada", rps: 10, burst: 1,
+			at:   []time.Duration{0, 0, 0, 0},
+			want: []time.Duration{0, 100 * ms, 200 * ms, 300 * ms},
+		},
+		{
+			name: "reposição ao longo do tempo", rps: 10, burst: 2,
+			at:   []time.Duration{0, 0, 0, 250 * ms, 250 * ms, time.Second, time.Second, time.Second},
+			want: []time.Duration{0, 0, 100 * ms, 0, 50 * ms, 0, 0, 100 * ms},
+		},
+		{
+			name: "pausa somada uma única vez", rps: 10, burst: 5, pause: 500 * ms,
+			at:   []time.Duration{0, 0, 0, 200 * ms},
+			want: []time.Duration{500 * ms, 600 * ms, 700 * ms, 600 * ms},
+		},
+		{
+			name: "reserva após o fim da pausa", rps: 10, burst: 5, pause: 500 * ms,
+			at:   []time.Duration{600 * ms, 600 * ms, 600 * ms},
+			want: []time.Duration{0, 0, 100 * ms},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter(tt.rps, tt.burst)
+			l.last = start
+			if tt.pause > 0 {
+				l.pauseAt(start, tt.pause)
+			}
+			for i, at := range tt.at {
+				if got := l.reserve(start.Add(at)); got != tt.want[i] {
+					t.Errorf("reserva %d em +%s: espera = %s, esperado %s", i+1, at, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	l := newRateLimiter(1, 1)
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sem tokens, a espera seria de um segundo; o contexto cancelado interrompe
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("erro = %v, esperado context.Canceled", err)
+	}
+
+	// O token devolvido não atrasa a próxima requisição além da taxa normal
+	if delay := l.reserve(time.Now()); delay > time.Second {
+		t.Errorf("espera após o cancelamento = %s, esperado no máximo 1s", delay)
+	}
+}
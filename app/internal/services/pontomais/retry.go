@@ -0,0 +1,136 @@
+package pontomais
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Valores padrão da política de retentativas
+const (
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 500 * time.Millisecond
+	defaultMaxDelay    = 30 * time.Second
+)
+
+// RetryPolicy controla como o cliente repete requisições que falharam de forma transitória
+type RetryPolicy struct {
+	MaxAttempts int           // Total de tentativas, incluindo a primeira; 1 desativa as retentativas
+	BaseDelay   time.Duration // Espera base do backoff exponencial
+	MaxDelay    time.Duration // Espera máxima entre tentativas
+}
+
+// WithRetryPolicy substitui a política de retentativas derivada da configuração
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy.withDefaults()
+	}
+}
+
+// withDefaults preenche os campos não definidos com os valores padrão
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultMaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaultBaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaultMaxDelay
+	}
+	return p
+}
+
+// backoff calcula a espera antes da próxima tentativa usando backoff
+// exponencial com jitter completo. attempt começa em 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	return rand.N(ceiling) + 1
+}
+
+// shouldRetry decide se uma falha pode ser repetida. Métodos idempotentes são
+// repetidos em falhas de rede e erros 429/502/503/504; POST só é repetido quando
+// há garantia de que a API não processou a requisição (429, 503 ou falha de conexão).
+// Respostas recebidas por completo, mas inválidas (ex.: JSON malformado), não são
+// repetidas, pois a próxima tentativa traria o mesmo conteúdo.
+func shouldRetry(method string, err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return true
+		case http.StatusBadGateway, http.StatusGatewayTimeout:
+			return isIdempotent(method)
+		default:
+			return false
+		}
+	}
+
+	// Cancelamentos e prazos do chamador nunca são repetidos
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	if !isIdempotent(method) {
+		return false
+	}
+
+	// Demais falhas de transporte: erros de rede e respostas cortadas no meio
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// isIdempotent indica se o método HTTP pode ser repetido sem efeitos colaterais
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter interpreta o cabeçalho Retry-After, em segundos ou como data HTTP
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// sleep espera pelo tempo indicado ou até o contexto ser cancelado
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,108 @@
+package pontomais
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// timeoutError simula o tempo esgotado do cliente HTTP
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestShouldRetry(t *testing.T) {
+	dialErr := fmt.Errorf("erro ao realizar a requisição: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")})
+	readErr := fmt.Errorf("erro ao realizar a requisição: %w", &net.OpError{Op: "read", Err: errors.New("connection reset")})
+	timeoutErr := fmt.Errorf("erro ao realizar a requisição: %w", &url.Error{Op: "Get", URL: "/teste", Err: timeoutError{}})
+	bodyErr := fmt.Errorf("erro ao ler o corpo da resposta: %w", io.ErrUnexpectedEOF)
+	decodeErr := fmt.Errorf("erro ao deserializar os dados: %w", json.Unmarshal([]byte("{"), &struct{}{}))
+
+	tests := []struct {
+		name   string
+		method string
+		err    error
+		want   bool
+	}{
+		{"429 no GET", http.MethodGet, newAPIError(429, "429", nil), true},
+		{"429 no POST", http.MethodPost, newAPIError(429, "429", nil), true},
+		{"503 no POST", http.MethodPost, newAPIError(503, "503", nil), true},
+		{"502 no GET", http.MethodGet, newAPIError(502, "502", nil), true},
+		{"502 no POST", http.MethodPost, newAPIError(502, "502", nil), false},
+		{"504 no PUT", http.MethodPut, newAPIError(504, "504", nil), true},
+		{"504 no POST", http.MethodPost, newAPIError(504, "504", nil), false},
+		{"500 no GET", http.MethodGet, newAPIError(500, "500", nil), false},
+		{"400 no GET", http.MethodGet, newAPIError(400, "400", nil), false},
+		{"404 no DELETE", http.MethodDelete, newAPIError(404, "404", nil), false},
+		{"422 no POST", http.MethodPost, newAPIError(422, "422", nil), false},
+		{"cancelamento", http.MethodGet, context.Canceled, false},
+		{"prazo do chamador", http.MethodGet, fmt.Errorf("erro: %w", context.DeadlineExceeded), false},
+		{"falha de conexão no POST", http.MethodPost, dialErr, true},
+		{"falha de leitura no GET", http.MethodGet, readErr, true},
+		{"falha de leitura no POST", http.MethodPost, readErr, false},
+		{"tempo esgotado no GET", http.MethodGet, timeoutErr, true},
+		{"tempo esgotado no POST", http.MethodPost, timeoutErr, false},
+		{"resposta cortada no GET", http.MethodGet, bodyErr, true},
+		{"JSON inválido no GET", http.MethodGet, decodeErr, false},
+		{"erro desconhecido no GET", http.MethodGet, errors.New("falha"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldRetry(tt.method, tt.err); got != tt.want {
+				t.Errorf("shouldRetry(%s, %v) = %v, esperado %v", tt.method, tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{5, time.Second},
+		{80, time.Second}, // O deslocamento estoura e cai no máximo
+	}
+	for _, tt := range tests {
+		for i := 0; i < 50; i++ {
+			if wait := policy.backoff(tt.attempt); wait <= 0 || wait > tt.ceiling {
+				t.Fatalf("backoff(%d) = %s, esperado entre 0 e %s", tt.attempt, wait, tt.ceiling)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"  ", 0},
+		{"5", 5 * time.Second},
+		{" 120 ", 2 * time.Minute},
+		{"-3", 0},
+		{"abc", 0},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, esperado %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package pontomais
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// TimeCardFilter define os filtros da listagem de batidas de ponto
+type TimeCardFilter struct {
+	EmployeeID int       // Filtra por colaborador quando diferente de zero
+	StartDate  time.Time // Data inicial (inclusiva); obrigatória para a API
+	EndDate    time.Time // Data final (inclusiva); obrigatória para a API
+	PerPage    int       // Tamanho da página; padrão 100
+}
+
+// query monta a query string da página informada
+func (f TimeCardFilter) query(page int) url.Values {
+	q := url.Values{}
+	if f.EmployeeID != 0 {
+		q.Set("employee_id", strconv.Itoa(f.EmployeeID))
+	}
+	if !f.StartDate.IsZero() {
+		q.Set("start_date", f.StartDate.Format("2006-01-02"))
+	}
+	if !f.EndDate.IsZero() {
+		q.Set("end_date", f.EndDate.Format("2006-01-02"))
+	}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("per_page", strconv.Itoa(f.perPage()))
+	return q
+}
+
+// perPage devolve o tamanho de página efetivo
+func (f TimeCardFilter) perPage() int {
+	if f.PerPage <= 0 {
+		return defaultPerPage
+	}
+	return f.PerPage
+}
+
+// TimeCards percorre as batidas de ponto que atendem ao filtro, página a
+// página. A iteração termina no primeiro erro.
+func (c *Client) TimeCards(ctx context.Context, filter TimeCardFilter) iter.Seq2[models.TimeCard, error] {
+	return paginate(filter.perPage(), func(page int) ([]models.TimeCard, *models.PaginationMeta, error) {
+		path := "/time_cards?" + filter.query(page).Encode()
+
+		var result models.TimeCardsResponse
+		if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+			return nil, nil, err
+		}
+		for i := range result.TimeCards {
+			card := &result.TimeCards[i]
+			if card.EmployeeID == 0 && card.Employee != nil {
+				card.EmployeeID = card.Employee.ID
+			}
+		}
+		return result.TimeCards, result.Meta, nil
+	})
+}
+
+// ListTimeCards lista todas as batidas de ponto que atendem ao filtro
+func (c *Client) ListTimeCards(ctx context.Context, filter TimeCardFilter) ([]models.TimeCard, error) {
+	var cards []models.TimeCard
+	for card, err := range c.TimeCards(ctx, filter) {
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
@@ -0,0 +1,244 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/scheduler"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// scheduleFilesDir é o subdiretório de DATA_DIR com as planilhas das importações agendadas
+const scheduleFilesDir = "schedules"
+
+// handleSchedule trata o comando /agendar:
+//   - sem argumentos ou "lista": lista os agendamentos
+//   - "remover <número>" e "executar <número>": remove ou executa um agendamento
+//   - "<tarefa> <quando>": agenda a tarefa; a importação pede a planilha em seguida
+func (b *Bot) handleSchedule(message *tgbotapi.Message) {
+	utils.Logger.Printf("Comando /agendar recebido do chat ID: %d", message.Chat.ID)
+	args, ok := b.parseCommand(message, scheduleCommand)
+	if !ok {
+		return
+	}
+
+	chatID := message.Chat.ID
+	task := strings.ToLower(args.String("tarefa"))
+	switch task {
+	case "", "lista", "listar":
+		b.api.Send(tgbotapi.NewMessage(chatID, formatSchedules(b.scheduler.List(), b.scheduler.Tasks())))
+		return
+	case "remover", "executar":
+		id, err := strconv.Atoi(strings.TrimPrefix(args.String("quando"), "#"))
+		if err != nil {
+			b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Informe o número do agendamento, ex.: /agendar %s 3. Use /agendar para ver os agendamentos.", task)))
+			return
+		}
+		if task == "remover" {
+			b.removeSchedule(message, id)
+		} else {
+			b.runSchedule(message, id)
+		}
+		return
+	}
+
+	entry, err := scheduler.NewEntry(task, args.String("quando"), time.Now())
+	if err == nil {
+		entry.ChatID = chatID
+		entry.CreatedBy = userLabel(message.From)
+		err = b.scheduler.Validate(entry)
+	}
+	if err != nil {
+		utils.Logger.Printf("Agendamento recusado no chat ID %d: %v", chatID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Não foi possível agendar: %v.\n\nUse:\n%s\n\nMais detalhes em /help agendar", err, scheduleCommand.Usage())))
+		return
+	}
+
+	// A importação agenda uma planilha, que é pedida antes de criar o agendamento
+	if entry.Task == taskImport {
+		sheet := args.String("aba")
+		if sheet == "" {
+			sheet = b.config.ImportSheet
+		}
+		b.setConversation(chatID, conversation{flow: flowSchedule, step: stepDocument, sheet: sheet, schedule: entry})
+		text := fmt.Sprintf("Envie a planilha a importar (%s) em .xlsx, .ods ou .csv, ou use /cancelar.", describeWhen(entry))
+		if sheet != "" {
+			text += fmt.Sprintf("\nSerá lida a aba \"%s\".", sheet)
+		}
+		b.api.Send(tgbotapi.NewMessage(chatID, text))
+		return
+	}
+
+	entry, err = b.scheduler.Add(entry)
+	if err != nil {
+		utils.Logger.Printf("Erro ao salvar o agendamento: %v", err)
+		b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Erro ao salvar o agendamento: %v", err)))
+		return
+	}
+	utils.Logger.Printf("Agendamento #%d (%s, %s) criado por %s no chat ID: %d", entry.ID, entry.Task, entry.When(), entry.CreatedBy, chatID)
+	b.api.Send(tgbotapi.NewMessage(chatID, scheduleCreatedText(entry)))
+}
+
+// handleScheduleDocument recebe a planilha de uma importação agendada: confere
+// a planilha agora, para que os erros apareçam antes da execução, guarda uma
+// cópia no diretório de dados e cria o agendamento
+func (b *Bot) handleScheduleDocument(message *tgbotapi.Message, conv conversation) {
+	chatID := message.Chat.ID
+	utils.Logger.Printf("Documento recebido do chat ID: %d para o comando: agendar", chatID)
+
+	filePath, ok := b.downloadDocument(message)
+	if !ok {
+		return
+	}
+	defer os.Remove(filePath)
+
+	sheet, warning, err := b.readImport(filePath, conv.sheet)
+	if err != nil {
+		utils.Logger.Printf("Erro ao ler a planilha: %v", err)
+		b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Erro ao ler a planilha: %v", err)))
+		return
+	}
+	summary := sheet.Summarize()
+	text := warning + formatImportSummary(summary)
+	if summary.ValidRows == 0 {
+		b.api.Send(tgbotapi.NewMessage(chatID, text+"\nNenhuma linha nova para lançar. Corrija a planilha e agende novamente com /agendar."))
+		return
+	}
+
+	saved, err := b.saveScheduleFile(filePath, message.Document.FileName)
+	if err != nil {
+		utils.Logger.Printf("Erro ao salvar a planilha agendada: %v", err)
+		b.api.Send(tgbotapi.NewMessage(chatID, "Erro ao salvar a planilha para o agendamento."))
+		return
+	}
+
+	entry := conv.schedule
+	entry.Args = map[string]string{"arquivo": saved, "nome": message.Document.FileName}
+	if conv.sheet != "" {
+		entry.Args["aba"] = conv.sheet
+	}
+	entry, err = b.scheduler.Add(entry)
+	if err != nil {
+		os.Remove(saved)
+		utils.Logger.Printf("Erro ao salvar o agendamento: %v", err)
+		b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Erro ao salvar o agendamento: %v", err)))
+		return
+	}
+	utils.Logger.Printf("Agendamento #%d (%s, %s) de %s criado por %s no chat ID: %d",
+		entry.ID, entry.Task, entry.When(), message.Document.FileName, entry.CreatedBy, chatID)
+	b.api.Send(tgbotapi.NewMessage(chatID, text+"\n"+scheduleCreatedText(entry)))
+}
+
+// saveScheduleFile copia a planilha recebida para o diretório das importações
+// agendadas, mantendo a extensão para a detecção do formato
+func (b *Bot) saveScheduleFile(srcPath, fileName string) (string, error) {
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(b.config.DataDir, scheduleFilesDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	file, err := os.CreateTemp(dir, "importacao-*"+strings.ToLower(filepath.Ext(fileName)))
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// removeSchedule trata o /agendar remover <número>
+func (b *Bot) removeSchedule(message *tgbotapi.Message, id int) {
+	entry, err := b.scheduler.Remove(id)
+	if errors.Is(err, scheduler.ErrNotFound) {
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Agendamento #%d não encontrado. Use /agendar para ver os agendamentos.", id)))
+		return
+	}
+	if err != nil {
+		utils.Logger.Printf("Erro ao remover o agendamento #%d: %v", id, err)
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Não foi possível remover: %v", err)))
+		return
+	}
+	removeScheduleFile(entry)
+	utils.Logger.Printf("Agendamento #%d removido por %s no chat ID: %d", id, userLabel(message.From), message.Chat.ID)
+	b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Agendamento #%d (%s, %s) removido.", entry.ID, entry.Task, entry.When())))
+}
+
+// runSchedule trata o /agendar executar <número>, que executa a tarefa agora,
+// após a tarefa agendada em andamento, sem alterar a próxima execução
+func (b *Bot) runSchedule(message *tgbotapi.Message, id int) {
+	entry, err := b.scheduler.Get(id)
+	if err == nil {
+		err = b.scheduler.RunNow(id)
+	}
+	if err != nil {
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Agendamento #%d não encontrado. Use /agendar para ver os agendamentos.", id)))
+		return
+	}
+	utils.Logger.Printf("Agendamento #%d executado por %s no chat ID: %d", id, userLabel(message.From), message.Chat.ID)
+	target := "a este chat"
+	if chat := b.scheduleChat(entry); chat != message.Chat.ID {
+		target = fmt.Sprintf("ao chat %d", chat)
+	}
+	b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Executando o agendamento #%d (%s), logo após a tarefa agendada em andamento, se houver. O resultado será enviado %s.", id, entry.Task, target)))
+}
+
+// describeWhen descreve quando o agendamento executa
+func describeWhen(entry scheduler.Entry) string {
+	if entry.Once() {
+		return "em " + entry.When()
+	}
+	return "recorrente, cron \"" + entry.When() + "\""
+}
+
+// scheduleCreatedText confirma a criação de um agendamento
+func scheduleCreatedText(entry scheduler.Entry) string {
+	return fmt.Sprintf("Agendamento #%d criado: %s, %s.\nPróxima execução: %s\n\nO resultado de cada execução será enviado a este chat. Use /agendar executar %d para executar agora ou /agendar remover %d para excluí-lo.",
+		entry.ID, entry.Task, describeWhen(entry), formatNextRun(entry), entry.ID, entry.ID)
+}
+
+// formatSchedules lista os agendamentos com a próxima e a última execução
+func formatSchedules(entries []scheduler.Entry, tasks []string) string {
+	var text strings.Builder
+	if len(entries) == 0 {
+		text.WriteString("Nenhum agendamento.\n")
+	} else {
+		text.WriteString(fmt.Sprintf("Agendamentos (%d):\n", len(entries)))
+	}
+	for _, entry := range entries {
+		text.WriteString(fmt.Sprintf("\n#%d %s", entry.ID, entry.Task))
+		if name := entry.Args["nome"]; name != "" {
+			text.WriteString(" de " + name)
+		}
+		text.WriteString(", " + describeWhen(entry))
+		if entry.Source == scheduler.SourceConfig {
+			text.WriteString(" (configuração do bot)")
+		}
+		text.WriteString(fmt.Sprintf("\n  Próxima execução: %s\n", formatNextRun(entry)))
+		if entry.LastRun != nil {
+			result := "ok"
+			if entry.LastError != "" {
+				result = "erro: " + entry.LastError
+			}
+			text.WriteString(fmt.Sprintf("  Última execução: %s - %s\n", entry.LastRun.Format("02/01/2006 15:04"), result))
+		}
+	}
+	text.WriteString(fmt.Sprintf("\nTarefas: %s.\nUse /agendar <tarefa> <quando> para agendar, /agendar executar <número> ou /agendar remover <número>. Detalhes em /help agendar.",
+		strings.Join(tasks, ", ")))
+	return text.String()
+}
@@ -0,0 +1,95 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// balanceRecentEntries é a quantidade de lançamentos recentes mostrada pelo /saldo
+const balanceRecentEntries = 5
+
+// handleBalance trata o comando /saldo <colaborador>
+func (b *Bot) handleBalance(message *tgbotapi.Message) {
+	utils.Logger.Printf("Comando /saldo recebido do chat ID: %d", message.Chat.ID)
+	args, ok := b.parseCommand(message, balanceCommand)
+	if !ok {
+		return
+	}
+
+	employee, ok := b.resolveEmployee(message.Chat.ID, args.String("colaborador"), "saldo")
+	if !ok {
+		return
+	}
+	b.sendBalance(message.Chat.ID, employee)
+}
+
+// handleBalanceCallback trata a escolha do colaborador quando o /saldo é ambíguo
+func (b *Bot) handleBalanceCallback(query *tgbotapi.CallbackQuery, args []string) {
+	employee, _, ok := b.chosenEmployee(query, args)
+	if !ok {
+		return
+	}
+	b.sendBalance(query.Message.Chat.ID, employee)
+}
+
+// sendBalance envia o resumo do banco de horas do colaborador: o saldo atual,
+// os créditos e débitos do mês corrente e os últimos lançamentos
+func (b *Bot) sendBalance(chatID int64, employee models.Employee) {
+	start, end := currentPeriod(time.Now())
+	summary, err := b.client.GetTimeBalanceSummary(b.ctx, employee.ID, start, end, balanceRecentEntries)
+	if err != nil {
+		utils.Logger.Printf("Erro ao consultar o saldo do colaborador %d: %v", employee.ID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Erro ao consultar o saldo: %s", describeError(err))))
+		return
+	}
+	b.api.Send(tgbotapi.NewMessage(chatID, formatBalance(employee, summary)))
+}
+
+// currentPeriod devolve o primeiro e o último dia do mês da data informada, no
+// fuso dela, para que a virada do mês siga o calendário local e não o UTC
+func currentPeriod(now time.Time) (time.Time, time.Time) {
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return start, start.AddDate(0, 1, -1)
+}
+
+// formatBalance descreve o resumo do banco de horas de um colaborador
+func formatBalance(employee models.Employee, summary *models.TimeBalanceSummary) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Banco de horas de %s\n\n", employeeLabel(employee)))
+	text.WriteString(fmt.Sprintf("Saldo atual: %s (%d lançamentos)\n", duration.FormatSigned(summary.Balance), summary.Entries))
+	text.WriteString(fmt.Sprintf("\nPeríodo de %s a %s:\n", summary.PeriodStart.Format("02/01/2006"), summary.PeriodEnd.Format("02/01/2006")))
+	text.WriteString(fmt.Sprintf("Créditos: %s\n", duration.FormatSigned(summary.PeriodCredits)))
+	text.WriteString(fmt.Sprintf("Débitos: %s\n", duration.FormatSigned(-summary.PeriodDebits)))
+	text.WriteString(fmt.Sprintf("Saldo do período: %s\n", duration.FormatSigned(summary.PeriodCredits-summary.PeriodDebits)))
+
+	if len(summary.Recent) == 0 {
+		text.WriteString("\nNenhum lançamento no banco de horas.")
+		return text.String()
+	}
+	text.WriteString("\nÚltimos lançamentos:\n")
+	for _, entry := range summary.Recent {
+		text.WriteString(formatEntryLine(entry) + "\n")
+	}
+	text.WriteString("\nUse o número do lançamento no /editar ou no /excluir.")
+	return text.String()
+}
+
+// formatEntryLine resume um lançamento em uma linha, ex.:
+// "#3833376 - 18/03/2025 - +02:00 - Horas extras"
+func formatEntryLine(entry models.TimeBalanceEntryRecord) string {
+	date := entry.Date
+	if parsed, err := entry.ParsedDate(); err == nil {
+		date = parsed.Format("02/01/2006")
+	}
+	line := fmt.Sprintf("#%d - %s - %s", entry.ID, date, duration.FormatSigned(entry.SignedAmount()))
+	if observation := strings.TrimSpace(entry.Observation); observation != "" {
+		line += " - " + observation
+	}
+	return line
+}
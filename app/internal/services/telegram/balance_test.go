@@ -0,0 +1,30 @@
+package telegram
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCurrentPeriod(t *testing.T) {
+	brt := time.FixedZone("BRT", -3*3600)
+	tests := []struct {
+		name       string
+		now        time.Time
+		start, end string
+	}{
+		{"meio do mês", time.Date(2025, 3, 18, 12, 0, 0, 0, brt), "2025-03-01", "2025-03-31"},
+		// 22h do dia 31 em Brasília já é dia 1º em UTC, mas ainda é março
+		{"fim do mês à noite", time.Date(2025, 3, 31, 22, 0, 0, 0, brt), "2025-03-01", "2025-03-31"},
+		{"fevereiro bissexto", time.Date(2024, 2, 10, 0, 0, 0, 0, brt), "2024-02-01", "2024-02-29"},
+		{"dezembro", time.Date(2025, 12, 31, 23, 59, 0, 0, time.UTC), "2025-12-01", "2025-12-31"},
+	}
+	for _, tt := range tests {
+		start, end := currentPeriod(tt.now)
+		if start.Format("2006-01-02") != tt.start || end.Format("2006-01-02") != tt.end {
+			t.Errorf("%s: período = %s a %s, esperado %s a %s", tt.name, start.Format("2006-01-02"), end.Format("2006-01-02"), tt.start, tt.end)
+		}
+		if start.Location() != tt.now.Location() {
+			t.Errorf("%s: fuso = %s, esperado %s", tt.name, start.Location(), tt.now.Location())
+		}
+	}
+}
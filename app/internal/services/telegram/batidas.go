@@ -0,0 +1,170 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/services/pontomais"
+	"github.com/jeffemart/PontoGo/app/internal/timecards"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// weekdayNames são os nomes dos dias da semana, a partir de domingo
+var weekdayNames = [...]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"}
+
+// handlePunches trata o comando /batidas <colaborador> [data]
+func (b *Bot) handlePunches(message *tgbotapi.Message) {
+	utils.Logger.Printf("Comando /batidas recebido do chat ID: %d", message.Chat.ID)
+	args, ok := b.parseCommand(message, punchesCommand)
+	if !ok {
+		return
+	}
+
+	date := args.Date("data")
+	employee, ok := b.resolveEmployee(message.Chat.ID, args.String("colaborador"), "batidas", date.Format(historyDateLayout))
+	if !ok {
+		return
+	}
+
+	text, markup, err := b.punchesDay(employee, date)
+	if err != nil {
+		utils.Logger.Printf("Erro ao consultar as batidas do colaborador %d: %v", employee.ID, err)
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Erro ao consultar as batidas: %s", describeError(err))))
+		return
+	}
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg.ReplyMarkup = markup
+	b.api.Send(msg)
+}
+
+// handlePunchesCallback trata os botões do /batidas:
+//   - "colaborador:<ID>:<data>": escolha do colaborador, que envia as batidas
+//   - "dia:<ID>:<data>": navegação para o dia anterior ou o seguinte
+func (b *Bot) handlePunchesCallback(query *tgbotapi.CallbackQuery, args []string) {
+	chatID := query.Message.Chat.ID
+	if len(args) != 3 {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+	date, err := time.Parse(historyDateLayout, args[2])
+	if err != nil {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+
+	if args[0] == "colaborador" {
+		employee, _, ok := b.chosenEmployee(query, args)
+		if !ok {
+			return
+		}
+		text, markup, err := b.punchesDay(employee, date)
+		if err != nil {
+			utils.Logger.Printf("Erro ao consultar as batidas do colaborador %d: %v", employee.ID, err)
+			b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Erro ao consultar as batidas: %s", describeError(err))))
+			return
+		}
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ReplyMarkup = markup
+		b.api.Send(msg)
+		return
+	}
+
+	employeeID, err := strconv.Atoi(args[1])
+	if args[0] != "dia" || err != nil {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+	employee, err := b.employeeByID(employeeID)
+	if err != nil {
+		utils.Logger.Printf("Erro ao buscar o colaborador %d: %v", employeeID, err)
+		b.answerCallback(query, "Colaborador não encontrado.")
+		return
+	}
+	text, markup, err := b.punchesDay(employee, date)
+	if err != nil {
+		utils.Logger.Printf("Erro ao consultar as batidas do colaborador %d: %v", employee.ID, err)
+		b.answerCallback(query, "Erro ao consultar as batidas.")
+		return
+	}
+	b.answerCallback(query, "")
+	b.editMessage(chatID, query.Message.MessageID, text, &markup)
+}
+
+// punchesDay consulta e descreve as batidas do colaborador no dia, com os
+// botões para o dia anterior e o seguinte
+func (b *Bot) punchesDay(employee models.Employee, date time.Time) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	cards, err := b.client.ListTimeCards(b.ctx, pontomais.TimeCardFilter{
+		EmployeeID: employee.ID,
+		StartDate:  date,
+		EndDate:    date,
+	})
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+
+	expected, _ := timecards.ExpectedWork(employee.WorkHours)
+	day := timecards.Analyze(cards, date, expected, timecards.DefaultRules)
+	utils.Logger.Printf("Batidas do colaborador %d em %s: %d batidas, %d ocorrências",
+		employee.ID, date.Format("02/01/2006"), len(day.Punches), len(day.Issues))
+
+	id := strconv.Itoa(employee.ID)
+	markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("« Dia anterior", callbackData("batidas", "dia", id, date.AddDate(0, 0, -1).Format(historyDateLayout))),
+		tgbotapi.NewInlineKeyboardButtonData("Próximo dia »", callbackData("batidas", "dia", id, date.AddDate(0, 0, 1).Format(historyDateLayout))),
+	))
+	return formatPunchesDay(employee, day), markup, nil
+}
+
+// formatPunchesDay descreve as batidas do dia, o total trabalhado, a
+// comparação com a jornada e as ocorrências
+func formatPunchesDay(employee models.Employee, day timecards.Day) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Batidas de %s\n%s, %s\n\n", employeeLabel(employee), weekdayNames[day.Date.Weekday()], day.Date.Format("02/01/2006")))
+
+	if len(day.Punches) == 0 {
+		text.WriteString("Nenhuma batida registrada.\n")
+	}
+	for _, interval := range day.Intervals {
+		text.WriteString(fmt.Sprintf("Entrada %s - Saída %s (%s)\n",
+			interval.Start.Format("15:04"), interval.End.Format("15:04"), duration.Format(interval.End.Sub(interval.Start).Seconds())))
+	}
+	if len(day.Punches)%2 != 0 {
+		text.WriteString(fmt.Sprintf("Entrada %s - sem saída\n", day.Punches[len(day.Punches)-1].Format("15:04")))
+	}
+	if day.Invalid > 0 {
+		text.WriteString(fmt.Sprintf("Batidas ignoradas por data ou hora inválida: %d\n", day.Invalid))
+	}
+
+	text.WriteString("\n")
+	if len(day.Punches) > 0 {
+		text.WriteString(fmt.Sprintf("Trabalhado: %s\n", duration.Format(day.Worked.Seconds())))
+	}
+	switch {
+	case !timecards.DefaultRules.IsWorkday(day.Date):
+		text.WriteString("Jornada: fora dos dias úteis\n")
+	case day.Expected > 0:
+		text.WriteString(fmt.Sprintf("Jornada: %s", duration.Format(day.Expected.Seconds())))
+		// Com batida faltando, o trabalhado é parcial e a diferença não faz sentido
+		if len(day.Punches) > 0 && len(day.Punches)%2 == 0 {
+			text.WriteString(fmt.Sprintf(" (diferença %s)", duration.FormatSigned(day.Difference().Seconds())))
+		}
+		text.WriteString("\n")
+	default:
+		text.WriteString("Jornada: não informada no cadastro\n")
+	}
+
+	if len(day.Issues) == 0 {
+		text.WriteString("\nNenhuma ocorrência.")
+		return text.String()
+	}
+	text.WriteString("\nOcorrências:\n")
+	for _, issue := range day.Issues {
+		text.WriteString(fmt.Sprintf("- %s\n", issue.Message))
+	}
+	return text.String()
+}
@@ -0,0 +1,225 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/services/pontomais"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// Paginação e validade dos resultados do /buscar
+const (
+	searchPageSize = 8
+	searchTTL      = 30 * time.Minute
+)
+
+// employeeSearch guarda os resultados de uma busca para a navegação pelos botões
+type employeeSearch struct {
+	chatID  int64
+	query   string
+	results []models.Employee
+	expires time.Time
+}
+
+// page devolve o total de páginas e os colaboradores da página informada, a partir de 0
+func (s *employeeSearch) page(page int) (int, []models.Employee) {
+	pages := (len(s.results) + searchPageSize - 1) / searchPageSize
+	start := page * searchPageSize
+	if start >= len(s.results) {
+		return pages, nil
+	}
+	return pages, s.results[start:min(start+searchPageSize, len(s.results))]
+}
+
+// find busca um colaborador dos resultados e a página em que ele está
+func (s *employeeSearch) find(employeeID int) (models.Employee, int, bool) {
+	for i, employee := range s.results {
+		if employee.ID == employeeID {
+			return employee, i / searchPageSize, true
+		}
+	}
+	return models.Employee{}, 0, false
+}
+
+// handleSearchEmployees trata o comando /buscar <termo>
+func (b *Bot) handleSearchEmployees(message *tgbotapi.Message) {
+	utils.Logger.Printf("Comando /buscar recebido do chat ID: %d", message.Chat.ID)
+	args, ok := b.parseCommand(message, searchCommand)
+	if !ok {
+		return
+	}
+	query := args.String("termo")
+
+	employees, err := b.client.GetEmployees(b.ctx, pontomais.EmployeeFilter{Attributes: employeeDetailAttributes})
+	if err != nil {
+		utils.Logger.Printf("Erro ao buscar colaboradores: %v", err)
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Erro ao buscar colaboradores: %s", describeError(err))))
+		return
+	}
+
+	results := searchEmployees(employees, query)
+	utils.Logger.Printf("Busca por \"%s\" encontrou %d colaboradores", query, len(results))
+	if len(results) == 0 {
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Nenhum colaborador ativo encontrado para \"%s\".", query)))
+		return
+	}
+
+	search := &employeeSearch{chatID: message.Chat.ID, query: query, results: results}
+	id := b.storeSearch(search)
+	text, markup := searchPage(id, search, 0)
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg.ReplyMarkup = markup
+	b.api.Send(msg)
+}
+
+// storeSearch guarda os resultados de uma busca e devolve o número usado nos botões
+func (b *Bot) storeSearch(search *employeeSearch) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	for id, s := range b.searches {
+		if now.After(s.expires) {
+			delete(b.searches, id)
+		}
+	}
+	search.expires = now.Add(searchTTL)
+	b.nextSearch++
+	b.searches[b.nextSearch] = search
+	return b.nextSearch
+}
+
+// search devolve os resultados de uma busca do chat, se ainda não expiraram
+func (b *Bot) search(chatID int64, id int) (*employeeSearch, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	search, ok := b.searches[id]
+	if !ok || search.chatID != chatID || time.Now().After(search.expires) {
+		return nil, false
+	}
+	return search, true
+}
+
+// searchPage monta o texto e os botões de uma página dos resultados
+func searchPage(id int, search *employeeSearch, page int) (string, tgbotapi.InlineKeyboardMarkup) {
+	pages, employees := search.page(page)
+	text := fmt.Sprintf("Colaboradores encontrados para \"%s\": %d", search.query, len(search.results))
+	if pages > 1 {
+		text += fmt.Sprintf(" (página %d de %d)", page+1, pages)
+	}
+	text += "\n\nToque em um colaborador para ver os detalhes."
+
+	searchID := strconv.Itoa(id)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, employee := range employees {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(employeeLabel(employee), callbackData("buscar", "ver", searchID, strconv.Itoa(employee.ID))),
+		))
+	}
+	if nav := pageButtons("buscar", page, pages, searchID); len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	return text, tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// pageButtons monta os botões "Anterior" e "Próxima" de uma lista paginada. Os
+// botões geram os dados "<action>:pagina:<args...>:<página>", com a página a partir de 0.
+func pageButtons(action string, page, pages int, args ...string) []tgbotapi.InlineKeyboardButton {
+	var buttons []tgbotapi.InlineKeyboardButton
+	data := func(page int) string {
+		return callbackData(action, append(append([]string{"pagina"}, args...), strconv.Itoa(page))...)
+	}
+	if page > 0 {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("« Anterior", data(page-1)))
+	}
+	if page+1 < pages {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("Próxima »", data(page+1)))
+	}
+	return buttons
+}
+
+// handleSearchCallback trata os botões dos resultados do /buscar
+func (b *Bot) handleSearchCallback(query *tgbotapi.CallbackQuery, args []string) {
+	chatID := query.Message.Chat.ID
+	messageID := query.Message.MessageID
+	if len(args) != 3 {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+	id, err1 := strconv.Atoi(args[1])
+	value, err2 := strconv.Atoi(args[2])
+	if err1 != nil || err2 != nil {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+
+	search, ok := b.search(chatID, id)
+	if !ok {
+		b.answerCallback(query, "Esta busca expirou.")
+		b.editMessage(chatID, messageID, "Esta busca expirou. Envie /buscar novamente.", nil)
+		return
+	}
+
+	if args[0] == "pagina" {
+		b.answerCallback(query, "")
+		text, markup := searchPage(id, search, value)
+		b.editMessage(chatID, messageID, text, &markup)
+		return
+	}
+
+	employee, page, ok := search.find(value)
+	if !ok {
+		b.answerCallback(query, "Colaborador não encontrado nesta busca.")
+		return
+	}
+
+	switch args[0] {
+	case "ver":
+		b.answerCallback(query, "")
+		markup := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("Ver saldo", callbackData("buscar", "saldo", args[1], args[2])),
+				tgbotapi.NewInlineKeyboardButtonData("Criar lançamento", callbackData("buscar", "criar", args[1], args[2])),
+			),
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("« Voltar", callbackData("buscar", "pagina", args[1], strconv.Itoa(page))),
+			),
+		)
+		b.editMessage(chatID, messageID, formatEmployee(employee), &markup)
+
+	case "saldo":
+		b.answerCallback(query, "Consultando o saldo...")
+		b.sendBalance(chatID, employee)
+
+	case "criar":
+		b.answerCallback(query, "")
+		b.startCreateWizardFor(chatID, employee)
+
+	default:
+		b.answerCallback(query, "Ação desconhecida.")
+	}
+}
+
+// formatEmployee descreve os dados de um colaborador, omitindo os não preenchidos
+func formatEmployee(employee models.Employee) string {
+	lines := []string{
+		employeeName(employee),
+		fmt.Sprintf("ID: %d", employee.ID),
+	}
+	for _, field := range []struct{ label, value string }{
+		{"E-mail", employee.Email},
+		{"CPF", employee.CPF},
+		{"Matrícula", employee.RegistrationNumber},
+		{"Centro de custo", employee.CostCenter},
+		{"Jornada", employee.WorkHours},
+	} {
+		if strings.TrimSpace(field.value) != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", field.label, field.value))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,71 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// monthNames são os nomes dos meses exibidos no calendário
+var monthNames = [...]string{
+	"Janeiro", "Fevereiro", "Março", "Abril", "Maio", "Junho",
+	"Julho", "Agosto", "Setembro", "Outubro", "Novembro", "Dezembro",
+}
+
+// calendarKeyboard monta um calendário inline do mês informado. Os botões geram
+// os dados "<action>:data:AAAA-MM-DD" ao escolher um dia, "<action>:mes:AAAA-MM"
+// ao navegar entre os meses e "<action>:nada" nos botões apenas informativos.
+func calendarKeyboard(action string, month, today time.Time) tgbotapi.InlineKeyboardMarkup {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	noop := callbackData(action, "nada")
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("«", callbackData(action, "mes", first.AddDate(0, -1, 0).Format("2006-01"))),
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%s %d", monthNames[first.Month()-1], first.Year()), noop),
+			tgbotapi.NewInlineKeyboardButtonData("»", callbackData(action, "mes", first.AddDate(0, 1, 0).Format("2006-01"))),
+		),
+	}
+
+	var header []tgbotapi.InlineKeyboardButton
+	for _, day := range []string{"D", "S", "T", "Q", "Q", "S", "S"} {
+		header = append(header, tgbotapi.NewInlineKeyboardButtonData(day, noop))
+	}
+	rows = append(rows, header)
+
+	// Semanas começando no domingo, com botões vazios antes do dia 1 e depois do último dia
+	week := make([]tgbotapi.InlineKeyboardButton, 0, 7)
+	for i := 0; i < int(first.Weekday()); i++ {
+		week = append(week, tgbotapi.NewInlineKeyboardButtonData(" ", noop))
+	}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		label := strconv.Itoa(day.Day())
+		if sameDay(day, today) {
+			label = "[" + label + "]"
+		}
+		week = append(week, tgbotapi.NewInlineKeyboardButtonData(label, callbackData(action, "data", day.Format("2006-01-02"))))
+		if len(week) == 7 {
+			rows = append(rows, week)
+			week = make([]tgbotapi.InlineKeyboardButton, 0, 7)
+		}
+	}
+	if len(week) > 0 {
+		for len(week) < 7 {
+			week = append(week, tgbotapi.NewInlineKeyboardButtonData(" ", noop))
+		}
+		rows = append(rows, week)
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Hoje", callbackData(action, "data", today.Format("2006-01-02"))),
+		tgbotapi.NewInlineKeyboardButtonData("Ontem", callbackData(action, "data", today.AddDate(0, 0, -1).Format("2006-01-02"))),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// sameDay indica se as duas datas caem no mesmo dia
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month() && a.Day() == b.Day()
+}
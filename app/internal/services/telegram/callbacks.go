@@ -0,0 +1,95 @@
+package telegram
+
+import (
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// callbackSeparator separa a ação e os argumentos nos dados dos botões inline
+const callbackSeparator = ":"
+
+// callbackData monta os dados de um botão inline no formato "ação:arg1:arg2".
+// O Telegram limita esses dados a 64 bytes.
+func callbackData(action string, args ...string) string {
+	return strings.Join(append([]string{action}, args...), callbackSeparator)
+}
+
+// parseCallbackData separa a ação e os argumentos dos dados de um botão inline
+func parseCallbackData(data string) (string, []string) {
+	parts := strings.Split(data, callbackSeparator)
+	return parts[0], parts[1:]
+}
+
+// handleCallback processa os cliques nos botões inline
+func (b *Bot) handleCallback(query *tgbotapi.CallbackQuery) {
+	if query.Message == nil {
+		b.answerCallback(query, "")
+		return
+	}
+
+	// Verifica se o chat está autorizado
+	chatID := query.Message.Chat.ID
+	if !b.hosts[chatID] {
+		utils.Logger.Printf("Tentativa de acesso não autorizado via botão do chat ID: %d", chatID)
+		b.answerCallback(query, "Você não está autorizado a usar este bot.")
+		return
+	}
+
+	action, args := parseCallbackData(query.Data)
+	utils.Logger.Printf("Botão recebido: %s do chat ID: %d", query.Data, chatID)
+
+	switch action {
+	case "excluir":
+		b.handleDeleteCallback(query, args)
+	case "desfazer":
+		b.handleUndoCallback(query, args)
+	case "relatorio":
+		b.handleRelatorioCallback(query, args)
+	case "lancamento":
+		b.handleEntryCallback(query, args)
+	case "listar":
+		b.handleListCallback(query, args)
+	case "buscar":
+		b.handleSearchCallback(query, args)
+	case "saldo":
+		b.handleBalanceCallback(query, args)
+	case "historico":
+		b.handleHistoryCallback(query, args)
+	case "batidas":
+		b.handlePunchesCallback(query, args)
+	case wizardAction:
+		b.handleWizardCallback(query, args)
+	default:
+		utils.Logger.Printf("Ação de botão desconhecida: %s", query.Data)
+		b.answerCallback(query, "Ação desconhecida.")
+	}
+}
+
+// answerCallback confirma o recebimento do clique, opcionalmente exibindo um aviso
+func (b *Bot) answerCallback(query *tgbotapi.CallbackQuery, text string) {
+	if _, err := b.api.AnswerCallbackQuery(tgbotapi.NewCallback(query.ID, text)); err != nil {
+		utils.Logger.Printf("Erro ao responder o botão: %v", err)
+	}
+}
+
+// editMessage substitui o texto (e os botões) de uma mensagem já enviada pelo bot.
+// Se markup for nil os botões são removidos.
+func (b *Bot) editMessage(chatID int64, messageID int, text string, markup *tgbotapi.InlineKeyboardMarkup) {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ReplyMarkup = markup
+	if _, err := b.api.Send(edit); err != nil {
+		utils.Logger.Printf("Erro ao editar a mensagem: %v", err)
+	}
+}
+
+// confirmKeyboard cria os botões "Confirmar" e "Cancelar" de uma ação
+func confirmKeyboard(action string, args ...string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Confirmar", callbackData(action, append([]string{"confirmar"}, args...)...)),
+			tgbotapi.NewInlineKeyboardButtonData("Cancelar", callbackData(action, append([]string{"cancelar"}, args...)...)),
+		),
+	)
+}
@@ -0,0 +1,204 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/command"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// Parâmetros compartilhados pelo /criar e pelo /editar
+var (
+	amountParam = command.Param{
+		Name: "quantidade", Kind: command.Duration,
+		Help: "7200 (segundos), 2h, 2h30m, 02:30, 2,5h, 90min ou 45s; números sem unidade são segundos e precisam ser de pelo menos 60",
+	}
+	observationParam = command.Param{
+		Name: "observacao", Aliases: []string{"obs"}, Kind: command.String,
+		Help: "motivo do lançamento, entre aspas se tiver espaços",
+	}
+	debitParam = command.Param{
+		Name: "debito", Aliases: []string{"retirada"}, Kind: command.Bool, Optional: true, Default: "false",
+		Help: "true/sim para retirada, false/não para crédito; como opção, basta --debito",
+	}
+)
+
+// Esquemas dos comandos, na ordem em que aparecem no /help
+var (
+	startCommand = command.Spec{Name: "start", Summary: "Inicia o bot"}
+	helpCommand  = command.Spec{
+		Name:    "help",
+		Summary: "Mostra os comandos disponíveis, ou a ajuda detalhada de um comando",
+		Params:  []command.Param{{Name: "comando", Kind: command.String, Optional: true, Help: "comando a detalhar, ex.: criar"}},
+	}
+	listCommand = command.Spec{
+		Name:    "listar",
+		Summary: "Lista os colaboradores ativos, em páginas, ou envia a lista completa em .xlsx ou .csv",
+		Params: []command.Param{
+			{Name: "pagina", Kind: command.Int, Optional: true, Help: "página a mostrar, a partir de 1"},
+			{Name: "formato", Kind: command.String, Optional: true, Help: "xlsx ou csv para receber a lista completa como arquivo, com ID, nome, e-mail, CPF, matrícula, centro de custo e jornada"},
+		},
+		Examples: []string{"/listar", "/listar 3", "/listar xlsx"},
+	}
+	searchCommand = command.Spec{
+		Name:     "buscar",
+		Summary:  "Busca colaboradores por nome, e-mail, CPF ou matrícula, tolerando erros de digitação; o resultado abre os detalhes, o saldo e a criação de lançamentos",
+		Params:   []command.Param{{Name: "termo", Kind: command.String, Rest: true, Help: "parte do nome, e-mail, CPF ou matrícula"}},
+		Examples: []string{"/buscar maria souza", "/buscar 123.456.789-00", "/buscar joao@empresa.com.br"},
+	}
+	balanceCommand = command.Spec{
+		Name:     "saldo",
+		Summary:  "Mostra o saldo do banco de horas de um colaborador, os créditos e débitos do mês e os últimos lançamentos com seus números",
+		Params:   []command.Param{{Name: "colaborador", Kind: command.String, Rest: true, Help: "ID, CPF, matrícula, e-mail ou parte do nome"}},
+		Examples: []string{"/saldo 1487972", "/saldo maria souza", "/saldo 123.456.789-00"},
+	}
+	historyCommand = command.Spec{
+		Name:    "historico",
+		Summary: "Lista os lançamentos do banco de horas de um colaborador em um período, com o saldo após cada um, ou os exporta em .xlsx ou .csv",
+		Params: []command.Param{
+			{Name: "colaborador", Kind: command.String, Help: "ID, CPF, matrícula, e-mail ou parte do nome, entre aspas se tiver espaços"},
+			{Name: "de", Kind: command.Date, Optional: true, Help: "data inicial; padrão: primeiro dia do mês da data final"},
+			{Name: "ate", Kind: command.Date, Optional: true, Help: "data final; padrão: hoje"},
+			{Name: "formato", Kind: command.String, Optional: true, Help: "xlsx ou csv para receber o período como arquivo"},
+		},
+		Examples: []string{
+			"/historico 1487972",
+			`/historico "maria souza" 01/01/2025 31/03/2025`,
+			"/historico 1487972 --de=01/03 xlsx",
+		},
+	}
+	punchesCommand = command.Spec{
+		Name:    "batidas",
+		Summary: "Mostra as batidas de ponto de um colaborador em um dia, as horas trabalhadas comparadas à jornada e as ocorrências, como batida faltando",
+		Params: []command.Param{
+			{Name: "colaborador", Kind: command.String, Help: "ID, CPF, matrícula, e-mail ou parte do nome, entre aspas se tiver espaços"},
+			{Name: "data", Kind: command.Date, Optional: true, Default: "hoje", Help: "AAAA-MM-DD, DD/MM/AAAA, DD/MM, hoje ou ontem"},
+		},
+		Examples: []string{"/batidas 1487972", "/batidas 1487972 ontem", `/batidas "maria souza" 16/10/2026`},
+	}
+	digestCommand = command.Spec{
+		Name:     "ocorrencias",
+		Summary:  "Gera o relatório de ocorrências nas batidas de todos os colaboradores em um dia, o mesmo enviado pela tarefa agendada",
+		Params:   []command.Param{{Name: "data", Kind: command.Date, Optional: true, Default: "ontem", Help: "AAAA-MM-DD, DD/MM/AAAA, DD/MM, hoje ou ontem"}},
+		Examples: []string{"/ocorrencias", "/ocorrencias 16/10/2026"},
+	}
+	scheduleCommand = command.Spec{
+		Name:    "agendar",
+		Summary: "Agenda tarefas recorrentes ou de execução única (relatório de ocorrências, planilha de saldos do mês anterior ou importação de uma planilha) e lista, executa ou remove os agendamentos; o resultado de cada execução é enviado ao chat",
+		Params: []command.Param{
+			{Name: "tarefa", Kind: command.String, Optional: true, Help: "ocorrencias, saldos ou importacao; ou lista, executar <número> e remover <número>"},
+			{Name: "quando", Kind: command.String, Optional: true, Rest: true, Help: "expressão cron de 5 posições (minuto hora dia-do-mês mês dia-da-semana), ex.: 0 8 * * 1-5, ou data e horário de uma execução única, ex.: 01/11/2026 08:00"},
+			{Name: "aba", Kind: command.String, Optional: true, Help: "aba da planilha na importação, pelo nome ou pela posição, como opção: --aba=Março"},
+		},
+		Examples: []string{
+			"/agendar",
+			"/agendar ocorrencias 0 8 * * 1-5",
+			"/agendar saldos 0 7 1 * *",
+			"/agendar importacao 01/11/2026 08:00 --aba=Novembro",
+			"/agendar executar 3",
+			"/agendar remover 3",
+		},
+	}
+	createCommand = command.Spec{
+		Name:    "criar",
+		Summary: "Cria um novo lançamento no banco de horas, após confirmação; sem argumentos, inicia um assistente passo a passo",
+		Params: []command.Param{
+			{Name: "funcionario", Kind: command.Int, Help: "ID do funcionário no Ponto Mais"},
+			amountParam,
+			{Name: "data", Kind: command.Date, Optional: true, Default: "hoje", Help: "AAAA-MM-DD, DD/MM/AAAA, DD/MM, hoje ou ontem"},
+			observationParam,
+			debitParam,
+		},
+		Examples: []string{
+			"/criar",
+			`/criar 1487972 2h 2025-03-18 "Horas extras" false`,
+			`/criar 1487972 90min "Ajuste de ponto"`,
+			`/criar 1487972 1h30m --data=ontem --obs="Folga compensada" --debito`,
+		},
+	}
+	editCommand = command.Spec{
+		Name:    "editar",
+		Summary: "Edita um lançamento do banco de horas, após confirmação",
+		Params: []command.Param{
+			{Name: "lancamento", Kind: command.Int, Help: "ID do lançamento no banco de horas"},
+			amountParam,
+			{Name: "data", Kind: command.Date, Help: "AAAA-MM-DD, DD/MM/AAAA, DD/MM, hoje ou ontem"},
+			observationParam,
+			debitParam,
+		},
+		Examples: []string{
+			`/editar 3833376 2h30m 2025-03-18 "Horas extras" false`,
+			`/editar 3833376 1h 18/03 "Ajuste de ponto" --debito`,
+		},
+	}
+	deleteCommand = command.Spec{
+		Name:     "excluir",
+		Summary:  "Exclui um lançamento do banco de horas, após confirmação",
+		Params:   []command.Param{{Name: "lancamento", Kind: command.Int, Help: "ID do lançamento no banco de horas"}},
+		Examples: []string{"/excluir 3833376"},
+	}
+	undoCommand = command.Spec{
+		Name:     "desfazer",
+		Summary:  "Desfaz a última operação feita pelo bot, ou uma escolhida da lista",
+		Params:   []command.Param{{Name: "operacao", Kind: command.String, Optional: true, Help: "\"lista\" para ver as últimas operações, ou o número de uma delas"}},
+		Examples: []string{"/desfazer", "/desfazer lista", "/desfazer 12"},
+	}
+	relatorioCommand = command.Spec{
+		Name:     "relatorio",
+		Summary:  "Processa uma planilha (.xlsx, .ods ou .csv) com múltiplos lançamentos de banco de horas",
+		Params:   []command.Param{{Name: "aba", Kind: command.String, Optional: true, Rest: true, Help: "nome ou posição (a partir de 1) da aba a ler; padrão: IMPORT_SHEET ou a primeira"}},
+		Examples: []string{"/relatorio", "/relatorio Março", "/relatorio 2"},
+	}
+	statusCommand = command.Spec{
+		Name:    "status",
+		Summary: "Mostra o andamento das últimas importações, ou de uma delas",
+		Params:  []command.Param{{Name: "importacao", Kind: command.Int, Optional: true, Help: "número da importação"}},
+	}
+	cancelCommand = command.Spec{
+		Name:    "cancelar",
+		Summary: "Sem argumentos, sai do assistente ou do envio de planilha em andamento; com o número, interrompe uma importação (as linhas já lançadas são mantidas)",
+		Params:  []command.Param{{Name: "importacao", Kind: command.Int, Optional: true, Help: "número da importação"}},
+	}
+)
+
+// commandSpecs lista os esquemas de todos os comandos, usados pelo /help
+var commandSpecs = []command.Spec{
+	startCommand, helpCommand, listCommand, searchCommand, balanceCommand, historyCommand, punchesCommand, digestCommand, scheduleCommand, createCommand, editCommand, deleteCommand,
+	undoCommand, relatorioCommand, statusCommand, cancelCommand,
+}
+
+// findCommandSpec busca o esquema de um comando pelo nome, com ou sem a barra
+func findCommandSpec(name string) (command.Spec, bool) {
+	name = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "/")
+	for _, spec := range commandSpecs {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return command.Spec{}, false
+}
+
+// parseCommand interpreta os argumentos da mensagem segundo o esquema do
+// comando. Em caso de erro, responde com o motivo e a forma de uso.
+func (b *Bot) parseCommand(message *tgbotapi.Message, spec command.Spec) (command.Args, bool) {
+	args, err := spec.Parse(message.CommandArguments())
+	if err != nil {
+		utils.Logger.Printf("Argumentos inválidos para o comando /%s: %v", spec.Name, err)
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Erro: %v.\n\nUse:\n%s\n\nMais detalhes em /help %s", err, spec.Usage(), spec.Name)))
+		return command.Args{}, false
+	}
+	return args, true
+}
+
+// helpText gera a lista de comandos do /help a partir dos esquemas
+func helpText() string {
+	var text strings.Builder
+	text.WriteString("Comandos disponíveis:\n")
+	for _, spec := range commandSpecs {
+		text.WriteString(fmt.Sprintf("\n%s - %s", spec.Usage(), spec.Summary))
+	}
+	text.WriteString("\n\nUse /help <comando> para ver os parâmetros e exemplos de um comando, ex.: /help criar")
+	return text.String()
+}
@@ -0,0 +1,191 @@
+package telegram
+
+import (
+	"context"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/scheduler"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// defaultConversationTimeout é o tempo sem resposta após o qual uma conversa é
+// encerrada, quando CONVERSATION_TIMEOUT não está definido
+const defaultConversationTimeout = 10 * time.Minute
+
+// conversationSweepInterval é o intervalo entre as verificações de conversas expiradas
+const conversationSweepInterval = 30 * time.Second
+
+// conversationFlow identifica o comando que iniciou a conversa
+type conversationFlow string
+
+const (
+	flowRelatorio conversationFlow = "relatorio" // Aguarda a planilha do /relatorio
+	flowCreate    conversationFlow = "criar"     // Assistente de criação de lançamento
+	flowSchedule  conversationFlow = "agendar"   // Aguarda a planilha de uma importação agendada
+)
+
+// conversationStep é a informação que a conversa aguarda do chat
+type conversationStep string
+
+const (
+	stepDocument    conversationStep = "documento"
+	stepEmployee    conversationStep = "colaborador"
+	stepKind        conversationStep = "tipo"
+	stepAmount      conversationStep = "quantidade"
+	stepDate        conversationStep = "data"
+	stepObservation conversationStep = "observacao"
+	stepDone        conversationStep = "concluido" // Assistente respondido; não fica guardado
+)
+
+// conversation é o estado de uma conversa de várias mensagens com um chat.
+// Cada chat tem no máximo uma; iniciar outra substitui a anterior.
+type conversation struct {
+	flow    conversationFlow
+	step    conversationStep
+	expires time.Time
+
+	sheet string // Aba a ler da planilha, no /relatorio e no /agendar
+
+	schedule scheduler.Entry // Importação agendada aguardando a planilha
+
+	entry        models.TimeBalanceEntry // Lançamento montado pelo assistente do /criar
+	employeeName string
+	candidates   map[string]string // Colaboradores oferecidos na busca, por ID
+}
+
+// conversationTimeout devolve o tempo de espera configurado para as conversas
+func (b *Bot) conversationTimeout() time.Duration {
+	if b.config.ConversationTimeout > 0 {
+		return b.config.ConversationTimeout
+	}
+	return defaultConversationTimeout
+}
+
+// setConversation inicia ou avança a conversa do chat, renovando o prazo de resposta
+func (b *Bot) setConversation(chatID int64, conv conversation) {
+	conv.expires = time.Now().Add(b.conversationTimeout())
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.conversations[chatID] = &conv
+}
+
+// activeConversation devolve uma cópia da conversa do chat. Uma conversa
+// expirada é encerrada e o chat é avisado.
+func (b *Bot) activeConversation(chatID int64) (conversation, bool) {
+	b.mu.Lock()
+	conv, ok := b.conversations[chatID]
+	expired := ok && time.Now().After(conv.expires)
+	if expired {
+		delete(b.conversations, chatID)
+	}
+	b.mu.Unlock()
+
+	if !ok {
+		return conversation{}, false
+	}
+	if expired {
+		b.notifyExpired(chatID, *conv)
+		return conversation{}, false
+	}
+	return *conv, true
+}
+
+// endConversation encerra a conversa do chat, informando se havia uma
+func (b *Bot) endConversation(chatID int64) (conversation, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	conv, ok := b.conversations[chatID]
+	if !ok {
+		return conversation{}, false
+	}
+	delete(b.conversations, chatID)
+	return *conv, true
+}
+
+// sweepConversations encerra periodicamente as conversas sem resposta no
+// prazo, avisando os chats, até ctx ser cancelado
+func (b *Bot) sweepConversations(ctx context.Context) {
+	ticker := time.NewTicker(conversationSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			b.expireConversations(now)
+		}
+	}
+}
+
+// expireConversations encerra as conversas cujo prazo terminou antes de now e
+// avisa os chats
+func (b *Bot) expireConversations(now time.Time) {
+	expired := make(map[int64]conversation)
+	b.mu.Lock()
+	for chatID, conv := range b.conversations {
+		if now.After(conv.expires) {
+			expired[chatID] = *conv
+			delete(b.conversations, chatID)
+		}
+	}
+	b.mu.Unlock()
+
+	for chatID, conv := range expired {
+		b.notifyExpired(chatID, conv)
+	}
+}
+
+// notifyExpired avisa o chat de que a conversa foi encerrada por falta de resposta
+func (b *Bot) notifyExpired(chatID int64, conv conversation) {
+	utils.Logger.Printf("Conversa /%s do chat ID %d encerrada por inatividade", conv.flow, chatID)
+	b.api.Send(tgbotapi.NewMessage(chatID, describeFlow(conv.flow)+" foi encerrado por falta de resposta. Envie o comando novamente para recomeçar."))
+}
+
+// describeFlow nomeia o fluxo nas mensagens, ex.: "O assistente do /criar"
+func describeFlow(flow conversationFlow) string {
+	switch flow {
+	case flowRelatorio:
+		return "O envio da planilha do /relatorio"
+	case flowSchedule:
+		return "O envio da planilha do /agendar"
+	default:
+		return "O assistente do /" + string(flow)
+	}
+}
+
+// handleConversationMessage encaminha uma mensagem que não é comando para o
+// passo atual da conversa do chat
+func (b *Bot) handleConversationMessage(message *tgbotapi.Message, conv conversation) {
+	if conv.step == stepDocument {
+		if message.Document == nil {
+			b.api.Send(tgbotapi.NewMessage(message.Chat.ID, "Por favor, envie a planilha (.xlsx, .ods ou .csv) como arquivo, ou use /cancelar."))
+			return
+		}
+		b.endConversation(message.Chat.ID)
+		if conv.flow == flowSchedule {
+			b.handleScheduleDocument(message, conv)
+			return
+		}
+		b.handleDocumentReceived(message, conv.sheet)
+		return
+	}
+
+	if message.Text == "" {
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, "Responda com uma mensagem de texto, ou use /cancelar para sair do assistente."))
+		return
+	}
+	b.handleWizardText(message, conv)
+}
+
+// handleCancelConversation encerra a conversa em andamento no chat (/cancelar sem argumentos)
+func (b *Bot) handleCancelConversation(message *tgbotapi.Message) {
+	conv, ok := b.endConversation(message.Chat.ID)
+	if !ok {
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, "Nenhum assistente em andamento. Para interromper uma importação, use /cancelar <número da importação>."))
+		return
+	}
+	utils.Logger.Printf("Conversa /%s cancelada por %s no chat ID: %d", conv.flow, userLabel(message.From), message.Chat.ID)
+	b.api.Send(tgbotapi.NewMessage(message.Chat.ID, describeFlow(conv.flow)+" foi cancelado."))
+}
@@ -0,0 +1,100 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestCancelConversation(t *testing.T) {
+	tests := []struct {
+		name    string
+		conv    *conversation // Conversa em andamento; nil se não houver
+		wantMsg string
+	}{
+		{"assistente do /criar", &conversation{flow: flowCreate, step: stepAmount}, "O assistente do /criar foi cancelado."},
+		{"planilha do /relatorio", &conversation{flow: flowRelatorio, step: stepDocument}, "O envio da planilha do /relatorio foi cancelado."},
+		{"sem conversa", nil, "Nenhum assistente em andamento"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, fake := newTestBot(t, nil)
+			if tt.conv != nil {
+				b.setConversation(testChatID, *tt.conv)
+			}
+
+			b.handleCancelConversation(&tgbotapi.Message{Text: "/cancelar", Chat: &tgbotapi.Chat{ID: testChatID}})
+
+			if _, ok := b.activeConversation(testChatID); ok {
+				t.Error("conversa ainda ativa após /cancelar")
+			}
+			if texts := fake.texts(); len(texts) != 1 || !strings.Contains(texts[0], tt.wantMsg) {
+				t.Errorf("mensagens = %q, esperado %q", texts, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestExpireConversations(t *testing.T) {
+	b, fake := newTestBot(t, nil)
+	b.config.ConversationTimeout = time.Minute
+	b.setConversation(1, conversation{flow: flowCreate, step: stepDate})
+	b.setConversation(2, conversation{flow: flowSchedule, step: stepDocument})
+	b.setConversation(3, conversation{flow: flowRelatorio, step: stepDocument})
+
+	// A conversa do chat 2 foi respondida há menos tempo e ainda está no prazo
+	b.mu.Lock()
+	now := time.Now()
+	b.conversations[1].expires = now.Add(-time.Second)
+	b.conversations[2].expires = now.Add(time.Second)
+	b.conversations[3].expires = now.Add(-time.Minute)
+	b.mu.Unlock()
+
+	b.expireConversations(now)
+
+	if _, ok := b.conversations[2]; !ok || len(b.conversations) != 1 {
+		t.Errorf("conversas mantidas = %v, esperado só a do chat 2", b.conversations)
+	}
+	notified := make(map[string]string)
+	fake.mu.Lock()
+	for _, call := range fake.calls {
+		notified[call.Params["chat_id"]] = call.Params["text"]
+	}
+	fake.mu.Unlock()
+	if len(notified) != 2 {
+		t.Fatalf("avisos enviados = %v, esperado 2", notified)
+	}
+	if !strings.HasPrefix(notified["1"], "O assistente do /criar foi encerrado por falta de resposta") {
+		t.Errorf("aviso do chat 1 = %q", notified["1"])
+	}
+	if !strings.HasPrefix(notified["3"], "O envio da planilha do /relatorio foi encerrado") {
+		t.Errorf("aviso do chat 3 = %q", notified["3"])
+	}
+
+	// Uma segunda varredura não repete os avisos
+	b.expireConversations(now.Add(time.Millisecond))
+	if texts := fake.texts(); len(texts) != 2 {
+		t.Errorf("%d avisos após a segunda varredura, esperado 2", len(texts))
+	}
+}
+
+func TestActiveConversationExpired(t *testing.T) {
+	b, fake := newTestBot(t, nil)
+	b.setConversation(testChatID, conversation{flow: flowCreate, step: stepObservation})
+	b.mu.Lock()
+	b.conversations[testChatID].expires = time.Now().Add(-time.Second)
+	b.mu.Unlock()
+
+	// Uma resposta que chega depois do prazo encontra a conversa encerrada
+	if _, ok := b.activeConversation(testChatID); ok {
+		t.Fatal("conversa expirada devolvida como ativa")
+	}
+	if texts := fake.texts(); len(texts) != 1 || !strings.Contains(texts[0], "encerrado por falta de resposta") {
+		t.Errorf("mensagens = %q, esperado o aviso de encerramento", texts)
+	}
+	if _, ok := b.activeConversation(testChatID); ok {
+		t.Error("conversa reapareceu após o encerramento")
+	}
+}
@@ -0,0 +1,99 @@
+package telegram
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/journal"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// recordFingerprint resume os dados de um lançamento em poucos caracteres, para
+// que o botão de confirmação detecte alterações feitas depois da prévia
+func recordFingerprint(record *models.TimeBalanceEntryRecord) string {
+	entry := record.Entry()
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%v|%s|%s|%t", entry.EmployeeID, entry.Amount, entry.Date, entry.Observation, entry.Withdraw)))
+	return hex.EncodeToString(sum[:4])
+}
+
+// handleDeleteTimeBalance mostra os dados de um lançamento e pede confirmação antes de excluí-lo
+func (b *Bot) handleDeleteTimeBalance(message *tgbotapi.Message) {
+	utils.Logger.Printf("Comando /excluir recebido do chat ID: %d", message.Chat.ID)
+
+	args, ok := b.parseCommand(message, deleteCommand)
+	if !ok {
+		return
+	}
+	entryID := strconv.Itoa(args.Int("lancamento"))
+
+	// Busca o lançamento para que o operador confira o que será excluído
+	record, err := b.client.GetTimeBalanceEntry(b.ctx, entryID)
+	if err != nil {
+		utils.Logger.Printf("Erro ao buscar o lançamento %s: %v", entryID, err)
+		errorMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Erro ao buscar o lançamento: %s", describeError(err)))
+		b.api.Send(errorMsg)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Deseja realmente excluir este lançamento?\n\n%s", formatEntryRecord(record)))
+	msg.ReplyMarkup = confirmKeyboard("excluir", entryID, recordFingerprint(record))
+	b.api.Send(msg)
+}
+
+// handleDeleteCallback trata os botões de confirmação do /excluir
+func (b *Bot) handleDeleteCallback(query *tgbotapi.CallbackQuery, args []string) {
+	chatID := query.Message.Chat.ID
+	messageID := query.Message.MessageID
+	if len(args) != 3 {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+	decision, entryID, fingerprint := args[0], args[1], args[2]
+
+	if decision != "confirmar" {
+		utils.Logger.Printf("Exclusão do lançamento %s cancelada por %s", entryID, userLabel(query.From))
+		b.answerCallback(query, "Exclusão cancelada.")
+		b.editMessage(chatID, messageID, fmt.Sprintf("Exclusão do lançamento %s cancelada.", entryID), nil)
+		return
+	}
+
+	b.answerCallback(query, "Excluindo lançamento...")
+
+	// Busca novamente os dados atuais para que a exclusão possa ser desfeita
+	record, err := b.client.GetTimeBalanceEntry(b.ctx, entryID)
+	if err != nil {
+		utils.Logger.Printf("Erro ao buscar o lançamento %s antes da exclusão: %v", entryID, err)
+		b.editMessage(chatID, messageID, fmt.Sprintf("Erro ao buscar o lançamento %s: %s", entryID, describeError(err)), nil)
+		return
+	}
+
+	// Se o lançamento mudou desde a prévia, mostra os dados atuais e pede uma nova confirmação
+	if current := recordFingerprint(record); current != fingerprint {
+		utils.Logger.Printf("Lançamento %s alterado entre a prévia e a confirmação da exclusão", entryID)
+		markup := confirmKeyboard("excluir", entryID, current)
+		b.editMessage(chatID, messageID, fmt.Sprintf("O lançamento %s foi alterado desde a prévia. Confira os dados atuais antes de excluir:\n\n%s", entryID, formatEntryRecord(record)), &markup)
+		return
+	}
+
+	if err := b.client.DeleteTimeBalanceEntry(b.ctx, entryID); err != nil {
+		utils.Logger.Printf("Erro ao excluir o lançamento %s: %v", entryID, err)
+		b.editMessage(chatID, messageID, fmt.Sprintf("Erro ao excluir o lançamento %s: %s", entryID, describeError(err)), nil)
+		return
+	}
+
+	previous := record.Entry()
+	b.recordOperation(journal.Operation{
+		Kind:     journal.OperationDelete,
+		ChatID:   chatID,
+		User:     userLabel(query.From),
+		EntryID:  record.ID,
+		Previous: &previous,
+	})
+
+	utils.Logger.Printf("Lançamento %s excluído por %s no chat ID: %d", entryID, userLabel(query.From), chatID)
+	b.editMessage(chatID, messageID, fmt.Sprintf("Lançamento %s excluído com sucesso. Use /desfazer para recriá-lo.", entryID), nil)
+}
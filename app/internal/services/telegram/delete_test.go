@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/journal"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// entryServer simula os lançamentos do Ponto Mais, guardando os excluídos
+type entryServer struct {
+	mu      sync.Mutex
+	records map[string]models.TimeBalanceEntryRecord
+	deleted []string
+}
+
+func (s *entryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := strings.TrimPrefix(r.URL.Path, "/time_balance_entries/")
+	record, ok := s.records[id]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		json.NewEncoder(w).Encode(models.TimeBalanceEntryResponse{TimeBalanceEntry: record})
+	case http.MethodDelete:
+		delete(s.records, id)
+		s.deleted = append(s.deleted, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// set troca os dados de um lançamento, como se alguém o editasse no Ponto Mais
+func (s *entryServer) set(id string, record models.TimeBalanceEntryRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[id] = record
+}
+
+func TestDeleteFlow(t *testing.T) {
+	original := models.TimeBalanceEntryRecord{ID: 5, EmployeeID: 12, Amount: 3600, Date: "2025-03-10", Observation: "Plantão"}
+	changed := original
+	changed.Amount = 7200
+
+	tests := []struct {
+		name        string
+		decision    string
+		change      bool // Altera o lançamento entre a prévia e a confirmação
+		wantDeleted bool
+		wantText    string
+	}{
+		{"confirmação exclui e registra no diário", "confirmar", false, true, "Lançamento 5 excluído com sucesso"},
+		{"cancelamento não exclui", "cancelar", false, false, "Exclusão do lançamento 5 cancelada"},
+		{"lançamento alterado pede nova confirmação", "confirmar", true, false, "foi alterado desde a prévia"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &entryServer{records: map[string]models.TimeBalanceEntryRecord{"5": original}}
+			b, fake := newTestBot(t, server)
+
+			// Prévia: o botão de confirmação leva a impressão digital do lançamento exibido
+			b.handleDeleteTimeBalance(&tgbotapi.Message{
+				Text:     "/excluir 5",
+				Chat:     &tgbotapi.Chat{ID: testChatID},
+				Entities: &[]tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 8}},
+			})
+			preview, ok := fake.last("sendMessage")
+			if !ok || !strings.Contains(preview.Params["text"], "Deseja realmente excluir") {
+				t.Fatalf("prévia não enviada: %v", fake.texts())
+			}
+			data := callbackData("excluir", tt.decision, "5", recordFingerprint(&original))
+			if !strings.Contains(preview.Params["reply_markup"], data) && tt.decision == "confirmar" {
+				t.Fatalf("botões da prévia = %s, esperado %q", preview.Params["reply_markup"], data)
+			}
+
+			if tt.change {
+				server.set("5", changed)
+			}
+			b.handleCallback(callbackQuery(data))
+
+			edit, ok := fake.last("editMessageText")
+			if !ok || !strings.Contains(edit.Params["text"], tt.wantText) {
+				t.Fatalf("mensagem final = %q, esperado %q", edit.Params["text"], tt.wantText)
+			}
+			if deleted := len(server.deleted) > 0; deleted != tt.wantDeleted {
+				t.Fatalf("excluído = %t, esperado %t", deleted, tt.wantDeleted)
+			}
+
+			ops := b.journal.Recent(testChatID, 10)
+			if !tt.wantDeleted {
+				if len(ops) != 0 {
+					t.Errorf("diário = %+v, esperado vazio", ops)
+				}
+				if tt.change && !strings.Contains(edit.Params["reply_markup"], recordFingerprint(&changed)) {
+					t.Errorf("nova confirmação sem a impressão digital atual: %s", edit.Params["reply_markup"])
+				}
+				return
+			}
+			if len(ops) != 1 {
+				t.Fatalf("diário com %d operações, esperado 1", len(ops))
+			}
+			op := ops[0]
+			if op.Kind != journal.OperationDelete || op.EntryID != 5 || op.Previous == nil {
+				t.Fatalf("operação = %+v", op)
+			}
+			if want := original.Entry(); *op.Previous != want {
+				t.Errorf("valores anteriores = %+v, esperado %+v", *op.Previous, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+	"github.com/jeffemart/PontoGo/app/internal/export"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/scheduler"
+	"github.com/jeffemart/PontoGo/app/internal/services/pontomais"
+	"github.com/jeffemart/PontoGo/app/internal/timecards"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// digestMaxLines é a quantidade de ocorrências listadas na mensagem do
+// relatório; as demais ficam apenas na planilha
+const digestMaxLines = 40
+
+// digestMaxUnknownHours é a quantidade de colaboradores com jornada não
+// reconhecida citados na mensagem do relatório
+const digestMaxUnknownHours = 10
+
+// digestOffender é um colaborador com ocorrências no relatório de batidas
+type digestOffender struct {
+	employee models.Employee
+	day      timecards.Day
+	issues   []timecards.Issue // Ocorrências relatadas, conforme DIGEST_RULES
+}
+
+// punchDigest é o relatório de ocorrências nas batidas de um dia
+type punchDigest struct {
+	date         time.Time
+	analyzed     int // Colaboradores ativos analisados
+	offenders    []digestOffender
+	unknownHours []models.Employee // Jornada do cadastro não reconhecida: hora extra e jornada incompleta não verificadas
+}
+
+// handleDigest trata o comando /ocorrencias [data], que gera o relatório de
+// ocorrências sob demanda para o chat
+func (b *Bot) handleDigest(message *tgbotapi.Message) {
+	utils.Logger.Printf("Comando /ocorrencias recebido do chat ID: %d", message.Chat.ID)
+	args, ok := b.parseCommand(message, digestCommand)
+	if !ok {
+		return
+	}
+
+	b.api.Send(tgbotapi.NewMessage(message.Chat.ID, "Analisando as batidas de todos os colaboradores..."))
+	digest, err := b.buildDigest(b.ctx, args.Date("data"))
+	if err != nil {
+		utils.Logger.Printf("Erro ao gerar o relatório de ocorrências: %v", err)
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Erro ao gerar o relatório de ocorrências: %s", describeError(err))))
+		return
+	}
+	b.sendDigest(message.Chat.ID, digest)
+}
+
+// digestTask é a tarefa agendada "ocorrencias": envia o relatório de
+// ocorrências do dia anterior ao chat do agendamento ou, nos agendamentos sem
+// chat (DIGEST_TIME e SCHEDULES_FILE), a todos os TELEGRAM_HOSTS
+func (b *Bot) digestTask(ctx context.Context, entry scheduler.Entry) (string, error) {
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, now.Location())
+	utils.Logger.Printf("Gerando o relatório de ocorrências de %s", date.Format("02/01/2006"))
+	digest, err := b.buildDigest(ctx, date)
+	if err != nil {
+		return "", fmt.Errorf("não foi possível gerar o relatório de ocorrências de %s: %s", date.Format("02/01/2006"), describeError(err))
+	}
+	chats := b.config.TelegramHosts
+	if entry.ChatID != 0 {
+		chats = []int64{entry.ChatID}
+	}
+	for _, chatID := range chats {
+		b.sendDigest(chatID, digest)
+	}
+	return fmt.Sprintf("Relatório de ocorrências de %s enviado a %d chats: %d de %d colaboradores com ocorrências.",
+		date.Format("02/01/2006"), len(chats), len(digest.offenders), digest.analyzed), nil
+}
+
+// buildDigest analisa as batidas de todos os colaboradores ativos na data e
+// reúne os que têm alguma das ocorrências de DIGEST_RULES
+func (b *Bot) buildDigest(ctx context.Context, date time.Time) (*punchDigest, error) {
+	employees, err := b.client.GetEmployees(ctx, pontomais.EmployeeFilter{Attributes: employeeDetailAttributes})
+	if err != nil {
+		return nil, err
+	}
+	cards, err := b.client.ListTimeCards(ctx, pontomais.TimeCardFilter{StartDate: date, EndDate: date})
+	if err != nil {
+		return nil, err
+	}
+
+	rules := timecards.DefaultRules
+	rules.Tolerance = b.config.DigestTolerance
+	digest := analyzeDigest(date, employees, cards, rules, b.config.DigestRules)
+	if len(digest.unknownHours) > 0 {
+		ids := make([]string, len(digest.unknownHours))
+		for i, employee := range digest.unknownHours {
+			ids[i] = fmt.Sprintf("%d (%q)", employee.ID, employee.WorkHours)
+		}
+		utils.Logger.Printf("Relatório de ocorrências de %s: jornada não reconhecida para %d colaboradores: %s",
+			date.Format("02/01/2006"), len(ids), strings.Join(ids, ", "))
+	}
+	utils.Logger.Printf("Relatório de ocorrências de %s: %d batidas, %d de %d colaboradores com ocorrências",
+		date.Format("02/01/2006"), len(cards), len(digest.offenders), digest.analyzed)
+	return digest, nil
+}
+
+// analyzeDigest analisa as batidas de cada colaborador na data e mantém apenas
+// as ocorrências dos tipos em reported (DIGEST_RULES). Colaboradores com a
+// jornada preenchida, mas em formato não reconhecido, ficam em unknownHours.
+func analyzeDigest(date time.Time, employees []models.Employee, cards []models.TimeCard, rules timecards.Rules, reported []string) *punchDigest {
+	byEmployee := make(map[int][]models.TimeCard)
+	for _, card := range cards {
+		byEmployee[card.EmployeeID] = append(byEmployee[card.EmployeeID], card)
+	}
+	kinds := make(map[timecards.IssueKind]bool)
+	for _, kind := range reported {
+		kinds[timecards.IssueKind(kind)] = true
+	}
+
+	digest := &punchDigest{date: date, analyzed: len(employees)}
+	for _, employee := range employees {
+		expected, ok := timecards.ExpectedWork(employee.WorkHours)
+		if !ok && strings.TrimSpace(employee.WorkHours) != "" {
+			digest.unknownHours = append(digest.unknownHours, employee)
+		}
+		day := timecards.Analyze(byEmployee[employee.ID], date, expected, rules)
+		var issues []timecards.Issue
+		for _, issue := range day.Issues {
+			if kinds[issue.Kind] {
+				issues = append(issues, issue)
+			}
+		}
+		if len(issues) > 0 {
+			digest.offenders = append(digest.offenders, digestOffender{employee: employee, day: day, issues: issues})
+		}
+	}
+	return digest
+}
+
+// sendDigest envia o relatório ao chat: o resumo por ocorrência e a planilha
+// com todos os colaboradores com ocorrências
+func (b *Bot) sendDigest(chatID int64, digest *punchDigest) {
+	b.api.Send(tgbotapi.NewMessage(chatID, formatDigest(digest)))
+	if len(digest.offenders) == 0 {
+		return
+	}
+
+	data, err := digestTable(digest).XLSX()
+	if err != nil {
+		utils.Logger.Printf("Erro ao gerar a planilha do relatório de ocorrências: %v", err)
+		b.api.Send(tgbotapi.NewMessage(chatID, "Não foi possível gerar a planilha do relatório de ocorrências."))
+		return
+	}
+	name := fmt.Sprintf("ocorrencias_%s.xlsx", digest.date.Format("2006-01-02"))
+	doc := tgbotapi.NewDocumentUpload(chatID, tgbotapi.FileBytes{Name: name, Bytes: data})
+	doc.Caption = fmt.Sprintf("Ocorrências nas batidas de %s: %d colaboradores.", digest.date.Format("02/01/2006"), len(digest.offenders))
+	if _, err := b.api.Send(doc); err != nil {
+		utils.Logger.Printf("Erro ao enviar a planilha do relatório de ocorrências: %v", err)
+	}
+}
+
+// formatDigest resume o relatório, agrupando os colaboradores por ocorrência.
+// Ao chegar a digestMaxLines ocorrências listadas, as seções seguintes aparecem
+// apenas com o total, em uma única linha.
+func formatDigest(digest *punchDigest) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Ocorrências nas batidas de %s, %s\n", weekdayNames[digest.date.Weekday()], digest.date.Format("02/01/2006")))
+	text.WriteString(fmt.Sprintf("Colaboradores analisados: %d\n", digest.analyzed))
+	if len(digest.offenders) == 0 {
+		text.WriteString("\nNenhuma ocorrência.\n")
+		writeUnknownHours(&text, digest.unknownHours)
+		return strings.TrimSuffix(text.String(), "\n")
+	}
+	text.WriteString(fmt.Sprintf("Com ocorrências: %d\n", len(digest.offenders)))
+
+	lines := 0
+	var omitted []string // Seções que não couberam na mensagem, com o total de cada uma
+	for _, kind := range timecards.IssueKinds {
+		var section []string
+		for _, offender := range digest.offenders {
+			for _, issue := range offender.issues {
+				if issue.Kind == kind {
+					section = append(section, fmt.Sprintf("- %s: %s", employeeLabel(offender.employee), issue.Message))
+				}
+			}
+		}
+		if len(section) == 0 {
+			continue
+		}
+		if lines == digestMaxLines {
+			omitted = append(omitted, fmt.Sprintf("%s (%d)", kind.Label(), len(section)))
+			continue
+		}
+		text.WriteString(fmt.Sprintf("\n%s (%d):\n", kind.Label(), len(section)))
+		for i, line := range section {
+			if lines == digestMaxLines {
+				text.WriteString(fmt.Sprintf("... e mais %d\n", len(section)-i))
+				break
+			}
+			text.WriteString(line + "\n")
+			lines++
+		}
+	}
+	if len(omitted) > 0 {
+		text.WriteString(fmt.Sprintf("\nTambém na planilha: %s.\n", strings.Join(omitted, ", ")))
+	}
+	writeUnknownHours(&text, digest.unknownHours)
+	text.WriteString("\nUse /batidas <colaborador> <data> para ver as batidas de um colaborador.")
+	return text.String()
+}
+
+// writeUnknownHours cita no relatório os colaboradores cuja jornada do
+// cadastro não foi reconhecida, até digestMaxUnknownHours
+func writeUnknownHours(text *strings.Builder, employees []models.Employee) {
+	if len(employees) == 0 {
+		return
+	}
+	names := make([]string, 0, digestMaxUnknownHours+1)
+	for _, employee := range employees[:min(len(employees), digestMaxUnknownHours)] {
+		names = append(names, employeeLabel(employee))
+	}
+	if extra := len(employees) - digestMaxUnknownHours; extra > 0 {
+		names = append(names, fmt.Sprintf("e mais %d", extra))
+	}
+	text.WriteString(fmt.Sprintf("\nJornada do cadastro não reconhecida, sem verificação de hora extra e jornada incompleta (%d): %s.\n",
+		len(employees), strings.Join(names, ", ")))
+}
+
+// digestTable monta a planilha do relatório, com uma linha por colaborador
+func digestTable(digest *punchDigest) *export.Table {
+	table := &export.Table{
+		Sheet:   "Ocorrencias",
+		Headers: []string{"ID", "NOME", "CPF", "MATRICULA", "CENTRO_DE_CUSTO", "DATA", "BATIDAS", "TRABALHADO", "JORNADA", "DIFERENCA", "OCORRENCIAS"},
+	}
+	for _, offender := range digest.offenders {
+		punches := make([]string, len(offender.day.Punches))
+		for i, punch := range offender.day.Punches {
+			punches[i] = punch.Format("15:04")
+		}
+		issues := make([]string, len(offender.issues))
+		for i, issue := range offender.issues {
+			issues[i] = fmt.Sprintf("%s: %s", issue.Kind.Label(), issue.Message)
+		}
+		table.Rows = append(table.Rows, []interface{}{
+			offender.employee.ID,
+			employeeName(offender.employee),
+			offender.employee.CPF,
+			offender.employee.RegistrationNumber,
+			offender.employee.CostCenter,
+			digest.date.Format("02/01/2006"),
+			strings.Join(punches, " "),
+			duration.Format(offender.day.Worked.Seconds()),
+			duration.Format(offender.day.Expected.Seconds()),
+			duration.FormatSigned(offender.day.Difference().Seconds()),
+			strings.Join(issues, "; "),
+		})
+	}
+	return table
+}
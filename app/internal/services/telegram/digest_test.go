@@ -0,0 +1,203 @@
+package telegram
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/timecards"
+)
+
+// digestDate é uma terça-feira, dia útil nas regras padrão
+var digestDate = time.Date(2025, 3, 18, 0, 0, 0, 0, time.UTC)
+
+// punches monta as batidas do colaborador em digestDate
+func punches(employeeID int, times ...string) []models.TimeCard {
+	cards := make([]models.TimeCard, len(times))
+	for i, clock := range times {
+		cards[i] = models.TimeCard{EmployeeID: employeeID, Date: "2025-03-18", Time: clock}
+	}
+	return cards
+}
+
+func TestAnalyzeDigest(t *testing.T) {
+	employees := []models.Employee{
+		{ID: 1, FirstName: "Ana", WorkHours: "08:00"},
+		{ID: 2, FirstName: "Bia", WorkHours: "08:00"},
+		{ID: 3, FirstName: "Caio", WorkHours: "8h"},
+		{ID: 4, FirstName: "Duda", WorkHours: "oito horas"},
+		{ID: 5, FirstName: "Edu"},
+		{ID: 6, FirstName: "Fê", WorkHours: "08:00"},
+	}
+	var cards []models.TimeCard
+	cards = append(cards, punches(1, "08:00", "12:00", "13:00", "19:00")...) // Hora extra
+	cards = append(cards, punches(2, "08:00", "12:00", "13:00")...)          // Batida faltando
+	cards = append(cards, punches(4, "08:00", "17:00")...)                   // Sem intervalo, jornada não reconhecida
+	cards = append(cards, punches(5, "08:00", "12:00", "13:00", "18:00")...) // Sem jornada: nada a comparar
+	cards = append(cards, punches(6, "08:00", "12:00", "13:00", "16:00")...) // Jornada incompleta
+	cards = append(cards, models.TimeCard{EmployeeID: 3, Date: "2025-03-17", Time: "08:00"})
+
+	all := make([]string, len(timecards.IssueKinds))
+	for i, kind := range timecards.IssueKinds {
+		all[i] = string(kind)
+	}
+
+	tests := []struct {
+		name     string
+		reported []string
+		want     []string // "ID: ocorrências" de cada colaborador relatado
+	}{
+		{"regras padrão", []string{"batida_faltando", "sem_batidas", "hora_extra"}, []string{"1: hora_extra", "2: batida_faltando", "3: sem_batidas"}},
+		{"todas as regras", all, []string{"1: hora_extra", "2: batida_faltando", "3: sem_batidas", "4: sem_intervalo", "6: jornada_incompleta"}},
+		{"só sem intervalo", []string{"sem_intervalo"}, []string{"4: sem_intervalo"}},
+		{"só jornada incompleta", []string{"jornada_incompleta"}, []string{"6: jornada_incompleta"}},
+		{"regra desconhecida ignorada", []string{"atraso"}, nil},
+		{"sem regras", nil, nil},
+	}
+	for _, tt := range tests {
+		digest := analyzeDigest(digestDate, employees, cards, timecards.DefaultRules, tt.reported)
+		var got []string
+		for _, offender := range digest.offenders {
+			kinds := make([]string, len(offender.issues))
+			for i, issue := range offender.issues {
+				kinds[i] = string(issue.Kind)
+			}
+			got = append(got, fmt.Sprintf("%d: %s", offender.employee.ID, strings.Join(kinds, ",")))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: relatados = %v, esperado %v", tt.name, got, tt.want)
+		}
+		if digest.analyzed != len(employees) {
+			t.Errorf("%s: %d analisados, esperado %d", tt.name, digest.analyzed, len(employees))
+		}
+		if len(digest.unknownHours) != 1 || digest.unknownHours[0].ID != 4 {
+			t.Errorf("%s: jornada não reconhecida = %v, esperado só o colaborador 4", tt.name, digest.unknownHours)
+		}
+	}
+}
+
+// offenders cria n colaboradores com a ocorrência informada, com IDs a partir de first
+func offenders(first, n int, kind timecards.IssueKind) []digestOffender {
+	list := make([]digestOffender, n)
+	for i := range list {
+		list[i] = digestOffender{
+			employee: models.Employee{ID: first + i, FirstName: fmt.Sprint("Colaborador ", first+i)},
+			issues:   []timecards.Issue{{Kind: kind, Message: "ocorrência"}},
+		}
+	}
+	return list
+}
+
+func TestFormatDigest(t *testing.T) {
+	concat := func(lists ...[]digestOffender) []digestOffender {
+		var all []digestOffender
+		for _, list := range lists {
+			all = append(all, list...)
+		}
+		return all
+	}
+
+	tests := []struct {
+		name         string
+		offenders    []digestOffender
+		unknownHours []models.Employee
+		wantLines    int      // Ocorrências listadas
+		want         []string // Trechos esperados
+		notWant      []string // Trechos que não podem aparecer
+	}{
+		{
+			name:    "sem ocorrências",
+			want:    []string{"Ocorrências nas batidas de terça-feira, 18/03/2025\n", "Colaboradores analisados: 50\n\nNenhuma ocorrência."},
+			notWant: []string{"Com ocorrências", "/batidas", "Jornada do cadastro"},
+		},
+		{
+			name:      "seções na ordem de IssueKinds",
+			offenders: concat(offenders(1, 2, timecards.IssueOvertime), offenders(3, 1, timecards.IssueOddPunches)),
+			wantLines: 3,
+			want:      []string{"Com ocorrências: 3\n\nBatida faltando (1):\n- Colaborador 3 (ID 3): ocorrência\n\nHora extra (2):\n", "Use /batidas"},
+			notWant:   []string{"e mais", "Também na planilha"},
+		},
+		{
+			name:      "limite no meio de uma seção",
+			offenders: concat(offenders(1, 45, timecards.IssueOddPunches), offenders(100, 3, timecards.IssueNoPunches), offenders(200, 2, timecards.IssueOvertime)),
+			wantLines: digestMaxLines,
+			want:      []string{"Batida faltando (45):\n", "- Colaborador 40 (ID 40): ocorrência\n... e mais 5\n", "Também na planilha: Nenhuma batida (3), Hora extra (2).\n"},
+			notWant:   []string{"Colaborador 41 ", "Nenhuma batida (3):", "Hora extra (2):"},
+		},
+		{
+			name:      "limite no fim de uma seção",
+			offenders: concat(offenders(1, 40, timecards.IssueOddPunches), offenders(100, 3, timecards.IssueNoPunches)),
+			wantLines: digestMaxLines,
+			want:      []string{"- Colaborador 40 (ID 40): ocorrência\n\nTambém na planilha: Nenhuma batida (3).\n"},
+			notWant:   []string{"e mais", "Nenhuma batida (3):"},
+		},
+		{
+			name:      "limite na segunda seção",
+			offenders: concat(offenders(1, 30, timecards.IssueOddPunches), offenders(100, 15, timecards.IssueNoPunches), offenders(200, 1, timecards.IssueUndertime)),
+			wantLines: digestMaxLines,
+			want:      []string{"Nenhuma batida (15):\n", "- Colaborador 109 (ID 109): ocorrência\n... e mais 5\n", "Também na planilha: Jornada incompleta (1).\n"},
+		},
+		{
+			name:         "jornada não reconhecida",
+			offenders:    offenders(1, 1, timecards.IssueNoPunches),
+			unknownHours: []models.Employee{{ID: 7, FirstName: "Gil", WorkHours: "oito"}},
+			wantLines:    1,
+			want:         []string{"\nJornada do cadastro não reconhecida, sem verificação de hora extra e jornada incompleta (1): Gil (ID 7).\n"},
+		},
+		{
+			name: "jornada não reconhecida sem ocorrências",
+			unknownHours: func() []models.Employee {
+				var employees []models.Employee
+				for _, offender := range offenders(1, 12, timecards.IssueNoPunches) {
+					employees = append(employees, offender.employee)
+				}
+				return employees
+			}(),
+			want: []string{"Nenhuma ocorrência.\n\nJornada do cadastro não reconhecida", "(12): Colaborador 1 (ID 1),", "Colaborador 10 (ID 10), e mais 2."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := formatDigest(&punchDigest{date: digestDate, analyzed: 50, offenders: tt.offenders, unknownHours: tt.unknownHours})
+			if lines := strings.Count(text, "\n- "); lines != tt.wantLines {
+				t.Errorf("%d ocorrências listadas, esperado %d", lines, tt.wantLines)
+			}
+			for _, part := range tt.want {
+				if !strings.Contains(text, part) {
+					t.Errorf("texto sem %q:\n%s", part, text)
+				}
+			}
+			for _, part := range tt.notWant {
+				if strings.Contains(text, part) {
+					t.Errorf("texto com %q:\n%s", part, text)
+				}
+			}
+		})
+	}
+}
+
+func TestDigestTable(t *testing.T) {
+	employee := models.Employee{ID: 1, FirstName: "Ana", LastName: "Souza", CPF: "12345678900", RegistrationNumber: "A-17", CostCenter: "TI", WorkHours: "08:00"}
+	digest := analyzeDigest(digestDate, []models.Employee{employee},
+		punches(1, "13:00", "08:00", "19:30", "12:00"), timecards.DefaultRules, []string{"hora_extra", "sem_intervalo"})
+
+	table := digestTable(digest)
+	wantHeaders := []string{"ID", "NOME", "CPF", "MATRICULA", "CENTRO_DE_CUSTO", "DATA", "BATIDAS", "TRABALHADO", "JORNADA", "DIFERENCA", "OCORRENCIAS"}
+	if table.Sheet != "Ocorrencias" || !reflect.DeepEqual(table.Headers, wantHeaders) {
+		t.Errorf("aba = %q, cabeçalhos = %v", table.Sheet, table.Headers)
+	}
+	want := [][]interface{}{{
+		1, "Ana Souza", "12345678900", "A-17", "TI", "18/03/2025", "08:00 12:00 13:00 19:30", "10:30", "08:00", "+02:30",
+		"Sem intervalo: 06:30 contínuas sem intervalo, de 13:00 a 19:30; Hora extra: trabalhou 02:30 além da jornada",
+	}}
+	if !reflect.DeepEqual(table.Rows, want) {
+		t.Errorf("linhas = %v, esperado %v", table.Rows, want)
+	}
+
+	if rows := digestTable(&punchDigest{date: digestDate}).Rows; len(rows) != 0 {
+		t.Errorf("%d linhas sem ocorrências, esperado 0", len(rows))
+	}
+}
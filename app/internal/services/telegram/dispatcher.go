@@ -0,0 +1,108 @@
+package telegram
+
+import (
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// dispatcher distribui as atualizações entre um número fixo de workers, com
+// uma fila por chat: chats diferentes são atendidos em paralelo, enquanto as
+// mensagens de um mesmo chat são processadas na ordem em que chegaram. Cada
+// chat está, a cada momento, com no máximo um worker ou na fila de espera.
+type dispatcher struct {
+	handle  func(tgbotapi.Update)
+	workers int // Limite de workers e, portanto, de chats atendidos ao mesmo tempo
+
+	mu     sync.Mutex
+	queues map[int64][]tgbotapi.Update // Atualizações pendentes de cada chat em atendimento ou em espera
+	ready  []int64                     // Chats com atualizações pendentes aguardando um worker livre
+	active int                         // Workers em execução
+	wg     sync.WaitGroup
+}
+
+// newDispatcher cria um dispatcher que atende até workers chats simultaneamente
+func newDispatcher(workers int, handle func(tgbotapi.Update)) *dispatcher {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &dispatcher{
+		handle:  handle,
+		workers: workers,
+		queues:  make(map[int64][]tgbotapi.Update),
+	}
+}
+
+// dispatch enfileira a atualização no chat de origem. Um chat novo é entregue
+// a um worker novo enquanto houver vaga; sem vaga, espera o próximo worker
+// livre. Nenhuma goroutine é criada além do limite de workers.
+func (d *dispatcher) dispatch(update tgbotapi.Update) {
+	chatID := updateChatID(update)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	queue, pending := d.queues[chatID]
+	d.queues[chatID] = append(queue, update)
+	if pending {
+		// O chat já está com um worker ou aguardando um
+		return
+	}
+	if d.active < d.workers {
+		d.active++
+		d.wg.Add(1)
+		go d.work(chatID)
+		return
+	}
+	d.ready = append(d.ready, chatID)
+}
+
+// work processa uma atualização por vez, alternando entre os chats em espera
+// para que um chat com muitas mensagens não atrase os demais, e encerra quando
+// não há mais nada pendente
+func (d *dispatcher) work(chatID int64) {
+	defer d.wg.Done()
+
+	for {
+		d.mu.Lock()
+		queue := d.queues[chatID]
+		update := queue[0]
+		d.queues[chatID] = queue[1:]
+		d.mu.Unlock()
+
+		d.handle(update)
+
+		d.mu.Lock()
+		if len(d.queues[chatID]) == 0 {
+			delete(d.queues, chatID)
+		} else {
+			d.ready = append(d.ready, chatID)
+		}
+		if len(d.ready) == 0 {
+			d.active--
+			d.mu.Unlock()
+			return
+		}
+		chatID = d.ready[0]
+		d.ready = d.ready[1:]
+		d.mu.Unlock()
+	}
+}
+
+// wait aguarda o processamento de todas as atualizações já enfileiradas
+func (d *dispatcher) wait() {
+	d.wg.Wait()
+}
+
+// updateChatID identifica o chat de origem da atualização. Atualizações sem
+// chat compartilham a fila 0.
+func updateChatID(update tgbotapi.Update) int64 {
+	switch {
+	case update.Message != nil:
+		return update.Message.Chat.ID
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil:
+		return update.CallbackQuery.Message.Chat.ID
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,70 @@
+package telegram
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func chatUpdate(chatID int64, messageID int) tgbotapi.Update {
+	return tgbotapi.Update{Message: &tgbotapi.Message{MessageID: messageID, Chat: &tgbotapi.Chat{ID: chatID}}}
+}
+
+func TestDispatcherOrderAndLimit(t *testing.T) {
+	const workers, chats, perChat = 3, 20, 10
+
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	seen := make(map[int64][]int)
+	release := make(chan struct{})
+	d := newDispatcher(workers, func(update tgbotapi.Update) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		mu.Lock()
+		seen[update.Message.Chat.ID] = append(seen[update.Message.Chat.ID], update.Message.MessageID)
+		mu.Unlock()
+		running.Add(-1)
+	})
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < perChat; i++ {
+		for chat := int64(1); chat <= chats; chat++ {
+			d.dispatch(chatUpdate(chat, i))
+		}
+	}
+	// Com todos os workers ocupados, as atualizações ficam só nas filas
+	time.Sleep(20 * time.Millisecond)
+	if extra := runtime.NumGoroutine() - before; extra > workers {
+		t.Errorf("%d goroutines criadas para %d workers", extra, workers)
+	}
+	close(release)
+	d.wait()
+
+	if p := peak.Load(); p > workers {
+		t.Errorf("%d atualizações processadas ao mesmo tempo, limite %d", p, workers)
+	}
+	for chat := int64(1); chat <= chats; chat++ {
+		got := seen[chat]
+		if len(got) != perChat {
+			t.Fatalf("chat %d: %d atualizações processadas, esperado %d", chat, len(got), perChat)
+		}
+		for i, id := range got {
+			if id != i {
+				t.Fatalf("chat %d fora de ordem: %v", chat, got)
+			}
+		}
+	}
+	if len(d.queues) != 0 || len(d.ready) != 0 || d.active != 0 {
+		t.Errorf("estado após wait: %d filas, %d em espera, %d workers", len(d.queues), len(d.ready), d.active)
+	}
+}
@@ -0,0 +1,95 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/services/pontomais"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// maxEmployeeChoices é a quantidade de colaboradores oferecida quando a busca é ambígua
+const maxEmployeeChoices = 8
+
+// employeeDetailAttributes são os atributos pedidos à API quando o bot mostra
+// ou exporta os dados do colaborador, incluindo o centro de custo e a jornada
+var employeeDetailAttributes = append(append([]string{}, pontomais.DefaultEmployeeAttributes...), "cost_center", "work_hours")
+
+// resolveEmployee identifica o colaborador de um comando pelo ID, CPF,
+// matrícula, e-mail ou parte do nome. Quando a busca encontra mais de um, o bot
+// oferece botões com os dados "<action>:colaborador:<ID>:<args...>" e
+// resolveEmployee devolve false, assim como quando não encontra nenhum.
+func (b *Bot) resolveEmployee(chatID int64, query, action string, args ...string) (models.Employee, bool) {
+	employees, err := b.client.GetEmployees(b.ctx, pontomais.EmployeeFilter{Attributes: employeeDetailAttributes})
+	if err != nil {
+		utils.Logger.Printf("Erro ao buscar colaboradores: %v", err)
+		b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Erro ao buscar colaboradores: %s", describeError(err))))
+		return models.Employee{}, false
+	}
+
+	results := searchEmployees(employees, query)
+	switch {
+	case len(results) == 0:
+		b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Nenhum colaborador ativo encontrado para \"%s\". Use /buscar para procurar.", query)))
+		return models.Employee{}, false
+	case len(results) == 1:
+		return results[0], true
+	case employeeScore(results[0], query) >= scoreExactID && employeeScore(results[1], query) < scoreExactID:
+		// Um identificador exato vence as correspondências parciais de nome
+		return results[0], true
+	}
+
+	text := fmt.Sprintf("Encontrei %d colaboradores para \"%s\". Escolha um:", len(results), query)
+	if len(results) > maxEmployeeChoices {
+		text += fmt.Sprintf("\n\n(mostrando os %d mais relevantes; refine a busca se o colaborador não estiver na lista)", maxEmployeeChoices)
+	}
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, employee := range results[:min(len(results), maxEmployeeChoices)] {
+		data := callbackData(action, append([]string{"colaborador", strconv.Itoa(employee.ID)}, args...)...)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(employeeLabel(employee), data)))
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.api.Send(msg)
+	return models.Employee{}, false
+}
+
+// employeeByID busca um colaborador ativo pelo ID, para os botões que só
+// carregam o número
+func (b *Bot) employeeByID(id int) (models.Employee, error) {
+	for employee, err := range b.client.Employees(b.ctx, pontomais.EmployeeFilter{Attributes: employeeDetailAttributes}) {
+		if err != nil {
+			return models.Employee{}, err
+		}
+		if employee.ID == id {
+			return employee, nil
+		}
+	}
+	return models.Employee{}, fmt.Errorf("colaborador %d não encontrado entre os ativos", id)
+}
+
+// chosenEmployee trata o botão gerado por resolveEmployee, devolvendo o
+// colaborador escolhido e os argumentos extras do botão
+func (b *Bot) chosenEmployee(query *tgbotapi.CallbackQuery, args []string) (models.Employee, []string, bool) {
+	if len(args) < 2 || args[0] != "colaborador" {
+		b.answerCallback(query, "Botão inválido.")
+		return models.Employee{}, nil, false
+	}
+	id, err := strconv.Atoi(args[1])
+	if err != nil {
+		b.answerCallback(query, "Botão inválido.")
+		return models.Employee{}, nil, false
+	}
+
+	employee, err := b.employeeByID(id)
+	if err != nil {
+		utils.Logger.Printf("Erro ao buscar o colaborador %d: %v", id, err)
+		b.answerCallback(query, "Colaborador não encontrado.")
+		return models.Employee{}, nil, false
+	}
+	b.answerCallback(query, "")
+	b.editMessage(query.Message.Chat.ID, query.Message.MessageID, fmt.Sprintf("Colaborador: %s", employeeLabel(employee)), nil)
+	return employee, args[2:], true
+}
@@ -0,0 +1,167 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+	"github.com/jeffemart/PontoGo/app/internal/journal"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// pendingEntryTTL é por quanto tempo um lançamento aguarda a confirmação
+const pendingEntryTTL = 10 * time.Minute
+
+// pendingEntry é um lançamento do /criar ou /editar aguardando confirmação
+type pendingEntry struct {
+	chatID  int64
+	entryID string // ID do lançamento editado; vazio na criação
+	entry   models.TimeBalanceEntry
+	expires time.Time
+}
+
+// confirmEntry guarda o lançamento e pede confirmação, repetindo a quantidade
+// em HH:MM para que o operador confira como ela foi interpretada
+func (b *Bot) confirmEntry(chatID int64, entryID string, entry models.TimeBalanceEntry) {
+	b.mu.Lock()
+	now := time.Now()
+	for id, pending := range b.pendingEntries {
+		if now.After(pending.expires) {
+			delete(b.pendingEntries, id)
+		}
+	}
+	b.nextPendingEntry++
+	id := b.nextPendingEntry
+	b.pendingEntries[id] = &pendingEntry{chatID: chatID, entryID: entryID, entry: entry, expires: now.Add(pendingEntryTTL)}
+	b.mu.Unlock()
+
+	question := "Confirma a criação do lançamento?"
+	if entryID != "" {
+		question = fmt.Sprintf("Confirma a edição do lançamento %s?", entryID)
+	}
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("%s\n\n%s", question, formatEntry(entry)))
+	msg.ReplyMarkup = confirmKeyboard("lancamento", strconv.Itoa(id))
+	b.api.Send(msg)
+}
+
+// takePendingEntry remove e devolve o lançamento pendente do chat, se ainda não expirou
+func (b *Bot) takePendingEntry(chatID int64, id int) (*pendingEntry, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	pending, ok := b.pendingEntries[id]
+	if !ok || pending.chatID != chatID {
+		return nil, false
+	}
+	delete(b.pendingEntries, id)
+	if time.Now().After(pending.expires) {
+		return nil, false
+	}
+	return pending, true
+}
+
+// handleEntryCallback trata os botões de confirmação do /criar e do /editar
+func (b *Bot) handleEntryCallback(query *tgbotapi.CallbackQuery, args []string) {
+	chatID := query.Message.Chat.ID
+	messageID := query.Message.MessageID
+	if len(args) != 2 {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+	id, err := strconv.Atoi(args[1])
+	if err != nil {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+
+	pending, ok := b.takePendingEntry(chatID, id)
+	if !ok {
+		b.answerCallback(query, "Este lançamento expirou.")
+		b.editMessage(chatID, messageID, "Este lançamento expirou ou já foi processado. Envie o comando novamente.", nil)
+		return
+	}
+
+	if args[0] != "confirmar" {
+		utils.Logger.Printf("Lançamento cancelado por %s no chat ID: %d", userLabel(query.From), chatID)
+		b.answerCallback(query, "Lançamento cancelado.")
+		b.editMessage(chatID, messageID, "Lançamento cancelado.", nil)
+		return
+	}
+
+	if pending.entryID != "" {
+		b.answerCallback(query, "Processando atualização do banco de horas...")
+		b.editMessage(chatID, messageID, b.submitUpdate(chatID, query.From, pending.entryID, pending.entry), nil)
+		return
+	}
+	b.answerCallback(query, "Processando criação do lançamento no banco de horas...")
+	b.editMessage(chatID, messageID, b.submitCreate(chatID, query.From, pending.entry), nil)
+}
+
+// submitCreate cria o lançamento confirmado e devolve a mensagem de resultado
+func (b *Bot) submitCreate(chatID int64, user *tgbotapi.User, entry models.TimeBalanceEntry) string {
+	utils.Logger.Printf("Criando lançamento no banco de horas para o funcionário ID: %s", entry.EmployeeID)
+	created, err := b.client.CreateTimeBalanceEntry(b.ctx, entry)
+	if err == nil && created.ID == 0 {
+		err = errMissingEntryID
+	}
+	if err != nil {
+		utils.Logger.Printf("Erro ao criar o lançamento no banco de horas: %v", err)
+		return fmt.Sprintf("Erro ao criar o lançamento no banco de horas: %s", describeError(err))
+	}
+
+	b.recordOperation(journal.Operation{
+		Kind:    journal.OperationCreate,
+		ChatID:  chatID,
+		User:    userLabel(user),
+		EntryID: created.ID,
+		Current: &entry,
+	})
+
+	utils.Logger.Printf("Lançamento %d no banco de horas criado com sucesso para o funcionário ID: %s", created.ID, entry.EmployeeID)
+	return fmt.Sprintf("Lançamento no banco de horas criado com sucesso!\n\nID do lançamento: %d\n%s", created.ID, formatEntry(entry))
+}
+
+// submitUpdate edita o lançamento confirmado e devolve a mensagem de resultado
+func (b *Bot) submitUpdate(chatID int64, user *tgbotapi.User, entryID string, entry models.TimeBalanceEntry) string {
+	// Busca os valores atuais para que a edição possa ser desfeita
+	previous, err := b.client.GetTimeBalanceEntry(b.ctx, entryID)
+	if err != nil {
+		utils.Logger.Printf("Erro ao buscar o lançamento %s antes da edição: %v", entryID, err)
+		return fmt.Sprintf("Erro ao buscar o lançamento: %s", describeError(err))
+	}
+
+	utils.Logger.Printf("Atualizando banco de horas para o ID: %s", entryID)
+	if _, err := b.client.UpdateTimeBalanceEntry(b.ctx, entryID, entry); err != nil {
+		utils.Logger.Printf("Erro ao atualizar o banco de horas: %v", err)
+		return fmt.Sprintf("Erro ao atualizar o banco de horas: %s", describeError(err))
+	}
+
+	previousEntry := previous.Entry()
+	b.recordOperation(journal.Operation{
+		Kind:     journal.OperationUpdate,
+		ChatID:   chatID,
+		User:     userLabel(user),
+		EntryID:  previous.ID,
+		Previous: &previousEntry,
+		Current:  &entry,
+	})
+
+	utils.Logger.Printf("Banco de horas atualizado com sucesso para o ID: %s", entryID)
+	return fmt.Sprintf("Banco de horas atualizado com sucesso!\n\nID: %s\n%s", entryID, formatEntry(entry))
+}
+
+// formatEntry descreve um lançamento ainda não enviado (ou recém-enviado) à API
+func formatEntry(entry models.TimeBalanceEntry) string {
+	date := entry.Date
+	if parsed, err := time.Parse("02/01/2006", entry.Date); err == nil {
+		date = parsed.Format("2006-01-02")
+	}
+	text := fmt.Sprintf("Quantidade: %s\nData: %s\nObservação: %s\nRetirada: %t",
+		duration.Describe(entry.Amount), date, entry.Observation, entry.Withdraw)
+	if entry.EmployeeID != "" {
+		text = fmt.Sprintf("Funcionário ID: %s\n%s", entry.EmployeeID, text)
+	}
+	return text
+}
@@ -0,0 +1,62 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jeffemart/PontoGo/app/internal/services/pontomais"
+)
+
+// errMissingEntryID indica que o Ponto Mais confirmou a criação de um
+// lançamento sem devolver o ID, que o diário e o ledger precisam para desfazê-lo
+var errMissingEntryID = errors.New("o Ponto Mais não devolveu o ID do lançamento criado")
+
+// rejectedByAPI indica se o Ponto Mais recusou a requisição de forma definitiva
+// (uma resposta 4xx que não seja 429), ou seja, se ela certamente não teve efeito.
+// Tempo esgotado, falhas de conexão e erros 5xx ou 429 deixam a dúvida.
+func rejectedByAPI(err error) bool {
+	var apiErr *pontomais.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests
+}
+
+// describeError converte um erro do cliente do Ponto Mais em uma mensagem
+// compreensível para os operadores do bot
+func describeError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "a API do Ponto Mais não respondeu a tempo"
+	}
+
+	var apiErr *pontomais.APIError
+	if !errors.As(err, &apiErr) {
+		return err.Error()
+	}
+
+	reason := apiErr.Reason()
+	var summary string
+	switch {
+	case errors.Is(err, pontomais.ErrUnauthorized):
+		summary = "token do Ponto Mais inválido ou expirado"
+	case errors.Is(err, pontomais.ErrForbidden):
+		summary = "o token do Ponto Mais não tem permissão para esta operação"
+	case errors.Is(err, pontomais.ErrNotFound):
+		summary = "registro não encontrado no Ponto Mais"
+	case errors.Is(err, pontomais.ErrValidation):
+		summary = "dados rejeitados pelo Ponto Mais"
+	case errors.Is(err, pontomais.ErrRateLimited):
+		summary = "limite de requisições do Ponto Mais excedido, tente novamente em instantes"
+	case errors.Is(err, pontomais.ErrServer):
+		summary = "o Ponto Mais está com instabilidade, tente novamente mais tarde"
+	default:
+		summary = fmt.Sprintf("resposta inesperada do Ponto Mais (%s)", apiErr.Status)
+	}
+
+	if reason == "" {
+		return summary
+	}
+	return fmt.Sprintf("%s (%s)", summary, reason)
+}
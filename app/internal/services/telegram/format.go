@@ -0,0 +1,27 @@
+package telegram
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// formatEntryRecord descreve um lançamento do banco de horas para exibição no chat
+func formatEntryRecord(record *models.TimeBalanceEntryRecord) string {
+	date := record.Date
+	if parsed, err := record.ParsedDate(); err == nil {
+		date = parsed.Format("2006-01-02")
+	}
+	return fmt.Sprintf("ID: %d\nFuncionário ID: %d\nQuantidade: %s\nData: %s\nObservação: %s\nRetirada: %t",
+		record.ID, record.EmployeeID, duration.Describe(record.Amount), date, record.Observation, record.Withdraw)
+}
+
+// userLabel identifica quem executou uma ação, para os logs de auditoria
+func userLabel(user *tgbotapi.User) string {
+	if user == nil {
+		return "desconhecido"
+	}
+	return fmt.Sprintf("%s (ID %d)", user.String(), user.ID)
+}
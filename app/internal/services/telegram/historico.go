@@ -0,0 +1,265 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+	"github.com/jeffemart/PontoGo/app/internal/export"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// historyPageSize é a quantidade de lançamentos por página do /historico
+const historyPageSize = 10
+
+// historyDateLayout é o formato das datas nos botões do /historico
+const historyDateLayout = "2006-01-02"
+
+// historyModeList indica, nos botões de escolha do colaborador, que o
+// histórico deve ser mostrado no chat e não exportado
+const historyModeList = "lista"
+
+// handleHistory trata o comando /historico <colaborador> [de] [ate] [xlsx|csv]
+func (b *Bot) handleHistory(message *tgbotapi.Message) {
+	utils.Logger.Printf("Comando /historico recebido do chat ID: %d", message.Chat.ID)
+	args, ok := b.parseCommand(message, historyCommand)
+	if !ok {
+		return
+	}
+
+	start, end, err := historyPeriod(args.Date("de"), args.Date("ate"), time.Now())
+	if err != nil {
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, err.Error()))
+		return
+	}
+
+	mode := strings.ToLower(args.String("formato"))
+	switch mode {
+	case "":
+		mode = historyModeList
+	case "xlsx", "csv":
+	default:
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Formato inválido '%s': use xlsx ou csv. Nomes com espaços vão entre aspas, ex.: /historico \"maria souza\"", args.String("formato"))))
+		return
+	}
+
+	employee, ok := b.resolveEmployee(message.Chat.ID, args.String("colaborador"), "historico",
+		start.Format(historyDateLayout), end.Format(historyDateLayout), mode)
+	if !ok {
+		return
+	}
+	b.sendHistory(message.Chat.ID, employee, start, end, mode)
+}
+
+// handleHistoryCallback trata os botões do /historico:
+//   - "colaborador:<ID>:<de>:<ate>:<modo>": escolha do colaborador
+//   - "pagina:<ID>:<de>:<ate>:<página>": navegação entre as páginas
+//   - "xlsx:<ID>:<de>:<ate>" e "csv:<ID>:<de>:<ate>": exportação do período
+func (b *Bot) handleHistoryCallback(query *tgbotapi.CallbackQuery, args []string) {
+	chatID := query.Message.Chat.ID
+	if len(args) > 0 && args[0] == "colaborador" {
+		employee, extra, ok := b.chosenEmployee(query, args)
+		if !ok {
+			return
+		}
+		start, end, err := historyRange(extra)
+		if err != nil || len(extra) != 3 {
+			utils.Logger.Printf("Botão do /historico inválido: %s", query.Data)
+			return
+		}
+		b.sendHistory(chatID, employee, start, end, extra[2])
+		return
+	}
+
+	if len(args) < 4 {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+	employeeID, err := strconv.Atoi(args[1])
+	if err != nil {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+	start, end, err := historyRange(args[2:])
+	if err != nil {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+	employee, err := b.employeeByID(employeeID)
+	if err != nil {
+		utils.Logger.Printf("Erro ao buscar o colaborador %d: %v", employeeID, err)
+		b.answerCallback(query, "Colaborador não encontrado.")
+		return
+	}
+
+	switch args[0] {
+	case "pagina":
+		page, err := strconv.Atoi(args[len(args)-1])
+		if err != nil || len(args) != 5 {
+			b.answerCallback(query, "Botão inválido.")
+			return
+		}
+		statement, err := b.client.GetTimeBalanceStatement(b.ctx, employee.ID, start, end)
+		if err != nil {
+			utils.Logger.Printf("Erro ao consultar o histórico do colaborador %d: %v", employee.ID, err)
+			b.answerCallback(query, "Erro ao consultar o histórico.")
+			return
+		}
+		b.answerCallback(query, "")
+		text, markup := historyPage(employee, statement, page)
+		b.editMessage(chatID, query.Message.MessageID, text, markup)
+
+	case "xlsx", "csv":
+		b.answerCallback(query, "Gerando o arquivo...")
+		b.sendHistory(chatID, employee, start, end, args[0])
+
+	default:
+		b.answerCallback(query, "Ação desconhecida.")
+	}
+}
+
+// historyPeriod completa o período do /historico: sem data final, vai até
+// now; sem data inicial, começa no primeiro dia do mês da data final
+func historyPeriod(start, end, now time.Time) (time.Time, time.Time, error) {
+	if end.IsZero() {
+		end = now
+	}
+	if start.IsZero() {
+		start = time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, end.Location())
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("A data inicial (%s) é posterior à final (%s).", start.Format("02/01/2006"), end.Format("02/01/2006"))
+	}
+	return start, end, nil
+}
+
+// historyRange interpreta as datas inicial e final dos botões do /historico
+func historyRange(args []string) (time.Time, time.Time, error) {
+	if len(args) < 2 {
+		return time.Time{}, time.Time{}, fmt.Errorf("período ausente")
+	}
+	start, err := time.Parse(historyDateLayout, args[0])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := time.Parse(historyDateLayout, args[1])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, end, nil
+}
+
+// sendHistory envia o histórico do colaborador no período: a primeira página
+// no chat (modo "lista") ou o período inteiro como .xlsx ou .csv
+func (b *Bot) sendHistory(chatID int64, employee models.Employee, start, end time.Time, mode string) {
+	statement, err := b.client.GetTimeBalanceStatement(b.ctx, employee.ID, start, end)
+	if err != nil {
+		utils.Logger.Printf("Erro ao consultar o histórico do colaborador %d: %v", employee.ID, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Erro ao consultar o histórico: %s", describeError(err))))
+		return
+	}
+	utils.Logger.Printf("Histórico do colaborador %d de %s a %s: %d lançamentos",
+		employee.ID, start.Format("02/01/2006"), end.Format("02/01/2006"), len(statement.Items))
+
+	if mode == historyModeList {
+		text, markup := historyPage(employee, statement, 0)
+		msg := tgbotapi.NewMessage(chatID, text)
+		if markup != nil {
+			msg.ReplyMarkup = *markup
+		}
+		b.api.Send(msg)
+		return
+	}
+
+	data, err := historyTable(statement).Encode(mode)
+	if err != nil {
+		utils.Logger.Printf("Erro ao gerar o histórico em %s: %v", mode, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, "Não foi possível gerar o arquivo do histórico."))
+		return
+	}
+	name := fmt.Sprintf("historico_%d_%s_%s.%s", employee.ID, statement.Start.Format(historyDateLayout), statement.End.Format(historyDateLayout), mode)
+	doc := tgbotapi.NewDocumentUpload(chatID, tgbotapi.FileBytes{Name: name, Bytes: data})
+	doc.Caption = fmt.Sprintf("Histórico de %s de %s a %s: %d lançamentos. Saldo anterior %s, saldo final %s.",
+		employeeLabel(employee), statement.Start.Format("02/01/2006"), statement.End.Format("02/01/2006"), len(statement.Items),
+		duration.FormatSigned(statement.OpeningBalance), duration.FormatSigned(statement.ClosingBalance()))
+	if _, err := b.api.Send(doc); err != nil {
+		utils.Logger.Printf("Erro ao enviar o histórico do colaborador %d: %v", employee.ID, err)
+	}
+}
+
+// historyPage monta o texto e os botões de uma página do histórico, com a
+// página a partir de 0. Sem lançamentos, não há botões.
+func historyPage(employee models.Employee, statement *models.TimeBalanceStatement, page int) (string, *tgbotapi.InlineKeyboardMarkup) {
+	pages := (len(statement.Items) + historyPageSize - 1) / historyPageSize
+	page = max(min(page, pages-1), 0)
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Histórico de %s\n", employeeLabel(employee)))
+	text.WriteString(fmt.Sprintf("Período: %s a %s\n\n", statement.Start.Format("02/01/2006"), statement.End.Format("02/01/2006")))
+	text.WriteString(fmt.Sprintf("Saldo anterior: %s\n", duration.FormatSigned(statement.OpeningBalance)))
+	text.WriteString(fmt.Sprintf("Créditos: %s\n", duration.FormatSigned(statement.Credits)))
+	text.WriteString(fmt.Sprintf("Débitos: %s\n", duration.FormatSigned(-statement.Debits)))
+	text.WriteString(fmt.Sprintf("Saldo final: %s\n", duration.FormatSigned(statement.ClosingBalance())))
+
+	if len(statement.Items) == 0 {
+		text.WriteString("\nNenhum lançamento no período.")
+		return text.String(), nil
+	}
+
+	text.WriteString(fmt.Sprintf("\n%d lançamentos", len(statement.Items)))
+	if pages > 1 {
+		text.WriteString(fmt.Sprintf(" (página %d de %d)", page+1, pages))
+	}
+	text.WriteString(":\n")
+	for _, item := range statement.Items[page*historyPageSize : min((page+1)*historyPageSize, len(statement.Items))] {
+		text.WriteString(fmt.Sprintf("%s (saldo %s)\n", formatEntryLine(item.Entry), duration.FormatSigned(item.Balance)))
+	}
+
+	id := strconv.Itoa(employee.ID)
+	start, end := statement.Start.Format(historyDateLayout), statement.End.Format(historyDateLayout)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if nav := pageButtons("historico", page, pages, id, start, end); len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Exportar .xlsx", callbackData("historico", "xlsx", id, start, end)),
+		tgbotapi.NewInlineKeyboardButtonData("Exportar .csv", callbackData("historico", "csv", id, start, end)),
+	))
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return text.String(), &markup
+}
+
+// historyTable monta a tabela exportada pelo /historico, com uma linha por
+// lançamento e o saldo total após cada um
+func historyTable(statement *models.TimeBalanceStatement) *export.Table {
+	table := &export.Table{
+		Sheet:   "Historico",
+		Headers: []string{"LANCAMENTO", "DATA", "TIPO", "HORAS", "SEGUNDOS", "OBSERVACAO", "SALDO", "SALDO_SEGUNDOS"},
+	}
+	for _, item := range statement.Items {
+		entry := item.Entry
+		date := entry.Date
+		if parsed, err := entry.ParsedDate(); err == nil {
+			date = parsed.Format("02/01/2006")
+		}
+		kind := "Crédito"
+		if entry.Withdraw {
+			kind = "Débito"
+		}
+		table.Rows = append(table.Rows, []interface{}{
+			entry.ID,
+			date,
+			kind,
+			duration.FormatSigned(entry.SignedAmount()),
+			entry.SignedAmount(),
+			entry.Observation,
+			duration.FormatSigned(item.Balance),
+			item.Balance,
+		})
+	}
+	return table
+}
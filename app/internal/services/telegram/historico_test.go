@@ -0,0 +1,158 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+func TestHistoryPeriod(t *testing.T) {
+	brt := time.FixedZone("BRT", -3*3600)
+	now := time.Date(2025, 3, 18, 15, 30, 0, 0, brt)
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, brt) }
+
+	tests := []struct {
+		name       string
+		start, end time.Time // Datas do comando; zero se omitidas
+		wantStart  string
+		wantEnd    string
+		wantErr    string
+	}{
+		{name: "sem datas, mês atual até hoje", wantStart: "01/03/2025", wantEnd: "18/03/2025"},
+		{name: "só a inicial", start: day(2, 10), wantStart: "10/02/2025", wantEnd: "18/03/2025"},
+		{name: "só a final, desde o início do mês dela", end: day(1, 20), wantStart: "01/01/2025", wantEnd: "20/01/2025"},
+		{name: "as duas datas", start: day(1, 5), end: day(2, 28), wantStart: "05/01/2025", wantEnd: "28/02/2025"},
+		{name: "um único dia", start: day(3, 10), end: day(3, 10), wantStart: "10/03/2025", wantEnd: "10/03/2025"},
+		{name: "inicial hoje sem final", start: day(3, 18), wantStart: "18/03/2025", wantEnd: "18/03/2025"},
+		{name: "inicial depois da final", start: day(3, 10), end: day(3, 9), wantErr: "A data inicial (10/03/2025) é posterior à final (09/03/2025)."},
+		{name: "inicial no futuro sem final", start: day(4, 1), wantErr: "posterior à final (18/03/2025)"},
+	}
+	for _, tt := range tests {
+		start, end, err := historyPeriod(tt.start, tt.end, now)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: erro = %v, esperado %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got, want := start.Format("02/01/2006")+" a "+end.Format("02/01/2006"), tt.wantStart+" a "+tt.wantEnd; got != want {
+			t.Errorf("%s: período = %s, esperado %s", tt.name, got, want)
+		}
+		if start.Location() != brt {
+			t.Errorf("%s: fuso = %s, esperado BRT", tt.name, start.Location())
+		}
+	}
+}
+
+func TestHistoryRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantStart string
+		wantEnd   string
+		wantErr   bool
+	}{
+		{"período", []string{"2025-03-01", "2025-03-31"}, "2025-03-01", "2025-03-31", false},
+		{"argumentos extras ignorados", []string{"2025-03-01", "2025-03-31", "2"}, "2025-03-01", "2025-03-31", false},
+		{"sem a data final", []string{"2025-03-01"}, "", "", true},
+		{"sem datas", nil, "", "", true},
+		{"data no formato do chat", []string{"01/03/2025", "2025-03-31"}, "", "", true},
+		{"data final inválida", []string{"2025-03-01", "2025-02-30"}, "", "", true},
+	}
+	for _, tt := range tests {
+		start, end, err := historyRange(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: erro = %v, esperado erro %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (start.Format(historyDateLayout) != tt.wantStart || end.Format(historyDateLayout) != tt.wantEnd) {
+			t.Errorf("%s: período = %s a %s, esperado %s a %s", tt.name,
+				start.Format(historyDateLayout), end.Format(historyDateLayout), tt.wantStart, tt.wantEnd)
+		}
+	}
+}
+
+func TestHistoryFiltering(t *testing.T) {
+	entries := []models.TimeBalanceEntryRecord{
+		{ID: 1, Date: "2025-02-28", Amount: 3600},
+		{ID: 2, Date: "01/03/2025", Amount: 7200},
+		{ID: 3, Date: "2025-03-15T00:00:00Z", Amount: 1800, Withdraw: true},
+		{ID: 4, Date: "2025-03-31", Amount: 600},
+		{ID: 5, Date: "2025-04-01", Amount: 900},
+		{ID: 6, Date: "sem data", Amount: 60},
+	}
+	var endDate string
+	b, fake := newTestBot(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endDate = r.URL.Query().Get("end_date")
+		// Devolve todos os lançamentos: o período é filtrado pelo bot
+		json.NewEncoder(w).Encode(models.TimeBalanceEntriesResponse{TimeBalanceEntries: entries, Meta: &models.PaginationMeta{TotalPages: 1}})
+	}))
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		wantIDs    []string
+		wantLines  []string
+	}{
+		{"mês com os dois limites", day(3, 1), day(3, 31), []string{"2", "3", "4"},
+			[]string{"Saldo anterior: +01:01", "Créditos: +02:10", "Débitos: -00:30", "Saldo final: +02:41", "#4 - 31/03/2025 - +00:10 (saldo +02:41)"}},
+		{"um dia", day(3, 15), day(3, 15), []string{"3"},
+			[]string{"Saldo anterior: +03:01", "#3 - 15/03/2025 - -00:30 (saldo +02:31)", "Saldo final: +02:31"}},
+		{"sem lançamentos", day(1, 1), day(1, 31), nil,
+			[]string{"Saldo anterior: +00:01", "Nenhum lançamento no período."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.mu.Lock()
+			fake.calls = nil
+			fake.mu.Unlock()
+
+			b.sendHistory(testChatID, models.Employee{ID: 42, FirstName: "Ana"}, tt.start, tt.end, historyModeList)
+
+			if endDate != tt.end.Format("2006-01-02") {
+				t.Errorf("end_date = %q, esperado %s", endDate, tt.end.Format("2006-01-02"))
+			}
+			texts := fake.texts()
+			if len(texts) != 1 {
+				t.Fatalf("mensagens = %q", texts)
+			}
+			var ids []string
+			for _, match := range regexp.MustCompile(`(?m)^#(\d+) `).FindAllStringSubmatch(texts[0], -1) {
+				ids = append(ids, match[1])
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("lançamentos = %v, esperado %v", ids, tt.wantIDs)
+			}
+			for _, line := range tt.wantLines {
+				if !strings.Contains(texts[0], line) {
+					t.Errorf("texto sem %q:\n%s", line, texts[0])
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryTable(t *testing.T) {
+	statement := &models.TimeBalanceStatement{Items: []models.TimeBalanceHistoryItem{
+		{Entry: models.TimeBalanceEntryRecord{ID: 2, Date: "2025-03-01", Amount: 7200, Observation: "Plantão"}, Balance: 10800},
+		{Entry: models.TimeBalanceEntryRecord{ID: 3, Date: "15/03/2025", Amount: 1800, Withdraw: true}, Balance: 9000},
+	}}
+	want := [][]interface{}{
+		{2, "01/03/2025", "Crédito", "+02:00", 7200.0, "Plantão", "+03:00", 10800.0},
+		{3, "15/03/2025", "Débito", "-00:30", -1800.0, "", "+02:30", 9000.0},
+	}
+	if got := historyTable(statement).Rows; !reflect.DeepEqual(got, want) {
+		t.Errorf("linhas = %v, esperado %v", got, want)
+	}
+}
@@ -0,0 +1,326 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/importer"
+	"github.com/jeffemart/PontoGo/app/internal/jobs"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// Tempo que os jobs finalizados ficam guardados antes de serem removidos
+const jobRetention = 30 * 24 * time.Hour
+
+// Tempo que uma planilha validada aguarda a confirmação antes de ser descartada
+const pendingJobRetention = time.Hour
+
+// Intervalo entre as limpezas dos jobs antigos
+const jobPruneInterval = 10 * time.Minute
+
+// Quantidade de jobs listados pelo /status sem argumentos
+const jobStatusListSize = 5
+
+// resumeJobs retoma os jobs que estavam em execução quando o bot parou
+func (b *Bot) resumeJobs() {
+	b.pruneJobs()
+
+	for _, job := range b.jobs.Running() {
+		utils.Logger.Printf("Retomando a importação #%d na linha %d", job.ID, job.Cursor)
+		b.api.Send(tgbotapi.NewMessage(job.ChatID, fmt.Sprintf("O bot foi reiniciado. Retomando a importação #%d de onde parou...", job.ID)))
+		b.startJob(job.ID)
+	}
+}
+
+// pruneJobs remove os jobs finalizados antigos e as planilhas que não foram confirmadas a tempo
+func (b *Bot) pruneJobs() {
+	if err := b.jobs.Prune(jobRetention, pendingJobRetention); err != nil {
+		utils.Logger.Printf("Erro ao remover jobs antigos: %v", err)
+	}
+}
+
+// pruneJobsPeriodically executa pruneJobs a cada jobPruneInterval até ctx ser cancelado
+func (b *Bot) pruneJobsPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(jobPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.pruneJobs()
+		}
+	}
+}
+
+// startJob processa o job em segundo plano. O encerramento do bot aguarda o
+// término da linha em andamento.
+func (b *Bot) startJob(id int) {
+	b.runningJobs.Add(1)
+	go func() {
+		defer b.runningJobs.Done()
+		b.runJob(id)
+	}()
+}
+
+// runJob processa as linhas pendentes de um job a partir do cursor salvo,
+// gravando o progresso após cada linha. Se o bot estiver encerrando, para na
+// linha atual e deixa o job em execução para ser retomado.
+func (b *Bot) runJob(id int) {
+	job, err := b.jobs.Get(id)
+	if err != nil {
+		utils.Logger.Printf("Erro ao carregar a importação #%d: %v", id, err)
+		return
+	}
+
+	// Relê a planilha persistida com os apelidos da validação; as linhas ficam
+	// na mesma ordem do job
+	mapping := b.mapping
+	if job.Mapping != nil {
+		mapping = importer.Mapping(job.Mapping)
+	}
+	sheet, err := importer.ParseFile(job.FilePath, importer.Options{Sheet: job.Sheet, Mapping: mapping})
+	if err == nil && !sameRows(sheet, job) {
+		err = fmt.Errorf("a planilha salva não corresponde ao job")
+	}
+	if err != nil {
+		utils.Logger.Printf("Erro ao reabrir a planilha da importação #%d: %v", id, err)
+		b.finishJob(id, jobs.StateFailed, err.Error(), nil)
+		return
+	}
+
+	// Usa os colaboradores resolvidos na validação, sem consultar a API de novo.
+	// Jobs gravados antes dessa informação existir só têm linhas com ID.
+	for i := range sheet.Rows {
+		row := &sheet.Rows[i]
+		row.EmployeeID = job.Rows[i].EmployeeID
+		if row.EmployeeID == "" && row.Ref.Kind == importer.RefID {
+			row.EmployeeID = row.Ref.Value
+		}
+	}
+
+	b.api.Send(tgbotapi.NewMessage(job.ChatID, "Processando lançamentos no banco de horas. Isso pode levar alguns instantes..."))
+
+	for cursor := job.Cursor; cursor < len(sheet.Rows); cursor++ {
+		if b.ctx.Err() != nil {
+			utils.Logger.Printf("Importação #%d interrompida na linha %d pelo encerramento do bot", id, cursor)
+			return
+		}
+
+		// Confere a cada linha se o operador pediu o cancelamento
+		current, err := b.jobs.Get(id)
+		if err != nil {
+			utils.Logger.Printf("Erro ao carregar a importação #%d: %v", id, err)
+			return
+		}
+		if current.CancelRequested {
+			b.finishJob(id, jobs.StateCancelled, "", sheet)
+			return
+		}
+
+		row := sheet.Rows[cursor]
+		var status jobs.RowStatus
+		var entryID int
+		var rowErr error
+		if current.Rows[cursor].Status == jobs.RowPending {
+			status, entryID, rowErr = b.applyRow(b.ctx, job.ChatID, job.User, sheet.Checksum, row)
+			if rowErr != nil && b.ctx.Err() != nil {
+				// O envio foi interrompido pelo encerramento: a linha continua
+				// pendente e é conferida no ledger quando o job for retomado
+				utils.Logger.Printf("Importação #%d interrompida na linha %d pelo encerramento do bot", id, cursor)
+				return
+			}
+			if rowErr != nil {
+				utils.Logger.Printf("%s: %s", row.Label(), describeError(rowErr))
+			}
+		}
+
+		_, err = b.jobs.Update(id, func(job *jobs.Job) error {
+			if status != "" {
+				job.Rows[cursor].Status = status
+				job.Rows[cursor].EntryID = entryID
+				if rowErr != nil {
+					job.Rows[cursor].Error = describeError(rowErr)
+				}
+			}
+			job.Cursor = cursor + 1
+			return nil
+		})
+		if err != nil {
+			utils.Logger.Printf("Erro ao salvar o progresso da importação #%d: %v", id, err)
+		}
+	}
+
+	b.finishJob(id, jobs.StateDone, "", sheet)
+}
+
+// sameRows confere se a planilha relida tem as mesmas linhas registradas no job
+func sameRows(sheet *importer.Sheet, job *jobs.Job) bool {
+	if len(sheet.Rows) != len(job.Rows) || sheet.Checksum != job.Checksum {
+		return false
+	}
+	for i, row := range sheet.Rows {
+		if row.Line != job.Rows[i].Line {
+			return false
+		}
+	}
+	return true
+}
+
+// finishJob grava a situação final do job e envia o resultado ao chat, junto
+// com a planilha de resultado quando sheet não for nil
+func (b *Bot) finishJob(id int, state jobs.State, reason string, sheet *importer.Sheet) {
+	job, err := b.jobs.Update(id, func(job *jobs.Job) error {
+		job.State = state
+		job.Error = reason
+		return nil
+	})
+	if err != nil {
+		utils.Logger.Printf("Erro ao finalizar a importação #%d: %v", id, err)
+		return
+	}
+
+	utils.Logger.Printf("Importação #%d finalizada: %s", id, state)
+	b.api.Send(tgbotapi.NewMessage(job.ChatID, formatJobResult(job)))
+	if sheet != nil {
+		b.sendJobResults(job, sheet)
+	}
+}
+
+// formatJobResult monta a mensagem final de um job
+func formatJobResult(job *jobs.Job) string {
+	counts := job.Counts()
+
+	var resultText strings.Builder
+	switch job.State {
+	case jobs.StateCancelled:
+		resultText.WriteString(fmt.Sprintf("Importação #%d cancelada.\n\n", job.ID))
+	case jobs.StateFailed:
+		resultText.WriteString(fmt.Sprintf("Importação #%d falhou: %s\n\n", job.ID, job.Error))
+	default:
+		resultText.WriteString(fmt.Sprintf("Processamento da importação #%d concluído!\n\n", job.ID))
+	}
+	resultText.WriteString(fmt.Sprintf("Lançamentos criados com sucesso: %d\nJá lançados (ignorados): %d\nErros: %d\n",
+		counts[jobs.RowCreated], counts[jobs.RowAlreadyApplied], counts[jobs.RowFailed]))
+	if pending := counts[jobs.RowPending]; pending > 0 {
+		resultText.WriteString(fmt.Sprintf("Não processados: %d\n", pending))
+	}
+
+	// Adiciona detalhes dos erros, limitando a quantidade para não exceder o limite de mensagem do Telegram
+	if counts[jobs.RowFailed] > 0 {
+		resultText.WriteString("\nDetalhes dos erros:\n")
+		shown := 0
+		for _, row := range job.Rows {
+			if row.Status != jobs.RowFailed {
+				continue
+			}
+			if shown == maxErrorsToShow {
+				resultText.WriteString(fmt.Sprintf("... e mais %d erros. Veja todos na planilha de resultado.\n", counts[jobs.RowFailed]-maxErrorsToShow))
+				break
+			}
+			resultText.WriteString(fmt.Sprintf("- Linha %d: %s\n", row.Line, row.Error))
+			shown++
+		}
+	}
+	return resultText.String()
+}
+
+// formatJobStatus descreve a situação atual de um job
+func formatJobStatus(job *jobs.Job) string {
+	counts := job.Counts()
+	name := job.FileName
+	if name == "" {
+		name = "planilha"
+	}
+	return fmt.Sprintf("Importação #%d (%s)\nEnviada por: %s em %s\nSituação: %s\nProgresso: %d de %d linhas\nLançadas: %d\nJá lançadas: %d\nErros: %d\nInválidas: %d\nPendentes: %d",
+		job.ID, name, job.User, job.CreatedAt.Format("02/01/2006 15:04"), job.State, job.Cursor, len(job.Rows),
+		counts[jobs.RowCreated], counts[jobs.RowAlreadyApplied], counts[jobs.RowFailed], counts[jobs.RowInvalid], counts[jobs.RowPending])
+}
+
+// handleJobStatus trata o comando /status [job]
+func (b *Bot) handleJobStatus(message *tgbotapi.Message) {
+	utils.Logger.Printf("Comando /status recebido do chat ID: %d", message.Chat.ID)
+	args, ok := b.parseCommand(message, statusCommand)
+	if !ok {
+		return
+	}
+
+	if !args.Has("importacao") {
+		list := b.jobs.List(message.Chat.ID, jobStatusListSize)
+		if len(list) == 0 {
+			b.api.Send(tgbotapi.NewMessage(message.Chat.ID, "Nenhuma importação encontrada."))
+			return
+		}
+		var text strings.Builder
+		text.WriteString("Últimas importações:\n")
+		for _, job := range list {
+			text.WriteString(fmt.Sprintf("\n#%d %s - %s (%d/%d linhas)", job.ID, job.FileName, job.State, job.Cursor, len(job.Rows)))
+		}
+		text.WriteString("\n\nUse /status <número> para ver os detalhes.")
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, text.String()))
+		return
+	}
+
+	job, ok := b.chatJob(message.Chat.ID, args.Int("importacao"))
+	if !ok {
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Importação %d não encontrada.", args.Int("importacao"))))
+		return
+	}
+	b.api.Send(tgbotapi.NewMessage(message.Chat.ID, formatJobStatus(job)))
+}
+
+// handleCancelJob trata o comando /cancelar <job>. Sem o número, encerra a
+// conversa em andamento no chat.
+func (b *Bot) handleCancelJob(message *tgbotapi.Message) {
+	utils.Logger.Printf("Comando /cancelar recebido do chat ID: %d", message.Chat.ID)
+	args, ok := b.parseCommand(message, cancelCommand)
+	if !ok {
+		return
+	}
+	if !args.Has("importacao") {
+		b.handleCancelConversation(message)
+		return
+	}
+
+	job, ok := b.chatJob(message.Chat.ID, args.Int("importacao"))
+	if !ok {
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Importação %d não encontrada.", args.Int("importacao"))))
+		return
+	}
+
+	user := userLabel(message.From)
+	job, err := b.jobs.Update(job.ID, func(job *jobs.Job) error {
+		switch {
+		case job.State.Finished():
+			return fmt.Errorf("a importação #%d já foi finalizada (%s)", job.ID, job.State)
+		case job.State == jobs.StateAwaitingConfirmation:
+			job.State = jobs.StateCancelled
+		default:
+			job.CancelRequested = true
+		}
+		return nil
+	})
+	if err != nil {
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Não foi possível cancelar: %v", err)))
+		return
+	}
+
+	utils.Logger.Printf("Cancelamento da importação #%d pedido por %s no chat ID: %d", job.ID, user, message.Chat.ID)
+	if job.State == jobs.StateCancelled {
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Importação #%d cancelada. Nenhum lançamento foi feito.", job.ID)))
+		return
+	}
+	b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Cancelamento da importação #%d solicitado. As linhas já lançadas serão mantidas.", job.ID)))
+}
+
+// chatJob busca um job pelo número informado, desde que pertença ao chat
+func (b *Bot) chatJob(chatID int64, id int) (*jobs.Job, bool) {
+	job, err := b.jobs.Get(id)
+	if err != nil || job.ChatID != chatID {
+		return nil, false
+	}
+	return job, true
+}
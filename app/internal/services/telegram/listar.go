@@ -0,0 +1,213 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/export"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/services/pontomais"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+const (
+	listPageSize = 20              // Quantidade de colaboradores por página do /listar
+	listCacheTTL = 2 * time.Minute // Tempo em que os botões de uma mensagem reaproveitam a lista buscada
+)
+
+// listMessage identifica uma mensagem do /listar
+type listMessage struct {
+	chatID    int64
+	messageID int
+}
+
+// employeeList é a lista de colaboradores exibida em uma mensagem do /listar
+type employeeList struct {
+	employees []models.Employee
+	expires   time.Time
+}
+
+// handleListEmployees lista os colaboradores ativos, uma página por vez, ou
+// envia a lista completa como arquivo (/listar xlsx ou /listar csv)
+func (b *Bot) handleListEmployees(message *tgbotapi.Message) {
+	utils.Logger.Printf("Comando /listar recebido do chat ID: %d", message.Chat.ID)
+	args, ok := b.parseCommand(message, listCommand)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(args.String("formato"))
+	if format != "" && format != "xlsx" && format != "csv" {
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Formato inválido '%s': use xlsx ou csv.", args.String("formato"))))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, "Buscando colaboradores...")
+	b.api.Send(msg)
+
+	filter := pontomais.EmployeeFilter{}
+	if format != "" {
+		filter.Attributes = employeeDetailAttributes
+	}
+	employees, err := b.client.GetEmployees(b.ctx, filter)
+	if err != nil {
+		utils.Logger.Printf("Erro ao buscar colaboradores: %v", err)
+		errorMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Erro ao buscar colaboradores: %s", describeError(err)))
+		b.api.Send(errorMsg)
+		return
+	}
+
+	if len(employees) == 0 {
+		utils.Logger.Println("Nenhum colaborador encontrado")
+		noEmployeesMsg := tgbotapi.NewMessage(message.Chat.ID, "Nenhum colaborador encontrado.")
+		b.api.Send(noEmployeesMsg)
+		return
+	}
+	utils.Logger.Printf("Encontrados %d colaboradores", len(employees))
+
+	if format != "" {
+		b.sendEmployeesFile(message.Chat.ID, employees, format)
+		return
+	}
+
+	page := max(args.Int("pagina")-1, 0)
+	text, nav := employeesPage(employees, page)
+	resultMsg := tgbotapi.NewMessage(message.Chat.ID, text)
+	if len(nav) > 0 {
+		resultMsg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(nav)
+	}
+	sent, err := b.api.Send(resultMsg)
+	if err == nil && len(nav) > 0 {
+		b.storeEmployeeList(listMessage{message.Chat.ID, sent.MessageID}, employees)
+	}
+}
+
+// handleListCallback trata os botões "Anterior" e "Próxima" do /listar. A lista
+// buscada é reaproveitada por listCacheTTL; depois disso é buscada novamente,
+// para refletir admissões e desligamentos.
+func (b *Bot) handleListCallback(query *tgbotapi.CallbackQuery, args []string) {
+	chatID := query.Message.Chat.ID
+	if len(args) != 2 || args[0] != "pagina" {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+	page, err := strconv.Atoi(args[1])
+	if err != nil {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+
+	key := listMessage{chatID, query.Message.MessageID}
+	employees, ok := b.employeeList(key)
+	if !ok {
+		employees, err = b.client.GetEmployees(b.ctx, pontomais.EmployeeFilter{})
+		if err != nil {
+			utils.Logger.Printf("Erro ao buscar colaboradores: %v", err)
+			b.answerCallback(query, "Erro ao buscar colaboradores.")
+			return
+		}
+		b.storeEmployeeList(key, employees)
+	}
+	b.answerCallback(query, "")
+	if len(employees) == 0 {
+		b.editMessage(chatID, query.Message.MessageID, "Nenhum colaborador encontrado.", nil)
+		return
+	}
+	text, nav := employeesPage(employees, page)
+	var markup *tgbotapi.InlineKeyboardMarkup
+	if len(nav) > 0 {
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(nav)
+		markup = &keyboard
+	}
+	b.editMessage(chatID, query.Message.MessageID, text, markup)
+}
+
+// storeEmployeeList guarda a lista exibida em uma mensagem do /listar,
+// descartando as que já expiraram
+func (b *Bot) storeEmployeeList(key listMessage, employees []models.Employee) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	for k, list := range b.employeeLists {
+		if now.After(list.expires) {
+			delete(b.employeeLists, k)
+		}
+	}
+	b.employeeLists[key] = &employeeList{employees: employees, expires: now.Add(listCacheTTL)}
+}
+
+// employeeList devolve a lista exibida em uma mensagem do /listar, se ainda
+// não expirou
+func (b *Bot) employeeList(key listMessage) ([]models.Employee, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	list, ok := b.employeeLists[key]
+	if !ok || time.Now().After(list.expires) {
+		return nil, false
+	}
+	return list.employees, true
+}
+
+// employeesPage monta o texto e os botões de navegação de uma página do /listar,
+// com a página a partir de 0
+func employeesPage(employees []models.Employee, page int) (string, []tgbotapi.InlineKeyboardButton) {
+	pages := (len(employees) + listPageSize - 1) / listPageSize
+	page = max(min(page, pages-1), 0)
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Total de funcionários: %d", len(employees)))
+	if pages > 1 {
+		text.WriteString(fmt.Sprintf(" (página %d de %d)", page+1, pages))
+	}
+	text.WriteString("\n")
+	for _, employee := range employees[page*listPageSize : min((page+1)*listPageSize, len(employees))] {
+		text.WriteString(fmt.Sprintf("\n%d - %s", employee.ID, employeeName(employee)))
+	}
+	text.WriteString("\n\nUse /buscar para ver os detalhes de um colaborador, ou /listar xlsx e /listar csv para exportar a lista completa.")
+
+	return text.String(), pageButtons("listar", page, pages)
+}
+
+// employeesTable monta a tabela exportada pelo /listar. As colunas de
+// identificação usam os nomes aceitos pelo /relatorio, então o arquivo serve
+// de base para uma planilha de lançamentos.
+func employeesTable(employees []models.Employee) *export.Table {
+	table := &export.Table{
+		Sheet:   "Colaboradores",
+		Headers: []string{"ID", "NOME", "PRIMEIRO_NOME", "SOBRENOME", "EMAIL", "CPF", "MATRICULA", "CENTRO_DE_CUSTO", "JORNADA"},
+	}
+	for _, employee := range employees {
+		table.Rows = append(table.Rows, []interface{}{
+			employee.ID,
+			employeeName(employee),
+			employee.FirstName,
+			employee.LastName,
+			employee.Email,
+			employee.CPF,
+			employee.RegistrationNumber,
+			employee.CostCenter,
+			employee.WorkHours,
+		})
+	}
+	return table
+}
+
+// sendEmployeesFile envia a lista de colaboradores como .xlsx ou .csv
+func (b *Bot) sendEmployeesFile(chatID int64, employees []models.Employee, format string) {
+	data, err := employeesTable(employees).Encode(format)
+	if err != nil {
+		utils.Logger.Printf("Erro ao gerar a lista de colaboradores em %s: %v", format, err)
+		b.api.Send(tgbotapi.NewMessage(chatID, "Não foi possível gerar o arquivo de colaboradores."))
+		return
+	}
+
+	name := fmt.Sprintf("colaboradores_%s.%s", time.Now().Format("2006-01-02"), format)
+	doc := tgbotapi.NewDocumentUpload(chatID, tgbotapi.FileBytes{Name: name, Bytes: data})
+	doc.Caption = fmt.Sprintf("%d colaboradores ativos. Para lançamentos em lote, acrescente as colunas DATA, HORAS, OBSERVAÇÃO e DEBITO e envie com /relatorio.", len(employees))
+	if _, err := b.api.Send(doc); err != nil {
+		utils.Logger.Printf("Erro ao enviar a lista de colaboradores: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// testEmployees cria n colaboradores com IDs a partir de 1
+func testEmployees(n int) []models.Employee {
+	employees := make([]models.Employee, n)
+	for i := range employees {
+		employees[i] = models.Employee{ID: i + 1, FirstName: "Colaborador", LastName: fmt.Sprint(i + 1)}
+	}
+	return employees
+}
+
+func TestEmployeesPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		page      int
+		wantTitle string
+		wantFirst int // ID do primeiro colaborador da página
+		wantLast  int // ID do último colaborador da página
+		wantNav   []string
+	}{
+		{"página única", 5, 0, "Total de funcionários: 5\n", 1, 5, nil},
+		{"primeira de três", 45, 0, "Total de funcionários: 45 (página 1 de 3)", 1, 20, []string{"listar:pagina:1"}},
+		{"página do meio", 45, 1, "Total de funcionários: 45 (página 2 de 3)", 21, 40, []string{"listar:pagina:0", "listar:pagina:2"}},
+		{"última incompleta", 45, 2, "Total de funcionários: 45 (página 3 de 3)", 41, 45, []string{"listar:pagina:1"}},
+		{"página além do fim vira a última", 45, 9, "(página 3 de 3)", 41, 45, []string{"listar:pagina:1"}},
+		{"página negativa vira a primeira", 40, -3, "(página 1 de 2)", 1, 20, []string{"listar:pagina:1"}},
+		{"múltiplo exato do tamanho", 40, 1, "(página 2 de 2)", 21, 40, []string{"listar:pagina:0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, nav := employeesPage(testEmployees(tt.total), tt.page)
+			if !strings.Contains(text, tt.wantTitle) {
+				t.Errorf("texto sem %q:\n%s", tt.wantTitle, text)
+			}
+			lines := strings.Count(text, "\n") - 3
+			if want := tt.wantLast - tt.wantFirst + 1; lines != want {
+				t.Errorf("%d colaboradores na página, esperado %d", lines, want)
+			}
+			for _, id := range []int{tt.wantFirst, tt.wantLast} {
+				if !strings.Contains(text, fmt.Sprintf("\n%d - Colaborador %d", id, id)) {
+					t.Errorf("colaborador %d ausente:\n%s", id, text)
+				}
+			}
+
+			var data []string
+			for _, button := range nav {
+				data = append(data, *button.CallbackData)
+			}
+			if !reflect.DeepEqual(data, tt.wantNav) {
+				t.Errorf("botões = %v, esperado %v", data, tt.wantNav)
+			}
+		})
+	}
+}
+
+func TestEmployeesTable(t *testing.T) {
+	table := employeesTable([]models.Employee{
+		{ID: 101, FirstName: "Ana", LastName: "Souza", Email: "ana@empresa.com", CPF: "12345678900", RegistrationNumber: "A-17", CostCenter: "TI", WorkHours: "08:00"},
+		{ID: 102, FirstName: "Bia"},
+	})
+
+	if table.Sheet != "Colaboradores" {
+		t.Errorf("aba = %q", table.Sheet)
+	}
+	wantHeaders := []string{"ID", "NOME", "PRIMEIRO_NOME", "SOBRENOME", "EMAIL", "CPF", "MATRICULA", "CENTRO_DE_CUSTO", "JORNADA"}
+	if !reflect.DeepEqual(table.Headers, wantHeaders) {
+		t.Errorf("cabeçalhos = %v, esperado %v", table.Headers, wantHeaders)
+	}
+	wantRows := [][]interface{}{
+		{101, "Ana Souza", "Ana", "Souza", "ana@empresa.com", "12345678900", "A-17", "TI", "08:00"},
+		{102, "Bia", "Bia", "", "", "", "", "", ""},
+	}
+	if !reflect.DeepEqual(table.Rows, wantRows) {
+		t.Errorf("linhas = %v, esperado %v", table.Rows, wantRows)
+	}
+
+	// O CSV exportado usa cabeçalhos que o /relatorio reconhece
+	data, err := table.Encode("csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "101;Ana Souza;Ana;Souza;ana@empresa.com;12345678900;A-17;TI;08:00\n") {
+		t.Errorf("CSV = %q", data)
+	}
+}
+
+func TestListCallbackReusesList(t *testing.T) {
+	var calls atomic.Int32
+	b, fake := newTestBot(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		json.NewEncoder(w).Encode(models.EmployeesResponse{Employees: testEmployees(45), Meta: &models.PaginationMeta{TotalPages: 1}})
+	}))
+
+	b.handleListCallback(callbackQuery("listar:pagina:1"), []string{"pagina", "1"})
+	b.handleListCallback(callbackQuery("listar:pagina:2"), []string{"pagina", "2"})
+	if got := calls.Load(); got != 1 {
+		t.Errorf("%d buscas de colaboradores, esperado 1", got)
+	}
+	if texts := fake.texts(); len(texts) != 2 || !strings.Contains(texts[1], "(página 3 de 3)") {
+		t.Errorf("mensagens = %q", texts)
+	}
+
+	// Vencido o prazo, a lista é buscada novamente
+	b.mu.Lock()
+	for _, list := range b.employeeLists {
+		list.expires = list.expires.Add(-listCacheTTL)
+	}
+	b.mu.Unlock()
+	b.handleListCallback(callbackQuery("listar:pagina:0"), []string{"pagina", "0"})
+	if got := calls.Load(); got != 2 {
+		t.Errorf("%d buscas após o prazo, esperado 2", got)
+	}
+
+	// Outra mensagem não usa a lista guardada para esta
+	query := callbackQuery("listar:pagina:1")
+	query.Message.MessageID = 11
+	b.handleListCallback(query, []string{"pagina", "1"})
+	if got := calls.Load(); got != 3 {
+		t.Errorf("%d buscas para outra mensagem, esperado 3", got)
+	}
+}
@@ -0,0 +1,313 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+	"github.com/jeffemart/PontoGo/app/internal/importer"
+	"github.com/jeffemart/PontoGo/app/internal/jobs"
+	"github.com/jeffemart/PontoGo/app/internal/journal"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/services/pontomais"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// Limites de itens listados nas mensagens, para não exceder o tamanho máximo do Telegram
+const (
+	maxErrorsToShow    = 10
+	maxEmployeesToShow = 20
+)
+
+// processRelatorioFile lê e valida a planilha inteira e envia um resumo com os
+// botões "Confirmar" e "Cancelar". Nada é lançado antes da confirmação.
+func (b *Bot) processRelatorioFile(message *tgbotapi.Message, filePath, sheetName string) {
+	sheet, warning, err := b.readImport(filePath, sheetName)
+	if err != nil {
+		utils.Logger.Printf("Erro ao ler a planilha: %v", err)
+		errorMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Erro ao ler a planilha: %v", err))
+		b.api.Send(errorMsg)
+		return
+	}
+
+	summary := sheet.Summarize()
+	text := warning + formatImportSummary(summary)
+
+	if summary.ValidRows == 0 {
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, text+"\nNenhuma linha nova para lançar. Corrija a planilha e envie novamente com /relatorio."))
+		return
+	}
+
+	// Persiste a planilha como um job aguardando a confirmação do operador
+	job := b.newImportJob(sheet, message.Chat.ID, userLabel(message.From), message.Document.FileName, sheetName, jobs.StateAwaitingConfirmation)
+	job, err = b.jobs.Create(job, filePath)
+	if err != nil {
+		utils.Logger.Printf("Erro ao criar o job de importação: %v", err)
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, "Erro ao salvar a planilha para processamento."))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, text+fmt.Sprintf("\nImportação #%d. Confirma o lançamento das %d linhas válidas?", job.ID, summary.ValidRows))
+	msg.ReplyMarkup = confirmKeyboard("relatorio", strconv.Itoa(job.ID))
+	b.api.Send(msg)
+}
+
+// readImport lê a planilha, localiza os colaboradores e marca as linhas já
+// lançadas. Se os colaboradores não puderem ser consultados, devolve um aviso
+// para o resumo em vez de um erro.
+func (b *Bot) readImport(filePath, sheetName string) (*importer.Sheet, string, error) {
+	sheet, err := importer.ParseFile(filePath, importer.Options{Sheet: sheetName, Mapping: b.mapping})
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Imprime as linhas no log para debug
+	utils.Logger.Println("Dados da planilha:")
+	for _, row := range sheet.Rows {
+		utils.Logger.Printf("Linha %d: %v", row.Line, row.Values)
+	}
+
+	// Localiza os colaboradores da planilha (por ID, CPF, e-mail ou matrícula)
+	// entre os colaboradores ativos
+	var warning string
+	employees, err := b.client.GetEmployees(b.ctx, pontomais.EmployeeFilter{})
+	if err != nil {
+		utils.Logger.Printf("Erro ao buscar colaboradores para validar a planilha: %v", err)
+		warning = fmt.Sprintf("Atenção: não foi possível conferir os colaboradores (%s). Linhas com ID serão lançadas sem conferência.\n", describeError(err))
+		sheet.ResolveEmployees(nil)
+	} else {
+		sheet.ResolveEmployees(importer.NewEmployeeIndex(employees))
+	}
+
+	// Marca as linhas que já foram lançadas em um envio anterior do mesmo arquivo
+	sheet.MarkApplied(b.ledger)
+	return sheet, warning, nil
+}
+
+// newImportJob monta o job de importação da planilha lida, com as linhas
+// inválidas já marcadas e os apelidos de colunas usados na leitura
+func (b *Bot) newImportJob(sheet *importer.Sheet, chatID int64, user, fileName, sheetName string, state jobs.State) *jobs.Job {
+	job := &jobs.Job{
+		ChatID:   chatID,
+		User:     user,
+		FileName: fileName,
+		Sheet:    sheetName,
+		Mapping:  b.mapping,
+		Checksum: sheet.Checksum,
+		State:    state,
+		Rows:     make([]jobs.Row, len(sheet.Rows)),
+	}
+	for i, row := range sheet.Rows {
+		job.Rows[i] = jobs.Row{Line: row.Line, EmployeeID: row.EmployeeID, Status: jobs.RowPending}
+		if !row.Valid() {
+			job.Rows[i].Status = jobs.RowInvalid
+			job.Rows[i].Error = row.Err.Error()
+		}
+	}
+	return job
+}
+
+// formatImportSummary monta o texto do resumo de uma planilha
+func formatImportSummary(summary importer.Summary) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Resumo da planilha:\n\nLinhas: %d\nVálidas: %d\nJá lançadas (serão ignoradas): %d\nInválidas: %d\n",
+		summary.TotalRows, summary.ValidRows, summary.AlreadyApplied, len(summary.Invalid)))
+
+	if len(summary.Employees) > 0 {
+		text.WriteString("\nTotais por colaborador (crédito / débito):\n")
+		for i, t := range summary.Employees {
+			if i == maxEmployeesToShow {
+				text.WriteString(fmt.Sprintf("... e mais %d colaboradores.\n", len(summary.Employees)-maxEmployeesToShow))
+				break
+			}
+			text.WriteString(fmt.Sprintf("- %s (ID %s): %s / %s\n",
+				t.Name, t.EmployeeID, duration.Format(t.Credited), duration.Format(t.Debited)))
+		}
+	}
+
+	if len(summary.Repeated) > 0 {
+		text.WriteString("\nLinhas repetidas (mesmos dados de uma linha anterior; também serão lançadas):\n")
+		for i, row := range summary.Repeated {
+			if i == maxErrorsToShow {
+				text.WriteString(fmt.Sprintf("... e mais %d linhas repetidas.\n", len(summary.Repeated)-maxErrorsToShow))
+				break
+			}
+			text.WriteString(fmt.Sprintf("- %s: igual à linha %d\n", row.Label(), row.RepeatOf))
+		}
+	}
+
+	if len(summary.Invalid) > 0 {
+		text.WriteString("\nLinhas inválidas (não serão lançadas):\n")
+		for i, row := range summary.Invalid {
+			if i == maxErrorsToShow {
+				text.WriteString(fmt.Sprintf("... e mais %d linhas inválidas.\n", len(summary.Invalid)-maxErrorsToShow))
+				break
+			}
+			text.WriteString(fmt.Sprintf("- %s: %v\n", row.Label(), row.Err))
+		}
+	}
+	return text.String()
+}
+
+// handleRelatorioCallback trata os botões de confirmação do /relatorio
+func (b *Bot) handleRelatorioCallback(query *tgbotapi.CallbackQuery, args []string) {
+	chatID := query.Message.Chat.ID
+	messageID := query.Message.MessageID
+	if len(args) != 2 {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+	decision := args[0]
+	id, err := strconv.Atoi(args[1])
+	if err != nil {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+
+	user := userLabel(query.From)
+	job, err := b.jobs.Update(id, func(job *jobs.Job) error {
+		if job.ChatID != chatID {
+			return jobs.ErrNotFound
+		}
+		if job.State != jobs.StateAwaitingConfirmation {
+			return fmt.Errorf("a importação #%d não está aguardando confirmação (%s)", job.ID, job.State)
+		}
+		if decision == "confirmar" {
+			job.State = jobs.StateRunning
+			job.User = user
+		} else {
+			job.State = jobs.StateCancelled
+		}
+		return nil
+	})
+	if errors.Is(err, jobs.ErrNotFound) {
+		b.answerCallback(query, "A confirmação expirou.")
+		b.editMessage(chatID, messageID, "A confirmação desta planilha expirou. Envie a planilha novamente com /relatorio.", nil)
+		return
+	}
+	if err != nil {
+		b.answerCallback(query, "Esta planilha não está mais disponível.")
+		b.editMessage(chatID, messageID, fmt.Sprintf("Esta planilha não está mais disponível: %v", err), nil)
+		return
+	}
+
+	if job.State == jobs.StateCancelled {
+		utils.Logger.Printf("Importação #%d cancelada por %s no chat ID: %d", job.ID, user, chatID)
+		b.answerCallback(query, "Importação cancelada.")
+		b.editMessage(chatID, messageID, fmt.Sprintf("Importação #%d cancelada. Nenhum lançamento foi feito.", job.ID), nil)
+		return
+	}
+
+	utils.Logger.Printf("Importação #%d confirmada por %s no chat ID: %d", job.ID, user, chatID)
+	b.answerCallback(query, "Importação confirmada.")
+	b.editMessage(chatID, messageID, query.Message.Text+fmt.Sprintf("\n\nImportação confirmada. Acompanhe com /status %d ou interrompa com /cancelar %d.", job.ID, job.ID), nil)
+	b.startJob(job.ID)
+}
+
+// applyRow lança uma linha da planilha de forma idempotente. A chave da linha é
+// registrada no ledger antes do envio e confirmada depois; se um envio anterior
+// foi interrompido sem confirmação, o Ponto Mais é consultado antes de reenviar.
+// A chave fica reservada durante o envio, para que outra importação do mesmo
+// arquivo em andamento não lance a linha ao mesmo tempo. O registro só é
+// descartado quando a API recusa a linha; se não houver certeza de que o envio
+// falhou, ele fica pendente e a próxima tentativa confere o Ponto Mais.
+func (b *Bot) applyRow(ctx context.Context, chatID int64, user, checksum string, row importer.Row) (jobs.RowStatus, int, error) {
+	record, ok, err := b.ledger.Claim(row.Key)
+	if err != nil {
+		return jobs.RowFailed, 0, err
+	}
+	defer b.ledger.Release(row.Key)
+
+	if ok {
+		switch record.State {
+		case importer.LedgerApplied:
+			return jobs.RowAlreadyApplied, record.EntryID, nil
+		case importer.LedgerStarted:
+			// Sobrou de uma execução anterior do bot: confere se o envio chegou à API
+			existing, err := b.findImportedEntry(ctx, row)
+			if err != nil {
+				return jobs.RowFailed, 0, fmt.Errorf("não foi possível conferir se a linha já foi lançada: %w", err)
+			}
+			if existing != nil {
+				if err := b.ledger.Commit(row, checksum, existing.ID); err != nil {
+					utils.Logger.Printf("Erro ao gravar o ledger de importações: %v", err)
+				}
+				return jobs.RowAlreadyApplied, existing.ID, nil
+			}
+		}
+	}
+
+	// Sem o registro no ledger não há como evitar duplicidade, então não envia
+	if err := b.ledger.Start(row, checksum); err != nil {
+		return jobs.RowFailed, 0, err
+	}
+
+	entry := row.Entry()
+	utils.Logger.Printf("Criando lançamento para o funcionário ID: %s (%s), Quantidade: %s, Data: %s",
+		row.EmployeeID, row.EmployeeName, duration.Describe(row.Seconds), entry.Date)
+	created, err := b.client.CreateTimeBalanceEntry(ctx, entry)
+	if err == nil && created.ID == 0 {
+		err = errMissingEntryID
+	}
+	if err != nil {
+		if rejectedByAPI(err) {
+			if discardErr := b.ledger.Discard(row, checksum); discardErr != nil {
+				utils.Logger.Printf("Erro ao gravar o ledger de importações: %v", discardErr)
+			}
+		} else {
+			// O lançamento pode ter sido criado: a chave continua pendente para
+			// que a próxima tentativa o procure no Ponto Mais em vez de lançá-lo de novo
+			b.ledger.Abandon(row.Key)
+		}
+		return jobs.RowFailed, 0, err
+	}
+
+	if err := b.ledger.Commit(row, checksum, created.ID); err != nil {
+		utils.Logger.Printf("Erro ao gravar o ledger de importações: %v", err)
+	}
+	b.recordOperation(journal.Operation{
+		Kind:    journal.OperationCreate,
+		ChatID:  chatID,
+		User:    user,
+		EntryID: created.ID,
+		Current: &entry,
+	})
+	utils.Logger.Printf("Lançamento %d criado com sucesso para o funcionário ID: %s (%s)", created.ID, row.EmployeeID, row.EmployeeName)
+	return jobs.RowCreated, created.ID, nil
+}
+
+// findImportedEntry procura no Ponto Mais um lançamento com os mesmos dados da
+// linha, para decidir se um envio interrompido chegou a ser processado
+func (b *Bot) findImportedEntry(ctx context.Context, row importer.Row) (*models.TimeBalanceEntryRecord, error) {
+	employeeID, err := strconv.Atoi(row.EmployeeID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de funcionário inválido: %s", row.EmployeeID)
+	}
+
+	entries, err := b.client.ListTimeBalanceEntries(ctx, pontomais.TimeBalanceEntryFilter{
+		EmployeeID: employeeID,
+		StartDate:  row.Date,
+		EndDate:    row.Date,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Lançamentos já associados a outra linha no ledger são ignorados, pois uma
+	// linha repetida no arquivo tem os mesmos dados da anterior
+	for i := range entries {
+		entry := &entries[i]
+		if b.ledger.HasEntry(entry.ID) {
+			continue
+		}
+		if math.Abs(entry.Amount-row.Seconds) < 0.5 && entry.Observation == row.Observation && entry.Withdraw == row.Withdraw {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
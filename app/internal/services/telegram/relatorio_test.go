@@ -0,0 +1,120 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/importer"
+	"github.com/jeffemart/PontoGo/app/internal/jobs"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// importServer simula a criação e a listagem de lançamentos do Ponto Mais. A
+// primeira criação pode falhar com o status informado ou ficar sem resposta
+// depois de gravar o lançamento.
+type importServer struct {
+	mu      sync.Mutex
+	status  int  // Status da primeira criação; zero responde normalmente
+	hang    bool // A primeira criação grava o lançamento e não responde
+	creates int
+	entries []models.TimeBalanceEntryRecord
+}
+
+func (s *importServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/time_balance_entries" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method == http.MethodGet {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		json.NewEncoder(w).Encode(models.TimeBalanceEntriesResponse{TimeBalanceEntries: s.entries})
+		return
+	}
+
+	var body struct {
+		Entry models.TimeBalanceEntry `json:"time_balance_entry"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	s.mu.Lock()
+	s.creates++
+	first := s.creates == 1
+	if first && s.status != 0 {
+		s.mu.Unlock()
+		w.WriteHeader(s.status)
+		w.Write([]byte(`{"error":"falha simulada"}`))
+		return
+	}
+	record := models.TimeBalanceEntryRecord{ID: 76 + s.creates, EmployeeID: 12, Amount: body.Entry.Amount, Date: "2025-03-18",
+		Observation: body.Entry.Observation, Withdraw: body.Entry.Withdraw}
+	s.entries = append(s.entries, record)
+	s.mu.Unlock()
+
+	if first && s.hang {
+		// O lançamento foi criado, mas a resposta nunca chega ao bot
+		<-r.Context().Done()
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.TimeBalanceEntryResponse{TimeBalanceEntry: record})
+}
+
+func TestApplyRowFailures(t *testing.T) {
+	row := importer.Row{Line: 2, Key: "chave", EmployeeID: "12", EmployeeName: "ANA",
+		Date: time.Date(2025, 3, 18, 0, 0, 0, 0, time.UTC), Seconds: 3600, Observation: "Plantão"}
+
+	tests := []struct {
+		name       string
+		server     *importServer
+		wantState  string // Estado da chave no ledger após a falha; vazio se descartada
+		wantRetry  jobs.RowStatus
+		wantEntry  int
+		wantCreate int // Criações recebidas pelo servidor ao final
+	}{
+		{"tempo esgotado após criar", &importServer{hang: true}, importer.LedgerStarted, jobs.RowAlreadyApplied, 77, 1},
+		{"erro 503", &importServer{status: http.StatusServiceUnavailable}, importer.LedgerStarted, jobs.RowCreated, 78, 2},
+		{"limite de requisições", &importServer{status: http.StatusTooManyRequests}, importer.LedgerStarted, jobs.RowCreated, 78, 2},
+		{"recusa por validação", &importServer{status: http.StatusUnprocessableEntity}, "", jobs.RowCreated, 78, 2},
+		{"não encontrado", &importServer{status: http.StatusNotFound}, "", jobs.RowCreated, 78, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := newTestBot(t, tt.server)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			status, _, err := b.applyRow(ctx, testChatID, "Ana", "sum", row)
+			cancel()
+			if status != jobs.RowFailed || err == nil {
+				t.Fatalf("primeira tentativa = %s, %v; esperado erro", status, err)
+			}
+
+			record, found := b.ledger.Lookup(row.Key)
+			if tt.wantState == "" && found {
+				t.Fatalf("chave no ledger = %+v, esperado descartada", record)
+			}
+			if tt.wantState != "" && (!found || record.State != tt.wantState) {
+				t.Fatalf("chave no ledger = %+v (%t), esperado %s", record, found, tt.wantState)
+			}
+
+			// A nova tentativa confere o Ponto Mais antes de reenviar a linha
+			status, entryID, err := b.applyRow(context.Background(), testChatID, "Ana", "sum", row)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != tt.wantRetry || entryID != tt.wantEntry {
+				t.Errorf("nova tentativa = %s, lançamento %d; esperado %s, lançamento %d", status, entryID, tt.wantRetry, tt.wantEntry)
+			}
+			if tt.server.creates != tt.wantCreate {
+				t.Errorf("%d criações no Ponto Mais, esperado %d", tt.server.creates, tt.wantCreate)
+			}
+			if !b.ledger.Applied(row.Key) {
+				t.Error("a linha não ficou registrada como lançada no ledger")
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package telegram
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/export"
+	"github.com/jeffemart/PontoGo/app/internal/importer"
+	"github.com/jeffemart/PontoGo/app/internal/jobs"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// Colunas acrescentadas à planilha de resultado de uma importação
+const (
+	resultColumnStatus  = "STATUS"
+	resultColumnError   = "ERRO"
+	resultColumnEntryID = "ID_LANCAMENTO"
+)
+
+// rowStatusLabels são os textos da coluna STATUS para cada situação de linha
+var rowStatusLabels = map[jobs.RowStatus]string{
+	jobs.RowPending:        "NÃO PROCESSADA",
+	jobs.RowCreated:        "LANÇADA",
+	jobs.RowAlreadyApplied: "JÁ LANÇADA",
+	jobs.RowFailed:         "ERRO",
+	jobs.RowInvalid:        "INVÁLIDA",
+}
+
+// resultsTable monta a planilha de resultado: as colunas originais de cada
+// linha seguidas da situação, do erro e do ID do lançamento criado
+func resultsTable(sheet *importer.Sheet, job *jobs.Job) *export.Table {
+	table := &export.Table{
+		Sheet:   "Resultado",
+		Headers: append(append([]string(nil), sheet.Headers...), resultColumnStatus, resultColumnError, resultColumnEntryID),
+	}
+
+	for i, row := range sheet.Rows {
+		cells := make([]interface{}, len(sheet.Headers), len(sheet.Headers)+3)
+		for j := range cells {
+			cells[j] = ""
+			if j < len(row.Values) {
+				cells[j] = row.Values[j]
+			}
+		}
+
+		result := job.Rows[i]
+		var entryID interface{} = ""
+		if result.EntryID != 0 {
+			entryID = result.EntryID
+		}
+		table.Rows = append(table.Rows, append(cells, rowStatusLabels[result.Status], result.Error, entryID))
+	}
+	return table
+}
+
+// sendJobResults envia ao chat a planilha de resultado do job
+func (b *Bot) sendJobResults(job *jobs.Job, sheet *importer.Sheet) {
+	data, err := resultsTable(sheet, job).XLSX()
+	if err != nil {
+		utils.Logger.Printf("Erro ao gerar a planilha de resultado da importação #%d: %v", job.ID, err)
+		b.api.Send(tgbotapi.NewMessage(job.ChatID, "Não foi possível gerar a planilha de resultado."))
+		return
+	}
+
+	doc := tgbotapi.NewDocumentUpload(job.ChatID, tgbotapi.FileBytes{Name: resultsFileName(job), Bytes: data})
+	doc.Caption = fmt.Sprintf("Resultado da importação #%d. Filtre a coluna STATUS para corrigir e reenviar apenas as linhas com erro.", job.ID)
+	if _, err := b.api.Send(doc); err != nil {
+		utils.Logger.Printf("Erro ao enviar a planilha de resultado da importação #%d: %v", job.ID, err)
+	}
+}
+
+// resultsFileName monta o nome da planilha de resultado a partir do arquivo enviado
+func resultsFileName(job *jobs.Job) string {
+	name := strings.TrimSuffix(job.FileName, filepath.Ext(job.FileName))
+	if name == "" {
+		name = "importacao"
+	}
+	return fmt.Sprintf("%s_resultado_%d.xlsx", name, job.ID)
+}
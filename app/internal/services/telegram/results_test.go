@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/jeffemart/PontoGo/app/internal/importer"
+	"github.com/jeffemart/PontoGo/app/internal/jobs"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestResultsTableXLSX(t *testing.T) {
+	sheet, err := importer.ParseRows([][]string{
+		{"ID", "NOME", "DATA", "HORAS", "OBSERVAÇÃO", "DEBITO"},
+		{"1487972", "ANA", "18/03/2025", "7200", "Horas extras", "false"},
+		{"1487973", "BIA", "31/02/2025", "3600", "Ajuste", "false"},
+		{"1487974", "CAIO", "19/03/2025", "1800", "Plantão", "true"},
+		{"1487975", "DANI", "20/03/2025", "3600", "Folga"},
+		{"1487976", "EDU", "21/03/2025", "600", "Ajuste", "false"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := &jobs.Job{ID: 3, Rows: []jobs.Row{
+		{Line: 2, Status: jobs.RowCreated, EntryID: 901},
+		{Line: 3, Status: jobs.RowInvalid, Error: "data inválida"},
+		{Line: 4, Status: jobs.RowAlreadyApplied, EntryID: 877},
+		{Line: 5, Status: jobs.RowFailed, Error: "dados rejeitados pelo Ponto Mais"},
+		{Line: 6, Status: jobs.RowPending},
+	}}
+
+	data, err := resultsTable(sheet, job).XLSX()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("Resultado")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"ID", "NOME", "DATA", "HORAS", "OBSERVAÇÃO", "DEBITO", "STATUS", "ERRO", "ID_LANCAMENTO"},
+		{"1487972", "ANA", "18/03/2025", "7200", "Horas extras", "false", "LANÇADA", "", "901"},
+		{"1487973", "BIA", "31/02/2025", "3600", "Ajuste", "false", "INVÁLIDA", "data inválida", ""},
+		{"1487974", "CAIO", "19/03/2025", "1800", "Plantão", "true", "JÁ LANÇADA", "", "877"},
+		{"1487975", "DANI", "20/03/2025", "3600", "Folga", "", "ERRO", "dados rejeitados pelo Ponto Mais", ""},
+		{"1487976", "EDU", "21/03/2025", "600", "Ajuste", "false", "NÃO PROCESSADA", "", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("%d linhas na planilha, esperado %d: %v", len(rows), len(want), rows)
+	}
+	for i := range want {
+		// O excelize omite as células vazias no fim da linha
+		got := rows[i]
+		for len(got) < len(want[i]) {
+			got = append(got, "")
+		}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("linha %d = %q, esperado %q", i+1, got, want[i])
+		}
+	}
+}
+
+func TestResultsFileName(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"horas_marco.xlsx", "horas_marco_resultado_7.xlsx"},
+		{"folha.2025.csv", "folha.2025_resultado_7.xlsx"},
+		{"", "importacao_resultado_7.xlsx"},
+	}
+	for _, tt := range tests {
+		if got := resultsFileName(&jobs.Job{ID: 7, FileName: tt.file}); got != tt.want {
+			t.Errorf("resultsFileName(%q) = %q, esperado %q", tt.file, got, tt.want)
+		}
+	}
+}
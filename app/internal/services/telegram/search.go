@@ -0,0 +1,178 @@
+package telegram
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jeffemart/PontoGo/app/internal/importer"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// Pontuações da busca de colaboradores. Uma correspondência exata de
+// identificador vale mais do que qualquer correspondência de nome.
+const (
+	scoreExactID     = 1000
+	scoreEmail       = 500
+	scorePartialID   = 300
+	scoreWordExact   = 30
+	scoreWordPrefix  = 20
+	scoreWordContain = 10
+	scoreWordTypo    = 5
+)
+
+// searchEmployees busca os colaboradores pelo texto, ordenados do mais para o
+// menos relevante. A busca compara:
+//   - números com o ID, o CPF (com ou sem pontuação) e a matrícula;
+//   - o texto com o e-mail;
+//   - cada palavra com as palavras do nome, sem diferenciar acentos e caixa,
+//     aceitando prefixos ("jo" para "João") e erros de digitação (um erro a
+//     cada 4 letras, até dois).
+//
+// No nome, todas as palavras buscadas precisam ser encontradas.
+func searchEmployees(employees []models.Employee, query string) []models.Employee {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	type match struct {
+		employee models.Employee
+		score    int
+	}
+	var matches []match
+	for _, employee := range employees {
+		if score := employeeScore(employee, query); score > 0 {
+			matches = append(matches, match{employee, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return employeeName(matches[i].employee) < employeeName(matches[j].employee)
+	})
+
+	result := make([]models.Employee, len(matches))
+	for i, m := range matches {
+		result[i] = m.employee
+	}
+	return result
+}
+
+// employeeScore calcula a relevância do colaborador para a busca; zero indica
+// que ele não corresponde
+func employeeScore(employee models.Employee, query string) int {
+	best := 0
+	keep := func(score int) {
+		if score > best {
+			best = score
+		}
+	}
+
+	// Identificadores: ID, CPF e matrícula
+	plain := importer.NormalizeHeader(query)
+	if plain != "" {
+		cpf := importer.NormalizeHeader(employee.CPF)
+		registration := importer.NormalizeHeader(employee.RegistrationNumber)
+		switch {
+		case plain == strconv.Itoa(employee.ID), plain == cpf, plain == registration:
+			keep(scoreExactID)
+		case len(plain) >= 3 && (strings.HasPrefix(cpf, plain) || strings.Contains(registration, plain)):
+			keep(scorePartialID)
+		}
+	}
+
+	// E-mail
+	if email := strings.ToLower(employee.Email); email != "" && !strings.Contains(query, " ") {
+		if strings.Contains(email, strings.ToLower(query)) {
+			keep(scoreEmail)
+		}
+	}
+
+	// Nome: todas as palavras buscadas precisam corresponder a alguma palavra do nome
+	var nameWords []string
+	for _, word := range strings.Fields(employeeName(employee)) {
+		nameWords = append(nameWords, importer.NormalizeHeader(word))
+	}
+	nameScore := 0
+	for _, word := range strings.Fields(query) {
+		word = importer.NormalizeHeader(word)
+		if word == "" {
+			continue
+		}
+		bestWord := 0
+		for _, nameWord := range nameWords {
+			bestWord = max(bestWord, wordScore(word, nameWord))
+		}
+		if bestWord == 0 {
+			nameScore = 0
+			break
+		}
+		nameScore += bestWord
+	}
+	keep(nameScore)
+	return best
+}
+
+// wordScore compara uma palavra buscada com uma palavra do nome, já normalizadas
+func wordScore(word, nameWord string) int {
+	switch {
+	case word == nameWord:
+		return scoreWordExact
+	case strings.HasPrefix(nameWord, word):
+		return scoreWordPrefix
+	case len(word) >= 3 && strings.Contains(nameWord, word):
+		return scoreWordContain
+	}
+
+	typos := len([]rune(word)) / 4
+	if typos > 2 {
+		typos = 2
+	}
+	if typos == 0 {
+		return 0
+	}
+	// Compara também com o início da palavra do nome, para prefixos com erro ("joa" em "joana")
+	candidate := []rune(nameWord)
+	if prefix := len([]rune(word)); len(candidate) > prefix+typos {
+		candidate = candidate[:prefix]
+	}
+	if levenshtein([]rune(word), candidate) <= typos {
+		return scoreWordTypo
+	}
+	return 0
+}
+
+// levenshtein calcula a distância de edição entre duas palavras
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// employeeName devolve o nome completo do colaborador
+func employeeName(employee models.Employee) string {
+	return strings.TrimSpace(employee.FirstName + " " + employee.LastName)
+}
+
+// employeeLabel identifica o colaborador nos botões, ex.: "Maria Souza (ID 123)"
+func employeeLabel(employee models.Employee) string {
+	return fmt.Sprintf("%s (ID %d)", employeeName(employee), employee.ID)
+}
@@ -0,0 +1,79 @@
+package telegram
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+func TestSearchEmployees(t *testing.T) {
+	employees := []models.Employee{
+		{ID: 1, FirstName: "João", LastName: "Silva", CPF: "123.456.789-00", RegistrationNumber: "A100", Email: "joao.silva@empresa.com.br"},
+		{ID: 2, FirstName: "Joana", LastName: "Souza", RegistrationNumber: "A101", Email: "joana@empresa.com.br"},
+		{ID: 3, FirstName: "Maria", LastName: "Souza", RegistrationNumber: "B200", Email: "maria.souza@empresa.com.br"},
+		{ID: 4, FirstName: "Mário", LastName: "Santos", RegistrationNumber: "300", Email: "mario@empresa.com.br"},
+	}
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"xyz", []int{}},
+		// Identificadores
+		{"3", []int{3}},
+		{"12345678900", []int{1}},
+		{"123.456.789-00", []int{1}},
+		{"123", []int{1}},
+		{"A10", []int{2, 1}},
+		{"b200", []int{3}},
+		// E-mail vale mais do que o nome
+		{"joana@empresa.com.br", []int{2}},
+		{"souza", []int{3, 2}},
+		{"mario", []int{4, 3}},
+		// Nome: acentos, prefixos, trechos e erros de digitação
+		{"JOAO", []int{1}},
+		{"jo", []int{2, 1}},
+		{"ilv", []int{1}},
+		{"silvaa", []int{1}},
+		{"maria souza", []int{3}},
+		{"maria santos", []int{4}},
+		{"joana silva", []int{}},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, employee := range searchEmployees(employees, tt.query) {
+			got = append(got, employee.ID)
+		}
+		if tt.want != nil && got == nil {
+			got = []int{}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("searchEmployees(%q) = %v, esperado %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestWordScore(t *testing.T) {
+	tests := []struct {
+		word, nameWord string
+		want           int
+	}{
+		{"MARIA", "MARIA", scoreWordExact},
+		{"MAR", "MARIA", scoreWordPrefix},
+		{"ARI", "MARIA", scoreWordContain},
+		{"AR", "MARIA", 0},
+		{"MARIO", "MARIA", scoreWordTypo},
+		{"JOA", "JOANA", scoreWordPrefix},
+		{"JAONA", "JOANA", 0},
+		{"JOANNAS", "JOANAS", scoreWordTypo},
+		{"FERNADNO", "FERNANDO", scoreWordTypo},
+		{"ANA", "ANE", 0},
+	}
+	for _, tt := range tests {
+		if got := wordScore(tt.word, tt.nameWord); got != tt.want {
+			t.Errorf("wordScore(%q, %q) = %d, esperado %d", tt.word, tt.nameWord, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+	"github.com/jeffemart/PontoGo/app/internal/export"
+	"github.com/jeffemart/PontoGo/app/internal/jobs"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/scheduler"
+	"github.com/jeffemart/PontoGo/app/internal/services/pontomais"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// Tarefas que podem ser agendadas no SCHEDULES_FILE ou pelo /agendar
+const (
+	taskDigest   = "ocorrencias" // Relatório de ocorrências nas batidas do dia anterior
+	taskBalances = "saldos"      // Planilha com o banco de horas de todos os colaboradores no mês anterior
+	taskImport   = "importacao"  // Importação de uma planilha salva
+)
+
+// setupScheduler registra as tarefas e substitui os agendamentos da
+// configuração pelos de SCHEDULES_FILE e DIGEST_TIME
+func (b *Bot) setupScheduler() error {
+	b.scheduler.Register(taskDigest, b.digestTask)
+	b.scheduler.Register(taskBalances, b.balancesTask)
+	b.scheduler.Register(taskImport, b.importTask)
+	b.scheduler.OnResult(b.reportScheduleResult)
+
+	entries, err := scheduler.LoadFile(b.config.SchedulesFile)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Task == taskImport && entry.Args["arquivo"] == "" {
+			return fmt.Errorf("agendamento de importação em %s sem o argumento \"arquivo\"", b.config.SchedulesFile)
+		}
+	}
+
+	// DIGEST_TIME equivale a um agendamento diário do relatório de ocorrências
+	if b.config.DigestTime != "" {
+		at, err := time.Parse("15:04", b.config.DigestTime)
+		if err != nil {
+			return fmt.Errorf("horário inválido em DIGEST_TIME: %q", b.config.DigestTime)
+		}
+		entries = append(entries, scheduler.Entry{Task: taskDigest, Spec: fmt.Sprintf("%d %d * * *", at.Minute(), at.Hour())})
+	}
+
+	if err := b.scheduler.SetConfigEntries(entries); err != nil {
+		return err
+	}
+	for _, entry := range b.scheduler.List() {
+		utils.Logger.Printf("Agendamento #%d: %s (%s), próxima execução em %s", entry.ID, entry.Task, entry.When(), formatNextRun(entry))
+	}
+	return nil
+}
+
+// scheduleChat devolve o chat que recebe o resultado do agendamento
+func (b *Bot) scheduleChat(entry scheduler.Entry) int64 {
+	if entry.ChatID != 0 {
+		return entry.ChatID
+	}
+	return b.config.ScheduleChat
+}
+
+// reportScheduleResult envia ao chat do agendamento o resultado de uma
+// execução: sucesso ou falha, o resumo da tarefa e a próxima execução
+func (b *Bot) reportScheduleResult(result scheduler.Result) {
+	entry := result.Entry
+	// A planilha de uma importação única não é mais necessária após a execução
+	if entry.Once() && !result.Manual {
+		removeScheduleFile(entry)
+	}
+
+	label := fmt.Sprintf("Agendamento #%d (%s, %s)", entry.ID, entry.Task, entry.When())
+	var text strings.Builder
+	if result.Err != nil {
+		utils.Logger.Printf("%s falhou: %v", label, result.Err)
+		text.WriteString(fmt.Sprintf("%s falhou: %v\n", label, result.Err))
+	} else {
+		utils.Logger.Printf("%s concluído em %s", label, result.Finished.Sub(result.Started).Round(time.Second))
+		text.WriteString(fmt.Sprintf("%s concluído.\n", label))
+	}
+	if result.Manual {
+		text.WriteString("Execução pedida pelo /agendar executar.\n")
+	}
+	if result.Late {
+		text.WriteString("Execução atrasada: o bot estava parado no horário previsto.\n")
+	}
+	if result.Output != "" {
+		text.WriteString("\n" + result.Output + "\n")
+	}
+	if !entry.Next.IsZero() {
+		text.WriteString(fmt.Sprintf("\nPróxima execução: %s", formatNextRun(entry)))
+	}
+	b.api.Send(tgbotapi.NewMessage(b.scheduleChat(entry), text.String()))
+}
+
+// formatNextRun descreve a próxima execução do agendamento
+func formatNextRun(entry scheduler.Entry) string {
+	if entry.Next.IsZero() {
+		return "nenhuma"
+	}
+	return entry.Next.Format("02/01/2006 15:04")
+}
+
+// removeScheduleFile remove a planilha salva de uma importação agendada pelo
+// chat. As planilhas do SCHEDULES_FILE pertencem ao operador e são mantidas.
+func removeScheduleFile(entry scheduler.Entry) {
+	path := entry.Args["arquivo"]
+	if entry.Source != scheduler.SourceChat || path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		utils.Logger.Printf("Erro ao remover a planilha do agendamento #%d: %v", entry.ID, err)
+	}
+}
+
+// balancesTask é a tarefa agendada "saldos": envia ao chat do agendamento a
+// planilha com os créditos, os débitos e o saldo de todos os colaboradores
+// ativos no mês anterior
+func (b *Bot) balancesTask(ctx context.Context, entry scheduler.Entry) (string, error) {
+	start, end := currentPeriod(time.Now())
+	start, end = start.AddDate(0, -1, 0), start.AddDate(0, 0, -1)
+
+	employees, err := b.client.GetEmployees(ctx, pontomais.EmployeeFilter{Attributes: employeeDetailAttributes})
+	if err != nil {
+		return "", fmt.Errorf("erro ao buscar colaboradores: %s", describeError(err))
+	}
+	entries, err := b.client.ListTimeBalanceEntries(ctx, pontomais.TimeBalanceEntryFilter{})
+	if err != nil {
+		return "", fmt.Errorf("erro ao buscar os lançamentos do banco de horas: %s", describeError(err))
+	}
+	byEmployee := make(map[int][]models.TimeBalanceEntryRecord)
+	for _, record := range entries {
+		byEmployee[record.EmployeeID] = append(byEmployee[record.EmployeeID], record)
+	}
+
+	table := balancesTable(employees, byEmployee, start, end)
+	data, err := table.XLSX()
+	if err != nil {
+		return "", fmt.Errorf("erro ao gerar a planilha: %v", err)
+	}
+	name := fmt.Sprintf("saldos_%s.xlsx", start.Format("2006-01"))
+	doc := tgbotapi.NewDocumentUpload(b.scheduleChat(entry), tgbotapi.FileBytes{Name: name, Bytes: data})
+	doc.Caption = fmt.Sprintf("Banco de horas de %s: %d colaboradores.", start.Format("01/2006"), len(employees))
+	if _, err := b.api.Send(doc); err != nil {
+		return "", fmt.Errorf("erro ao enviar a planilha: %v", err)
+	}
+	return fmt.Sprintf("Planilha %s enviada: %d colaboradores, %d lançamentos no total.", name, len(employees), len(entries)), nil
+}
+
+// balancesTable monta a planilha da tarefa "saldos", com uma linha por
+// colaborador: créditos, débitos e saldo do período e o saldo atual
+func balancesTable(employees []models.Employee, entries map[int][]models.TimeBalanceEntryRecord, start, end time.Time) *export.Table {
+	table := &export.Table{
+		Sheet: "Saldos",
+		Headers: []string{"ID", "NOME", "CPF", "MATRICULA", "CENTRO_DE_CUSTO", "PERIODO", "CREDITOS", "DEBITOS",
+			"SALDO_PERIODO", "SALDO_ATUAL", "SALDO_ATUAL_SEGUNDOS"},
+	}
+	for _, employee := range employees {
+		summary := pontomais.SummarizeTimeBalance(entries[employee.ID], start, end, 0)
+		table.Rows = append(table.Rows, []interface{}{
+			employee.ID,
+			employeeName(employee),
+			employee.CPF,
+			employee.RegistrationNumber,
+			employee.CostCenter,
+			start.Format("01/2006"),
+			duration.Format(summary.PeriodCredits),
+			duration.Format(summary.PeriodDebits),
+			duration.FormatSigned(summary.PeriodCredits - summary.PeriodDebits),
+			duration.FormatSigned(summary.Balance),
+			summary.Balance,
+		})
+	}
+	return table
+}
+
+// importTask é a tarefa agendada "importacao": lê a planilha salva no
+// agendamento e inicia a importação sem pedir confirmação, pois ela foi dada
+// ao agendar. O andamento segue nas mensagens da importação e no /status.
+func (b *Bot) importTask(ctx context.Context, entry scheduler.Entry) (string, error) {
+	filePath := entry.Args["arquivo"]
+	if filePath == "" {
+		return "", fmt.Errorf("o agendamento não tem uma planilha para importar")
+	}
+	name := entry.Args["nome"]
+	if name == "" {
+		name = filepath.Base(filePath)
+	}
+
+	sheet, warning, err := b.readImport(filePath, entry.Args["aba"])
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler a planilha %s: %v", name, err)
+	}
+	summary := sheet.Summarize()
+	text := warning + formatImportSummary(summary)
+	if summary.ValidRows == 0 {
+		return text + "\nNenhuma linha nova para lançar.", nil
+	}
+
+	user := entry.CreatedBy
+	if user == "" {
+		user = fmt.Sprintf("agendamento #%d", entry.ID)
+	}
+	job := b.newImportJob(sheet, b.scheduleChat(entry), user, name, entry.Args["aba"], jobs.StateRunning)
+	job, err = b.jobs.Create(job, filePath)
+	if err != nil {
+		return "", fmt.Errorf("erro ao salvar a planilha para processamento: %v", err)
+	}
+	utils.Logger.Printf("Importação #%d iniciada pelo agendamento #%d", job.ID, entry.ID)
+	b.startJob(job.ID)
+	return text + fmt.Sprintf("\nImportação #%d iniciada com %d linhas válidas. Acompanhe com /status %d ou interrompa com /cancelar %d.",
+		job.ID, summary.ValidRows, job.ID, job.ID), nil
+}
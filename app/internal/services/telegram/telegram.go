@@ -1,54 +1,129 @@
 package telegram
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
+	"sync"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/importer"
+	"github.com/jeffemart/PontoGo/app/internal/jobs"
+	"github.com/jeffemart/PontoGo/app/internal/journal"
 	"github.com/jeffemart/PontoGo/app/internal/models"
-	services "github.com/jeffemart/PontoGo/app/internal/services/pontomais"
+	"github.com/jeffemart/PontoGo/app/internal/scheduler"
+	"github.com/jeffemart/PontoGo/app/internal/services/pontomais"
 	"github.com/jeffemart/PontoGo/app/internal/utils"
-	"github.com/xuri/excelize/v2"
 )
 
 // Bot representa a estrutura do bot do Telegram
 type Bot struct {
 	api              *tgbotapi.BotAPI
 	config           *models.Config
+	client           *pontomais.Client
+	journal          *journal.Journal // Diário das operações feitas pelo bot, usado pelo /desfazer
+	ledger           *importer.Ledger // Chaves das linhas de planilhas já lançadas
 	hosts            map[int64]bool
-	awaitingDocument map[int64]string // Mapa para rastrear usuários aguardando documentos
+	jobs             *jobs.Store             // Importações de planilhas, retomadas após reinícios
+	scheduler        *scheduler.Scheduler    // Tarefas recorrentes e agendadas pelo /agendar
+	ctx              context.Context         // Contexto de execução do bot, cancelado no encerramento
+	runningJobs      sync.WaitGroup          // Importações em execução, aguardadas no encerramento
+	mapping          importer.Mapping        // Apelidos aceitos nos cabeçalhos das planilhas
+	mu               sync.Mutex              // Protege conversations, pendingEntries, searches e employeeLists
+	conversations    map[int64]*conversation // Conversa em andamento em cada chat (assistentes e envio de planilhas)
+	pendingEntries   map[int]*pendingEntry   // Lançamentos do /criar e /editar aguardando confirmação
+	nextPendingEntry int
+	searches         map[int]*employeeSearch // Resultados do /buscar, navegados pelos botões
+	nextSearch       int
+	employeeLists    map[listMessage]*employeeList // Colaboradores exibidos em cada mensagem do /listar
 }
 
-// NewBot cria uma nova instância do bot do Telegram
-func NewBot(cfg *models.Config) (*Bot, error) {
+// NewBot cria uma nova instância do bot do Telegram usando o cliente do Ponto Mais informado
+func NewBot(cfg *models.Config, client *pontomais.Client) (*Bot, error) {
 	bot, err := tgbotapi.NewBotAPI(cfg.TelegramBotToken)
 	if err != nil {
 		utils.Logger.Printf("Erro ao criar o bot do Telegram: %v", err)
 		return nil, err
 	}
 
+	// Abre o diário de operações, persistido no diretório de dados
+	operations, err := journal.Open(filepath.Join(cfg.DataDir, "journal.json"))
+	if err != nil {
+		utils.Logger.Printf("Erro ao abrir o diário de operações: %v", err)
+		return nil, err
+	}
+
+	// Abre o ledger que evita lançar duas vezes a mesma linha de uma planilha
+	ledger, err := importer.OpenLedger(filepath.Join(cfg.DataDir, "import_ledger.jsonl"))
+	if err != nil {
+		utils.Logger.Printf("Erro ao abrir o ledger de importações: %v", err)
+		return nil, err
+	}
+
+	// Abre os jobs de importação de planilhas
+	importJobs, err := jobs.OpenStore(filepath.Join(cfg.DataDir, "jobs"))
+	if err != nil {
+		utils.Logger.Printf("Erro ao abrir os jobs de importação: %v", err)
+		return nil, err
+	}
+
+	// Abre os agendamentos de tarefas
+	schedules, err := scheduler.Open(filepath.Join(cfg.DataDir, "schedules.json"))
+	if err != nil {
+		utils.Logger.Printf("Erro ao abrir os agendamentos: %v", err)
+		return nil, err
+	}
+
+	// Carrega os apelidos das colunas das planilhas de importação
+	mapping, err := importer.LoadMapping(cfg.ImportColumnsFile)
+	if err != nil {
+		utils.Logger.Printf("Erro ao carregar o mapeamento de colunas: %v", err)
+		return nil, err
+	}
+
 	// Configura os hosts autorizados
 	hosts := make(map[int64]bool)
 	for _, hostID := range cfg.TelegramHosts {
 		hosts[hostID] = true
 	}
 
+	b := &Bot{
+		api:            bot,
+		ctx:            context.Background(), // Substituído pelo contexto do Start
+		config:         cfg,
+		client:         client,
+		journal:        operations,
+		ledger:         ledger,
+		jobs:           importJobs,
+		scheduler:      schedules,
+		hosts:          hosts,
+		mapping:        mapping,
+		conversations:  make(map[int64]*conversation),
+		pendingEntries: make(map[int]*pendingEntry),
+		searches:       make(map[int]*employeeSearch),
+		employeeLists:  make(map[listMessage]*employeeList),
+	}
+
+	// Registra as tarefas e os agendamentos da configuração
+	if err := b.setupScheduler(); err != nil {
+		utils.Logger.Printf("Erro ao configurar os agendamentos: %v", err)
+		return nil, err
+	}
+
 	utils.Logger.Printf("Bot do Telegram criado com sucesso: @%s", bot.Self.UserName)
-	return &Bot{
-		api:              bot,
-		config:           cfg,
-		hosts:            hosts,
-		awaitingDocument: make(map[int64]string),
-	}, nil
+	return b, nil
 }
 
-// Start inicia o bot do Telegram
-func (b *Bot) Start() {
+// Start inicia o bot do Telegram e processa as atualizações até ctx ser
+// cancelado. Ao encerrar, para de receber atualizações e aguarda o término das
+// que já estavam em andamento e da tarefa agendada em execução; as importações
+// em execução param na linha atual e são retomadas no próximo início.
+func (b *Bot) Start(ctx context.Context) {
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
@@ -59,39 +134,72 @@ func (b *Bot) Start() {
 
 	utils.Logger.Printf("Bot iniciado com sucesso: @%s", b.api.Self.UserName)
 
-	for update := range updates {
-		if update.Message == nil {
-			continue
-		}
+	// Retoma as importações interrompidas por um reinício
+	b.ctx = ctx
+	b.resumeJobs()
 
-		// Verifica se o usuário está autorizado
-		if !b.hosts[update.Message.Chat.ID] {
-			utils.Logger.Printf("Tentativa de acesso não autorizado do chat ID: %d", update.Message.Chat.ID)
-			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Você não está autorizado a usar este bot.")
-			b.api.Send(msg)
-			continue
-		}
+	// Encerra as conversas que ficarem sem resposta
+	go b.sweepConversations(ctx)
+
+	// Remove os jobs antigos e as planilhas não confirmadas
+	go b.pruneJobsPeriodically(ctx)
+
+	// Executa as tarefas agendadas
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		b.scheduler.Run(ctx)
+	}()
 
-		// Verifica se o usuário está aguardando um documento
-		if command, ok := b.awaitingDocument[update.Message.Chat.ID]; ok {
-			if update.Message.Document != nil {
-				// Processa o documento recebido
-				b.handleDocumentReceived(update.Message, command)
-				// Remove o usuário da lista de espera
-				delete(b.awaitingDocument, update.Message.Chat.ID)
-			} else {
-				// Se não for um documento, envia uma mensagem de erro
-				msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Por favor, envie um arquivo Excel (.xlsx).")
-				b.api.Send(msg)
-			}
+	d := newDispatcher(b.config.TelegramWorkers, b.handleUpdate)
+	for {
+		select {
+		case update := <-updates:
+			d.dispatch(update)
 			continue
+		case <-ctx.Done():
 		}
+		break
+	}
 
-		// Processa os comandos
-		if update.Message.IsCommand() {
-			utils.Logger.Printf("Comando recebido: %s do chat ID: %d", update.Message.Command(), update.Message.Chat.ID)
-			b.handleCommand(update.Message)
-		}
+	utils.Logger.Println("Encerrando o bot: aguardando as operações em andamento...")
+	b.api.StopReceivingUpdates()
+	d.wait()
+	<-schedulerDone
+	b.runningJobs.Wait()
+	utils.Logger.Println("Bot encerrado")
+}
+
+// handleUpdate processa uma atualização recebida do Telegram
+func (b *Bot) handleUpdate(update tgbotapi.Update) {
+	// Processa os cliques em botões inline
+	if update.CallbackQuery != nil {
+		b.handleCallback(update.CallbackQuery)
+		return
+	}
+
+	if update.Message == nil {
+		return
+	}
+
+	// Verifica se o usuário está autorizado
+	if !b.hosts[update.Message.Chat.ID] {
+		utils.Logger.Printf("Tentativa de acesso não autorizado do chat ID: %d", update.Message.Chat.ID)
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Você não está autorizado a usar este bot.")
+		b.api.Send(msg)
+		return
+	}
+
+	// Processa os comandos, inclusive durante uma conversa (ex.: /cancelar)
+	if update.Message.IsCommand() {
+		utils.Logger.Printf("Comando recebido: %s do chat ID: %d", update.Message.Command(), update.Message.Chat.ID)
+		b.handleCommand(update.Message)
+		return
+	}
+
+	// As demais mensagens respondem à conversa em andamento, se houver
+	if conv, ok := b.activeConversation(update.Message.Chat.ID); ok {
+		b.handleConversationMessage(update.Message, conv)
 	}
 }
 
@@ -104,12 +212,32 @@ func (b *Bot) handleCommand(message *tgbotapi.Message) {
 		b.handleHelp(message)
 	case "listar":
 		b.handleListEmployees(message)
+	case "buscar":
+		b.handleSearchEmployees(message)
+	case "saldo":
+		b.handleBalance(message)
+	case "historico":
+		b.handleHistory(message)
+	case "batidas":
+		b.handlePunches(message)
+	case "ocorrencias":
+		b.handleDigest(message)
+	case "agendar":
+		b.handleSchedule(message)
 	case "editar":
 		b.handleEditTimeBalance(message)
 	case "criar":
 		b.handleCreateTimeBalance(message)
+	case "excluir":
+		b.handleDeleteTimeBalance(message)
+	case "desfazer":
+		b.handleUndo(message)
 	case "relatorio":
 		b.handleRelatorio(message)
+	case "status":
+		b.handleJobStatus(message)
+	case "cancelar":
+		b.handleCancelJob(message)
 	default:
 		utils.Logger.Printf("Comando desconhecido recebido: %s", message.Command())
 		msg := tgbotapi.NewMessage(message.Chat.ID, "Comando desconhecido. Use /help para ver os comandos disponíveis.")
@@ -125,303 +253,118 @@ func (b *Bot) handleStart(message *tgbotapi.Message) {
 	b.api.Send(msg)
 }
 
-// handleHelp envia a lista de comandos disponíveis
+// handleHelp envia a lista de comandos disponíveis, gerada a partir dos
+// esquemas dos comandos, ou a ajuda detalhada do comando pedido
 func (b *Bot) handleHelp(message *tgbotapi.Message) {
 	utils.Logger.Printf("Comando /help recebido do chat ID: %d", message.Chat.ID)
-	helpText := `Comandos disponíveis:
-
-/start - Inicia o bot
-/help - Mostra esta mensagem de ajuda
-/listar - Lista todos os colaboradores ativos
-/editar <ID> <quantidade_segundos> <data> <observação> <retirada> - Edita o banco de horas de um colaborador
-/criar <ID_funcionário> <quantidade_segundos> <data> <observação> <retirada> - Cria um novo lançamento no banco de horas
-/relatorio - Permite processar múltiplos lançamentos de banco de horas a partir de um arquivo Excel.
-
-Exemplo de edição:
-/editar 59 9000.0 2023-05-15 "2.5 horas extras" false
-
-Exemplo de criação:
-/criar 1487972 60.0 2023-05-15 "1 minuto de trabalho" false
-
-Parâmetros:
-- ID: ID do registro no banco de horas (para edição)
-- ID_funcionário: ID do funcionário (para criação)
-- quantidade_segundos: Valor em segundos (use ponto para decimais)
-- data: Data no formato YYYY-MM-DD
-- observação: Texto entre aspas com o motivo
-- retirada: true para retirada, false para adição`
-
-	msg := tgbotapi.NewMessage(message.Chat.ID, helpText)
-	b.api.Send(msg)
-}
-
-// handleListEmployees lista todos os colaboradores ativos
-func (b *Bot) handleListEmployees(message *tgbotapi.Message) {
-	utils.Logger.Printf("Comando /listar recebido do chat ID: %d", message.Chat.ID)
-	msg := tgbotapi.NewMessage(message.Chat.ID, "Buscando colaboradores...")
-	b.api.Send(msg)
-
-	employees, err := services.GetEmployees(b.config)
-	if err != nil {
-		utils.Logger.Printf("Erro ao buscar colaboradores: %v", err)
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Erro ao buscar colaboradores: %v", err))
-		b.api.Send(errorMsg)
-		return
-	}
-
-	if len(employees) == 0 {
-		utils.Logger.Println("Nenhum colaborador encontrado")
-		noEmployeesMsg := tgbotapi.NewMessage(message.Chat.ID, "Nenhum colaborador encontrado.")
-		b.api.Send(noEmployeesMsg)
+	args, ok := b.parseCommand(message, helpCommand)
+	if !ok {
 		return
 	}
 
-	var response string
-	if len(employees) == 0 {
-		response = "Nenhum funcionário encontrado."
-	} else {
-		// Retorna apenas a quantidade de funcionários
-		response = fmt.Sprintf("Total de funcionários: %d", len(employees))
+	text := helpText()
+	if name := args.String("comando"); name != "" {
+		spec, found := findCommandSpec(name)
+		if !found {
+			b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Comando desconhecido: %s. Use /help para ver os comandos disponíveis.", name)))
+			return
+		}
+		text = spec.Help()
 	}
-
-	utils.Logger.Printf("Encontrados %d colaboradores", len(employees))
-	resultMsg := tgbotapi.NewMessage(message.Chat.ID, response)
-	b.api.Send(resultMsg)
+	b.api.Send(tgbotapi.NewMessage(message.Chat.ID, text))
 }
 
 // handleEditTimeBalance edita o banco de horas de um colaborador
 func (b *Bot) handleEditTimeBalance(message *tgbotapi.Message) {
 	utils.Logger.Printf("Comando /editar recebido do chat ID: %d", message.Chat.ID)
-	// Dividimos a mensagem em partes para extrair os argumentos básicos
-	parts := strings.SplitN(message.Text, " ", 5)
-	if len(parts) < 5 {
-		utils.Logger.Printf("Formato incorreto para o comando /editar: %s", message.Text)
-		helpMsg := tgbotapi.NewMessage(message.Chat.ID,
-			"Formato incorreto. Use:\n/editar <ID> <quantidade_segundos> <data> <observação> <retirada>\n\nExemplo:\n/editar 3833376 60.0 2025-03-18 \"Editando lançamento\" false")
-		b.api.Send(helpMsg)
-		return
-	}
-
-	// Extrai os argumentos básicos
-	entryID := parts[1]
-
-	// Converte a quantidade
-	amount, err := strconv.ParseFloat(parts[2], 64)
-	if err != nil {
-		utils.Logger.Printf("Erro ao converter quantidade para float: %v", err)
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro: A quantidade deve ser um número válido (use ponto para decimais).")
-		b.api.Send(errorMsg)
-		return
-	}
-
-	// Formata a data para o formato esperado pela API (DD/MM/YYYY)
-	inputDate := parts[3]
-	parsedDate, err := time.Parse("2006-01-02", inputDate)
-	if err != nil {
-		utils.Logger.Printf("Erro ao converter data: %v", err)
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro: A data deve estar no formato YYYY-MM-DD.")
-		b.api.Send(errorMsg)
-		return
-	}
-	formattedDate := parsedDate.Format("02/01/2006")
-
-	// Extrai a parte final que contém a observação e o parâmetro de retirada
-	lastPart := parts[4]
-
-	// Procura a última ocorrência de aspas para separar a observação do parâmetro de retirada
-	lastQuoteIndex := strings.LastIndex(lastPart, "\"")
-	if lastQuoteIndex == -1 || lastQuoteIndex == 0 {
-		utils.Logger.Println("Erro: Observação não está entre aspas duplas")
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro: A observação deve estar entre aspas duplas.")
-		b.api.Send(errorMsg)
-		return
-	}
-
-	// Encontra a primeira ocorrência de aspas
-	firstQuoteIndex := strings.Index(lastPart, "\"")
-	if firstQuoteIndex == lastQuoteIndex {
-		utils.Logger.Println("Erro: Observação não está entre aspas duplas")
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro: A observação deve estar entre aspas duplas.")
-		b.api.Send(errorMsg)
+	args, ok := b.parseCommand(message, editCommand)
+	if !ok {
 		return
 	}
 
-	// Extrai a observação entre aspas
-	observation := lastPart[firstQuoteIndex+1 : lastQuoteIndex]
-
-	// Extrai o parâmetro de retirada após a última aspas
-	withdrawPart := strings.TrimSpace(lastPart[lastQuoteIndex+1:])
-
-	// Verifica se o parâmetro de retirada é válido
-	var withdraw bool
-	if withdrawPart == "true" {
-		withdraw = true
-	} else if withdrawPart == "false" {
-		withdraw = false
-	} else {
-		utils.Logger.Printf("Erro: Parâmetro de retirada inválido: %s", withdrawPart)
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro: O parâmetro 'retirada' deve ser 'true' ou 'false'.")
-		b.api.Send(errorMsg)
-		return
-	}
-
-	// Cria a entrada para atualização
+	// Cria a entrada para atualização, com a data no formato da API (DD/MM/YYYY)
+	entryID := strconv.Itoa(args.Int("lancamento"))
 	entry := models.TimeBalanceEntry{
-		Amount:      amount,
-		Date:        formattedDate,
-		Observation: observation,
-		Withdraw:    withdraw,
-	}
-
-	// Envia mensagem de processamento
-	processingMsg := tgbotapi.NewMessage(message.Chat.ID, "Processando atualização do banco de horas...")
-	b.api.Send(processingMsg)
-
-	// Atualiza o banco de horas
-	utils.Logger.Printf("Atualizando banco de horas para o ID: %s", entryID)
-	err = services.UpdateTimeBalanceEntry(b.config, entryID, entry)
-	if err != nil {
-		utils.Logger.Printf("Erro ao atualizar o banco de horas: %v", err)
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Erro ao atualizar o banco de horas: %v", err))
-		b.api.Send(errorMsg)
-		return
+		Amount:      args.Duration("quantidade"),
+		Date:        args.Date("data").Format("02/01/2006"),
+		Observation: args.String("observacao"),
+		Withdraw:    args.Bool("debito"),
 	}
 
-	// Calcula as horas para exibição
-	hoursAmount := amount / 3600.0
-
-	// Envia mensagem de sucesso
-	utils.Logger.Printf("Banco de horas atualizado com sucesso para o ID: %s", entryID)
-	successMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Banco de horas atualizado com sucesso!\n\nID: %s\nQuantidade: %.2f segundos (%.2f horas)\nData: %s\nObservação: %s\nRetirada: %t",
-		entryID, amount, hoursAmount, inputDate, observation, withdraw))
-	b.api.Send(successMsg)
+	// Pede confirmação antes de enviar, mostrando a quantidade interpretada
+	b.confirmEntry(message.Chat.ID, entryID, entry)
 }
 
 // handleCreateTimeBalance cria um novo lançamento no banco de horas de um funcionário
 func (b *Bot) handleCreateTimeBalance(message *tgbotapi.Message) {
 	utils.Logger.Printf("Comando /criar recebido do chat ID: %d", message.Chat.ID)
-	// Dividimos a mensagem em partes para extrair os argumentos básicos
-	parts := strings.SplitN(message.Text, " ", 5)
-	if len(parts) < 5 {
-		utils.Logger.Printf("Formato incorreto para o comando /criar: %s", message.Text)
-		helpMsg := tgbotapi.NewMessage(message.Chat.ID,
-			"Formato incorreto. Use:\n/criar <ID_funcionário> <quantidade_segundos> <data> <observação> <retirada>\n\nExemplo:\n/criar 1487972 3600.0 2023-05-15 \"1 hora de trabalho\" false")
-		b.api.Send(helpMsg)
+	// Sem argumentos, o lançamento é montado pelo assistente
+	if strings.TrimSpace(message.CommandArguments()) == "" {
+		b.startCreateWizard(message)
 		return
 	}
 
-	// Extrai os argumentos básicos
-	employeeID := parts[1]
-
-	// Converte a quantidade diretamente em segundos (sem multiplicar por 3600)
-	secondsAmount, err := strconv.ParseFloat(parts[2], 64)
-	if err != nil {
-		utils.Logger.Printf("Erro ao converter quantidade para float: %v", err)
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro: A quantidade deve ser um número válido (use ponto para decimais).")
-		b.api.Send(errorMsg)
+	args, ok := b.parseCommand(message, createCommand)
+	if !ok {
 		return
 	}
 
-	// Calcula as horas para exibição na mensagem de sucesso
-	hoursAmount := secondsAmount / 3600.0
-
-	// Formata a data
-	inputDate := parts[3]
-	parsedDate, err := time.Parse("2006-01-02", inputDate)
-	if err != nil {
-		utils.Logger.Printf("Erro ao converter data: %v", err)
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro: A data deve estar no formato YYYY-MM-DD.")
-		b.api.Send(errorMsg)
-		return
+	// Cria a entrada para o banco de horas, com a data no formato da API (DD/MM/YYYY)
+	entry := models.TimeBalanceEntry{
+		Amount:      args.Duration("quantidade"),
+		Date:        args.Date("data").Format("02/01/2006"),
+		EmployeeID:  strconv.Itoa(args.Int("funcionario")),
+		Observation: args.String("observacao"),
+		Withdraw:    args.Bool("debito"),
 	}
-	formattedDate := parsedDate.Format("02/01/2006")
 
-	// Extrai a parte final que contém a observação e o parâmetro de retirada
-	lastPart := parts[4]
+	// Pede confirmação antes de enviar, mostrando a quantidade interpretada
+	b.confirmEntry(message.Chat.ID, "", entry)
+}
 
-	// Procura a última ocorrência de aspas para separar a observação do parâmetro de retirada
-	lastQuoteIndex := strings.LastIndex(lastPart, "\"")
-	if lastQuoteIndex == -1 || lastQuoteIndex == 0 {
-		utils.Logger.Println("Erro: Observação não está entre aspas duplas")
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro: A observação deve estar entre aspas duplas.")
-		b.api.Send(errorMsg)
-		return
-	}
+// handleRelatorio solicita ao usuário que envie uma planilha. O argumento
+// opcional escolhe a aba a ler, pelo nome ou pela posição.
+func (b *Bot) handleRelatorio(message *tgbotapi.Message) {
+	utils.Logger.Printf("Comando /relatorio recebido do chat ID: %d", message.Chat.ID)
 
-	// Encontra a primeira ocorrência de aspas
-	firstQuoteIndex := strings.Index(lastPart, "\"")
-	if firstQuoteIndex == lastQuoteIndex {
-		utils.Logger.Println("Erro: Observação não está entre aspas duplas")
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro: A observação deve estar entre aspas duplas.")
-		b.api.Send(errorMsg)
+	args, ok := b.parseCommand(message, relatorioCommand)
+	if !ok {
 		return
 	}
-
-	// Extrai a observação entre aspas
-	observation := lastPart[firstQuoteIndex+1 : lastQuoteIndex]
-
-	// Extrai o parâmetro de retirada após a última aspas
-	withdrawPart := strings.TrimSpace(lastPart[lastQuoteIndex+1:])
-
-	// Verifica se o parâmetro de retirada é válido
-	var withdraw bool
-	if withdrawPart == "true" {
-		withdraw = true
-	} else if withdrawPart == "false" {
-		withdraw = false
-	} else {
-		utils.Logger.Printf("Erro: Parâmetro de retirada inválido: %s", withdrawPart)
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro: O parâmetro 'retirada' deve ser 'true' ou 'false'.")
-		b.api.Send(errorMsg)
-		return
+	sheet := args.String("aba")
+	if sheet == "" {
+		sheet = b.config.ImportSheet
 	}
 
-	// Cria a entrada para o banco de horas
-	entry := models.TimeBalanceEntry{
-		Amount:      secondsAmount,
-		Date:        formattedDate,
-		EmployeeID:  employeeID,
-		Observation: observation,
-		Withdraw:    withdraw,
-	}
-
-	// Envia mensagem de processamento
-	processingMsg := tgbotapi.NewMessage(message.Chat.ID, "Processando criação do lançamento no banco de horas...")
-	b.api.Send(processingMsg)
+	// Aguarda a planilha na conversa do chat
+	b.setConversation(message.Chat.ID, conversation{flow: flowRelatorio, step: stepDocument, sheet: sheet})
 
-	// Cria o lançamento no banco de horas
-	utils.Logger.Printf("Criando lançamento no banco de horas para o funcionário ID: %s", employeeID)
-	err = services.CreateTimeBalanceEntry(b.config, entry)
-	if err != nil {
-		utils.Logger.Printf("Erro ao criar o lançamento no banco de horas: %v", err)
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Erro ao criar o lançamento no banco de horas: %v", err))
-		b.api.Send(errorMsg)
-		return
+	// Envia uma mensagem para o usuário solicitando o arquivo
+	text := "Por favor, envie a planilha com os dados (.xlsx, .ods ou .csv), ou use /cancelar."
+	if sheet != "" {
+		text += fmt.Sprintf("\nSerá lida a aba \"%s\".", sheet)
 	}
-
-	// Envia mensagem de sucesso com a conversão para horas para melhor visualização
-	utils.Logger.Printf("Lançamento no banco de horas criado com sucesso para o funcionário ID: %s", employeeID)
-	successMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Lançamento no banco de horas criado com sucesso!\n\nFuncionário ID: %s\nQuantidade: %.2f segundos (%.2f horas)\nData: %s\nObservação: %s\nRetirada: %t",
-		employeeID, secondsAmount, hoursAmount, inputDate, observation, withdraw))
-	b.api.Send(successMsg)
+	b.api.Send(tgbotapi.NewMessage(message.Chat.ID, text))
 }
 
-// handleRelatorio solicita ao usuário que envie um arquivo Excel
-func (b *Bot) handleRelatorio(message *tgbotapi.Message) {
-	utils.Logger.Printf("Comando /relatorio recebido do chat ID: %d", message.Chat.ID)
+// handleDocumentReceived processa a planilha recebida após o /relatorio,
+// lendo a aba informada
+func (b *Bot) handleDocumentReceived(message *tgbotapi.Message, sheet string) {
+	utils.Logger.Printf("Documento recebido do chat ID: %d para o comando: relatorio", message.Chat.ID)
 
-	// Marca o usuário como aguardando um documento
-	b.awaitingDocument[message.Chat.ID] = "relatorio"
+	filePath, ok := b.downloadDocument(message)
+	if !ok {
+		return
+	}
+	defer os.Remove(filePath) // Garante que o arquivo será removido ao final
 
-	// Envia uma mensagem para o usuário solicitando o arquivo
-	msg := tgbotapi.NewMessage(message.Chat.ID, "Por favor, envie o arquivo Excel com os dados.")
-	b.api.Send(msg)
+	b.processRelatorioFile(message, filePath, sheet)
 }
 
-// handleDocumentReceived processa o documento recebido após um comando
-func (b *Bot) handleDocumentReceived(message *tgbotapi.Message, command string) {
-	utils.Logger.Printf("Documento recebido do chat ID: %d para o comando: %s", message.Chat.ID, command)
-
+// downloadDocument baixa a planilha da mensagem para um arquivo temporário e
+// confere se o formato é suportado. Em caso de erro, avisa o chat e devolve
+// false; caso contrário, quem chama remove o arquivo.
+func (b *Bot) downloadDocument(message *tgbotapi.Message) (string, bool) {
 	// Obtém o arquivo do Telegram
 	fileID := message.Document.FileID
 	file, err := b.api.GetFile(tgbotapi.FileConfig{FileID: fileID})
@@ -429,7 +372,7 @@ func (b *Bot) handleDocumentReceived(message *tgbotapi.Message, command string)
 		utils.Logger.Printf("Erro ao obter o arquivo: %v", err)
 		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro ao obter o arquivo.")
 		b.api.Send(errorMsg)
-		return
+		return "", false
 	}
 
 	// Baixa o arquivo
@@ -439,19 +382,19 @@ func (b *Bot) handleDocumentReceived(message *tgbotapi.Message, command string)
 		utils.Logger.Printf("Erro ao baixar o arquivo: %v", err)
 		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro ao baixar o arquivo.")
 		b.api.Send(errorMsg)
-		return
+		return "", false
 	}
 	defer resp.Body.Close()
 
-	// Cria um arquivo temporário em vez de usar um caminho fixo
-	tempFile, err := os.CreateTemp("", "excel-*.xlsx")
+	// Cria um arquivo temporário em vez de usar um caminho fixo, mantendo a
+	// extensão original para a detecção do formato
+	tempFile, err := os.CreateTemp("", "planilha-*"+strings.ToLower(filepath.Ext(message.Document.FileName)))
 	if err != nil {
 		utils.Logger.Printf("Erro ao criar arquivo temporário: %v", err)
 		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro ao processar o arquivo.")
 		b.api.Send(errorMsg)
-		return
+		return "", false
 	}
-	defer os.Remove(tempFile.Name()) // Garante que o arquivo será removido ao final
 	defer tempFile.Close()
 
 	// Copia o conteúdo do arquivo baixado para o arquivo temporário
@@ -460,191 +403,21 @@ func (b *Bot) handleDocumentReceived(message *tgbotapi.Message, command string)
 		utils.Logger.Printf("Erro ao salvar arquivo temporário: %v", err)
 		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro ao salvar o arquivo.")
 		b.api.Send(errorMsg)
-		return
+		os.Remove(tempFile.Name())
+		return "", false
 	}
 
 	// Fecha o arquivo para garantir que todos os dados foram escritos
 	tempFile.Close()
 
-	// Processa o arquivo de acordo com o comando
-	if command == "relatorio" {
-		b.processRelatorioFile(message, tempFile.Name())
-	}
-}
-
-// processRelatorioFile processa o arquivo Excel do relatório
-func (b *Bot) processRelatorioFile(message *tgbotapi.Message, filePath string) {
-	// Lê o arquivo Excel
-	f, err := excelize.OpenFile(filePath)
+	// Confere se o arquivo é uma planilha suportada antes de processá-lo
+	format, err := importer.DetectFormat(tempFile.Name(), message.Document.FileName, message.Document.MimeType)
 	if err != nil {
-		utils.Logger.Printf("Erro ao abrir o arquivo Excel: %v", err)
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro ao abrir o arquivo Excel.")
-		b.api.Send(errorMsg)
-		return
-	}
-	defer f.Close()
-
-	// Lê as linhas da primeira planilha
-	sheetName := f.GetSheetName(0)
-	rows, err := f.GetRows(sheetName)
-	if err != nil {
-		utils.Logger.Printf("Erro ao ler as linhas da planilha: %v", err)
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "Erro ao ler as linhas da planilha.")
-		b.api.Send(errorMsg)
-		return
-	}
-
-	// Verifica se há linhas suficientes
-	if len(rows) < 2 {
-		utils.Logger.Println("Arquivo Excel não contém dados suficientes")
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, "O arquivo Excel não contém dados suficientes.")
-		b.api.Send(errorMsg)
-		return
-	}
-
-	// Imprime as linhas no console para debug
-	utils.Logger.Println("Dados do arquivo Excel:")
-	for i, row := range rows {
-		utils.Logger.Printf("Linha %d: %v", i, row)
-	}
-
-	// Identifica os índices das colunas com base nos cabeçalhos
-	headers := rows[0]
-	headerMap := make(map[string]int)
-
-	for i, header := range headers {
-		headerMap[strings.ToUpper(strings.TrimSpace(header))] = i
-	}
-
-	// Verifica se todas as colunas necessárias estão presentes
-	requiredHeaders := []string{"ID", "NOME", "DATA", "HORAS", "OBSERVAÇÃO", "DEBITO"}
-	missingHeaders := []string{}
-
-	for _, header := range requiredHeaders {
-		if _, exists := headerMap[header]; !exists {
-			missingHeaders = append(missingHeaders, header)
-		}
-	}
-
-	if len(missingHeaders) > 0 {
-		errorMsg := fmt.Sprintf("Colunas obrigatórias não encontradas: %s", strings.Join(missingHeaders, ", "))
-		utils.Logger.Println(errorMsg)
-		msg := tgbotapi.NewMessage(message.Chat.ID, errorMsg)
-		b.api.Send(msg)
-		return
-	}
-
-	// Envia mensagem de processamento
-	processingMsg := tgbotapi.NewMessage(message.Chat.ID, "Processando lançamentos no banco de horas. Isso pode levar alguns instantes...")
-	b.api.Send(processingMsg)
-
-	// Processa cada linha (exceto o cabeçalho)
-	successCount := 0
-	errorCount := 0
-	errorDetails := make([]string, 0)
-
-	for i, row := range rows {
-		if i == 0 {
-			continue // Pula o cabeçalho
-		}
-
-		// Verifica se a linha tem dados suficientes
-		if len(row) < len(headers) {
-			errorMsg := fmt.Sprintf("Linha %d: Dados insuficientes", i)
-			utils.Logger.Println(errorMsg)
-			errorDetails = append(errorDetails, errorMsg)
-			errorCount++
-			continue
-		}
-
-		// Extrai os dados da linha usando os índices do mapa de cabeçalhos
-		employeeID := row[headerMap["ID"]]
-		employeeName := row[headerMap["NOME"]]
-		dateStr := row[headerMap["DATA"]]
-		secondsStr := row[headerMap["HORAS"]] // Agora tratamos como segundos, não horas
-		observation := row[headerMap["OBSERVAÇÃO"]]
-		debitStr := row[headerMap["DEBITO"]]
-
-		// Converte a data para o formato correto
-		date, err := time.Parse("02/01/2006", dateStr)
-		if err != nil {
-			// Tenta outro formato de data
-			date, err = time.Parse("2006-01-02", dateStr)
-			if err != nil {
-				errorMsg := fmt.Sprintf("Linha %d (%s): Formato de data inválido '%s'", i, employeeName, dateStr)
-				utils.Logger.Println(errorMsg)
-				errorDetails = append(errorDetails, errorMsg)
-				errorCount++
-				continue
-			}
-		}
-		formattedDate := date.Format("02/01/2006")
-
-		// Converte a string de segundos para float
-		seconds, err := strconv.ParseFloat(secondsStr, 64)
-		if err != nil {
-			errorMsg := fmt.Sprintf("Linha %d (%s): Valor de segundos inválido '%s'", i, employeeName, secondsStr)
-			utils.Logger.Println(errorMsg)
-			errorDetails = append(errorDetails, errorMsg)
-			errorCount++
-			continue
-		}
-
-		// Calcula as horas para exibição (apenas para logs)
-		hours := seconds / 3600
-
-		// Determina se é uma retirada
-		withdraw := strings.ToLower(debitStr) == "true" || strings.ToLower(debitStr) == "sim" || strings.ToLower(debitStr) == "s"
-
-		// Cria a entrada para o banco de horas
-		entry := models.TimeBalanceEntry{
-			Amount:      seconds, // Usa os segundos diretamente
-			Date:        formattedDate,
-			EmployeeID:  employeeID,
-			Observation: observation,
-			Withdraw:    withdraw,
-		}
-
-		// Cria o lançamento no banco de horas
-		utils.Logger.Printf("Criando lançamento para o funcionário ID: %s (%s), Segundos: %.2f (%.2f horas), Data: %s",
-			employeeID, employeeName, seconds, hours, dateStr)
-		err = services.CreateTimeBalanceEntry(b.config, entry)
-		if err != nil {
-			errorMsg := fmt.Sprintf("Linha %d (%s): %v", i, employeeName, err)
-			utils.Logger.Println(errorMsg)
-			errorDetails = append(errorDetails, errorMsg)
-			errorCount++
-		} else {
-			successCount++
-			utils.Logger.Printf("Lançamento criado com sucesso para o funcionário ID: %s (%s)", employeeID, employeeName)
-		}
-
-		// Pequena pausa para não sobrecarregar a API
-		time.Sleep(500 * time.Millisecond)
-	}
-
-	// Prepara a mensagem de resultado
-	var resultText strings.Builder
-	resultText.WriteString(fmt.Sprintf("Processamento concluído!\n\nLançamentos criados com sucesso: %d\nErros: %d\n", successCount, errorCount))
-
-	// Adiciona detalhes dos erros, se houver
-	if errorCount > 0 {
-		resultText.WriteString("\nDetalhes dos erros:\n")
-		// Limita a quantidade de erros mostrados para não exceder o limite de mensagem do Telegram
-		maxErrorsToShow := 10
-		if len(errorDetails) > maxErrorsToShow {
-			for i := 0; i < maxErrorsToShow; i++ {
-				resultText.WriteString("- " + errorDetails[i] + "\n")
-			}
-			resultText.WriteString(fmt.Sprintf("... e mais %d erros.\n", len(errorDetails)-maxErrorsToShow))
-		} else {
-			for _, errDetail := range errorDetails {
-				resultText.WriteString("- " + errDetail + "\n")
-			}
-		}
+		utils.Logger.Printf("Arquivo recusado (%s, %s): %v", message.Document.FileName, message.Document.MimeType, err)
+		b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Não foi possível ler o arquivo: %v", err)))
+		os.Remove(tempFile.Name())
+		return "", false
 	}
-
-	// Envia a mensagem com o resultado
-	resultMsg := tgbotapi.NewMessage(message.Chat.ID, resultText.String())
-	b.api.Send(resultMsg)
+	utils.Logger.Printf("Arquivo %s recebido no formato %s", message.Document.FileName, format)
+	return tempFile.Name(), true
 }
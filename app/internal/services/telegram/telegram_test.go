@@ -0,0 +1,153 @@
+package telegram
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/importer"
+	"github.com/jeffemart/PontoGo/app/internal/jobs"
+	"github.com/jeffemart/PontoGo/app/internal/journal"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/services/pontomais"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// testChatID é o chat autorizado dos bots de teste
+const testChatID int64 = 42
+
+// telegramCall é uma chamada feita pelo bot à API do Telegram
+type telegramCall struct {
+	Method string
+	Params map[string]string
+}
+
+// fakeTelegram responde às chamadas do bot no lugar da API do Telegram e
+// guarda as chamadas recebidas
+type fakeTelegram struct {
+	mu    sync.Mutex
+	calls []telegramCall
+}
+
+func (f *fakeTelegram) RoundTrip(req *http.Request) (*http.Response, error) {
+	call := telegramCall{Method: req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:], Params: map[string]string{}}
+	if err := req.ParseForm(); err == nil {
+		for key := range req.PostForm {
+			call.Params[key] = req.PostForm.Get(key)
+		}
+	}
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	f.mu.Unlock()
+
+	body := `{"ok":true,"result":{"message_id":1,"chat":{"id":42}}}`
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// texts devolve os textos enviados ou editados pelo bot, em ordem
+func (f *fakeTelegram) texts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var texts []string
+	for _, call := range f.calls {
+		if text, ok := call.Params["text"]; ok && call.Method != "answerCallbackQuery" {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+// last devolve a última chamada ao método informado
+func (f *fakeTelegram) last(method string) (telegramCall, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := len(f.calls) - 1; i >= 0; i-- {
+		if f.calls[i].Method == method {
+			return f.calls[i], true
+		}
+	}
+	return telegramCall{}, false
+}
+
+// newTestBot cria um bot que fala com um Telegram falso e com o servidor do
+// Ponto Mais informado (nil responde 404 a tudo), guardando diário, ledger e
+// jobs em um diretório temporário
+func newTestBot(t *testing.T, pontoMais http.Handler) (*Bot, *fakeTelegram) {
+	t.Helper()
+	if utils.Logger == nil {
+		utils.Logger = log.New(io.Discard, "", 0)
+	}
+	if pontoMais == nil {
+		pontoMais = http.NotFoundHandler()
+	}
+
+	server := httptest.NewServer(pontoMais)
+	t.Cleanup(server.Close)
+	cfg := &models.Config{
+		PontoMaisBaseURL: server.URL,
+		PontoMaisToken:   base64.StdEncoding.EncodeToString([]byte("token")),
+		PontoMaisTimeout: time.Second,
+		TelegramHosts:    []int64{testChatID},
+		DataDir:          t.TempDir(),
+	}
+	client, err := pontomais.NewClient(cfg, pontomais.WithRetryPolicy(pontomais.RetryPolicy{MaxAttempts: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	operations, err := journal.Open(filepath.Join(cfg.DataDir, "journal.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ledger, err := importer.OpenLedger(filepath.Join(cfg.DataDir, "import_ledger.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	importJobs, err := jobs.OpenStore(filepath.Join(cfg.DataDir, "jobs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &fakeTelegram{}
+	b := &Bot{
+		api:            &tgbotapi.BotAPI{Token: "teste", Client: &http.Client{Transport: fake}},
+		ctx:            context.Background(),
+		config:         cfg,
+		client:         client,
+		journal:        operations,
+		ledger:         ledger,
+		jobs:           importJobs,
+		hosts:          map[int64]bool{testChatID: true},
+		conversations:  make(map[int64]*conversation),
+		pendingEntries: make(map[int]*pendingEntry),
+		searches:       make(map[int]*employeeSearch),
+		employeeLists:  make(map[listMessage]*employeeList),
+	}
+	return b, fake
+}
+
+// callbackQuery monta o clique em um botão de uma mensagem do chat de teste
+func callbackQuery(data string) *tgbotapi.CallbackQuery {
+	return &tgbotapi.CallbackQuery{
+		ID:      "1",
+		From:    &tgbotapi.User{ID: 7, FirstName: "Ana"},
+		Message: &tgbotapi.Message{MessageID: 10, Chat: &tgbotapi.Chat{ID: testChatID}},
+		Data:    data,
+	}
+}
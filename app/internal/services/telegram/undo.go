@@ -0,0 +1,225 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+	"github.com/jeffemart/PontoGo/app/internal/journal"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// undoListSize é a quantidade de operações exibidas em "/desfazer lista"
+const undoListSize = 10
+
+// recordOperation registra no diário uma operação concluída com sucesso. Falhas
+// ao gravar não interrompem o fluxo, apenas são registradas no log.
+func (b *Bot) recordOperation(op journal.Operation) {
+	if _, err := b.journal.Record(op); err != nil {
+		utils.Logger.Printf("Erro ao registrar a operação no diário: %v", err)
+	}
+}
+
+// handleUndo trata o comando /desfazer:
+//
+//	/desfazer        pede confirmação para desfazer a operação mais recente
+//	/desfazer lista  lista as últimas operações para escolher qual desfazer
+//	/desfazer <N>    pede confirmação para desfazer a operação N
+func (b *Bot) handleUndo(message *tgbotapi.Message) {
+	utils.Logger.Printf("Comando /desfazer recebido do chat ID: %d", message.Chat.ID)
+	args, ok := b.parseCommand(message, undoCommand)
+	if !ok {
+		return
+	}
+	arg := args.String("operacao")
+
+	switch {
+	case arg == "":
+		ops := b.journal.Recent(message.Chat.ID, 1)
+		if len(ops) == 0 {
+			b.api.Send(tgbotapi.NewMessage(message.Chat.ID, "Nenhuma operação para desfazer."))
+			return
+		}
+		b.sendUndoConfirmation(message.Chat.ID, ops[0])
+
+	case arg == "lista":
+		ops := b.journal.Recent(message.Chat.ID, undoListSize)
+		if len(ops) == 0 {
+			b.api.Send(tgbotapi.NewMessage(message.Chat.ID, "Nenhuma operação para desfazer."))
+			return
+		}
+
+		var text strings.Builder
+		text.WriteString("Últimas operações (toque para desfazer):\n")
+		rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(ops))
+		for _, op := range ops {
+			text.WriteString("\n" + describeOperation(op))
+			label := fmt.Sprintf("#%d %s lançamento %d", op.ID, op.Kind, op.EntryID)
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(label, callbackData("desfazer", "ver", strconv.Itoa(op.ID))),
+			))
+		}
+		msg := tgbotapi.NewMessage(message.Chat.ID, text.String())
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+		b.api.Send(msg)
+
+	default:
+		id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+		if err != nil {
+			b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Erro: operacao inválida '%s': use \"lista\" ou o número da operação.\n\nUse:\n%s", arg, undoCommand.Usage())))
+			return
+		}
+		op, ok := b.journal.Get(id)
+		if !ok || op.ChatID != message.Chat.ID {
+			b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Operação %d não encontrada.", id)))
+			return
+		}
+		if op.Reverted() {
+			b.api.Send(tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("A operação %d já foi desfeita.", id)))
+			return
+		}
+		b.sendUndoConfirmation(message.Chat.ID, op)
+	}
+}
+
+// sendUndoConfirmation descreve a operação e pede confirmação para desfazê-la
+func (b *Bot) sendUndoConfirmation(chatID int64, op journal.Operation) {
+	text := fmt.Sprintf("Deseja desfazer esta operação?\n\n%s\n\n%s", describeOperation(op), describeRevert(op))
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = confirmKeyboard("desfazer", strconv.Itoa(op.ID))
+	b.api.Send(msg)
+}
+
+// handleUndoCallback trata os botões do /desfazer
+func (b *Bot) handleUndoCallback(query *tgbotapi.CallbackQuery, args []string) {
+	chatID := query.Message.Chat.ID
+	messageID := query.Message.MessageID
+	if len(args) != 2 {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+	id, err := strconv.Atoi(args[1])
+	if err != nil {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+	op, ok := b.journal.Get(id)
+	if !ok || op.ChatID != chatID {
+		b.answerCallback(query, "Operação não encontrada.")
+		return
+	}
+
+	switch args[0] {
+	case "ver":
+		b.answerCallback(query, "")
+		b.sendUndoConfirmation(chatID, op)
+	case "cancelar":
+		b.answerCallback(query, "Cancelado.")
+		b.editMessage(chatID, messageID, fmt.Sprintf("A operação #%d foi mantida.", op.ID), nil)
+	case "confirmar":
+		if op.Reverted() {
+			b.answerCallback(query, "Esta operação já foi desfeita.")
+			b.editMessage(chatID, messageID, fmt.Sprintf("A operação #%d já foi desfeita.", op.ID), nil)
+			return
+		}
+		b.answerCallback(query, "Desfazendo...")
+		user := userLabel(query.From)
+		if err := b.revertOperation(b.ctx, op, user); err != nil {
+			utils.Logger.Printf("Erro ao desfazer a operação %d: %v", op.ID, err)
+			b.editMessage(chatID, messageID, fmt.Sprintf("Erro ao desfazer a operação #%d: %s", op.ID, describeError(err)), nil)
+			return
+		}
+		if err := b.journal.MarkReverted(op.ID, user); err != nil {
+			utils.Logger.Printf("Erro ao marcar a operação %d como desfeita: %v", op.ID, err)
+		}
+		utils.Logger.Printf("Operação %d (%s do lançamento %d) desfeita por %s no chat ID: %d", op.ID, op.Kind, op.EntryID, user, chatID)
+		b.editMessage(chatID, messageID, fmt.Sprintf("Operação #%d desfeita com sucesso.\n\n%s", op.ID, describeRevert(op)), nil)
+	default:
+		b.answerCallback(query, "Botão inválido.")
+	}
+}
+
+// revertOperation desfaz uma operação no Ponto Mais: lançamentos criados são
+// excluídos, editados voltam aos valores anteriores e excluídos são recriados.
+// A recriação é registrada no diário como uma nova criação feita por user, para
+// que também possa ser desfeita.
+func (b *Bot) revertOperation(ctx context.Context, op journal.Operation, user string) error {
+	entryID := strconv.Itoa(op.EntryID)
+	switch op.Kind {
+	case journal.OperationCreate:
+		return b.client.DeleteTimeBalanceEntry(ctx, entryID)
+	case journal.OperationUpdate:
+		if op.Previous == nil {
+			return fmt.Errorf("os valores anteriores do lançamento %s não foram registrados", entryID)
+		}
+		_, err := b.client.UpdateTimeBalanceEntry(ctx, entryID, *op.Previous)
+		return err
+	case journal.OperationDelete:
+		if op.Previous == nil {
+			return fmt.Errorf("os valores do lançamento excluído %s não foram registrados", entryID)
+		}
+		created, err := b.client.CreateTimeBalanceEntry(ctx, *op.Previous)
+		if err != nil {
+			return err
+		}
+		if created.ID == 0 {
+			return errMissingEntryID
+		}
+		b.recordOperation(journal.Operation{
+			Kind:    journal.OperationCreate,
+			ChatID:  op.ChatID,
+			User:    user,
+			EntryID: created.ID,
+			Current: op.Previous,
+		})
+		utils.Logger.Printf("Lançamento %s recriado com o novo ID %d", entryID, created.ID)
+		return nil
+	default:
+		return fmt.Errorf("tipo de operação desconhecido: %s", op.Kind)
+	}
+}
+
+// describeOperation resume uma operação do diário em uma linha
+func describeOperation(op journal.Operation) string {
+	when := op.CreatedAt.Format("02/01 15:04")
+	switch op.Kind {
+	case journal.OperationCreate:
+		return fmt.Sprintf("#%d [%s] %s criou o lançamento %d: %s", op.ID, when, op.User, op.EntryID, describeEntry(op.Current))
+	case journal.OperationUpdate:
+		return fmt.Sprintf("#%d [%s] %s editou o lançamento %d: %s", op.ID, when, op.User, op.EntryID, describeEntry(op.Current))
+	case journal.OperationDelete:
+		return fmt.Sprintf("#%d [%s] %s excluiu o lançamento %d: %s", op.ID, when, op.User, op.EntryID, describeEntry(op.Previous))
+	default:
+		return fmt.Sprintf("#%d [%s] %s %s o lançamento %d", op.ID, when, op.User, op.Kind, op.EntryID)
+	}
+}
+
+// describeRevert explica o que será feito ao desfazer a operação
+func describeRevert(op journal.Operation) string {
+	switch op.Kind {
+	case journal.OperationCreate:
+		return fmt.Sprintf("Desfazer exclui o lançamento %d.", op.EntryID)
+	case journal.OperationUpdate:
+		return fmt.Sprintf("Desfazer restaura o lançamento %d para: %s", op.EntryID, describeEntry(op.Previous))
+	case journal.OperationDelete:
+		return fmt.Sprintf("Desfazer recria o lançamento excluído (ele receberá um novo ID): %s", describeEntry(op.Previous))
+	default:
+		return ""
+	}
+}
+
+// describeEntry resume os dados de um lançamento em uma linha
+func describeEntry(entry *models.TimeBalanceEntry) string {
+	if entry == nil {
+		return "(dados não registrados)"
+	}
+	kind := "crédito"
+	if entry.Withdraw {
+		kind = "retirada"
+	}
+	return fmt.Sprintf("%s de %s em %s, \"%s\"", duration.Format(entry.Amount), kind, entry.Date, entry.Observation)
+}
@@ -0,0 +1,82 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jeffemart/PontoGo/app/internal/journal"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// createServer responde à criação de lançamentos com o ID informado
+func createServer(id int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/time_balance_entries" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"time_balance_entry":{"id":%d,"employee_id":12,"amount":3600,"date":"2025-03-10"}}`, id)
+	})
+}
+
+func TestRevertDeleteRecordsCreate(t *testing.T) {
+	previous := models.TimeBalanceEntry{EmployeeID: "12", Amount: 3600, Date: "10/03/2025", Observation: "Plantão"}
+
+	tests := []struct {
+		name    string
+		id      int // ID devolvido pela API ao recriar
+		wantErr error
+	}{
+		{"recriação registrada", 9, nil},
+		{"API sem o ID", 0, errMissingEntryID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := newTestBot(t, createServer(tt.id))
+			op, err := b.journal.Record(journal.Operation{Kind: journal.OperationDelete, ChatID: testChatID, User: "Ana", EntryID: 5, Previous: &previous})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = b.revertOperation(b.ctx, op, "Bia")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, tt.wantErr)
+			}
+
+			recent := b.journal.Recent(testChatID, 10)
+			if tt.wantErr != nil {
+				if len(recent) != 1 {
+					t.Errorf("diário com %d operações, esperado só a exclusão", len(recent))
+				}
+				return
+			}
+			if len(recent) != 2 {
+				t.Fatalf("diário com %d operações, esperado 2", len(recent))
+			}
+			created := recent[0]
+			if created.Kind != journal.OperationCreate || created.EntryID != tt.id || created.User != "Bia" {
+				t.Errorf("operação registrada = %+v, esperado criação do lançamento %d por Bia", created, tt.id)
+			}
+			if created.Current == nil || *created.Current != previous {
+				t.Errorf("valores da recriação = %+v, esperado %+v", created.Current, previous)
+			}
+		})
+	}
+}
+
+func TestSubmitCreateRejectsMissingID(t *testing.T) {
+	b, _ := newTestBot(t, createServer(0))
+	entry := models.TimeBalanceEntry{EmployeeID: "12", Amount: 3600, Date: "10/03/2025"}
+
+	text := b.submitCreate(testChatID, nil, entry)
+	if !strings.Contains(text, errMissingEntryID.Error()) {
+		t.Errorf("mensagem = %q, esperado o erro de ID ausente", text)
+	}
+	if ops := b.journal.Recent(testChatID, 10); len(ops) != 0 {
+		t.Errorf("diário = %+v, esperado vazio", ops)
+	}
+}
@@ -0,0 +1,279 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/command"
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+	"github.com/jeffemart/PontoGo/app/internal/services/pontomais"
+	"github.com/jeffemart/PontoGo/app/internal/utils"
+)
+
+// wizardAction é a ação dos botões inline do assistente do /criar
+const wizardAction = "assistente"
+
+// maxWizardCandidates é a quantidade máxima de colaboradores oferecidos na busca
+const maxWizardCandidates = 8
+
+// startCreateWizard inicia o assistente do /criar, usado quando o comando é
+// enviado sem argumentos
+func (b *Bot) startCreateWizard(message *tgbotapi.Message) {
+	utils.Logger.Printf("Assistente do /criar iniciado no chat ID: %d", message.Chat.ID)
+	b.setConversation(message.Chat.ID, conversation{flow: flowCreate, step: stepEmployee})
+	b.api.Send(tgbotapi.NewMessage(message.Chat.ID,
+		"Criação de lançamento no banco de horas. Use /cancelar para sair a qualquer momento.\n\n1/5 - Colaborador: digite parte do nome ou o ID."))
+}
+
+// startCreateWizardFor inicia o assistente do /criar com o colaborador já
+// escolhido, a partir do botão "Criar lançamento" do /buscar
+func (b *Bot) startCreateWizardFor(chatID int64, employee models.Employee) {
+	utils.Logger.Printf("Assistente do /criar iniciado no chat ID: %d para o colaborador %d", chatID, employee.ID)
+	conv := conversation{flow: flowCreate, employeeName: employeeName(employee)}
+	conv.entry.EmployeeID = strconv.Itoa(employee.ID)
+	b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf(
+		"Criação de lançamento no banco de horas. Use /cancelar para sair a qualquer momento.\n\nColaborador: %s", employeeLabel(employee))))
+	b.askKind(chatID, conv)
+}
+
+// errStepAnswered indica um botão de um passo do assistente que já foi respondido
+var errStepAnswered = errors.New("Este passo já foi respondido.")
+
+// wizardAnswer aplica a resposta digitada ao passo atual do assistente do
+// /criar e devolve a conversa no passo seguinte; depois da observação o passo é
+// stepDone. Não envia mensagens: o erro já é o texto a mostrar ao chat. O passo
+// do colaborador depende da busca na API e é tratado por offerEmployees.
+func wizardAnswer(conv conversation, text string, now time.Time) (conversation, error) {
+	text = strings.TrimSpace(text)
+	switch conv.step {
+	case stepKind:
+		return conv, errors.New("Escolha Crédito ou Débito nos botões acima, ou use /cancelar.")
+
+	case stepAmount:
+		seconds, err := duration.Parse(text)
+		if err != nil {
+			return conv, fmt.Errorf("Erro: %v.\n\nDigite a quantidade novamente, ex.: 2h30m, 02:30, 90min ou 7200.", err)
+		}
+		conv.entry.Amount = seconds
+		conv.step = stepDate
+
+	case stepDate:
+		date, err := command.ParseDate(text, now)
+		if err != nil {
+			return conv, errors.New("Data inválida. Escolha um dia no calendário ou digite AAAA-MM-DD, DD/MM/AAAA, DD/MM, hoje ou ontem.")
+		}
+		conv.entry.Date = date.Format("02/01/2006")
+		conv.step = stepObservation
+
+	case stepObservation:
+		if text == "" {
+			return conv, errors.New("Digite o motivo do lançamento.")
+		}
+		conv.entry.Observation = text
+		conv.step = stepDone
+
+	default:
+		return conv, fmt.Errorf("passo do assistente inesperado: %s", conv.step)
+	}
+	return conv, nil
+}
+
+// wizardChoice aplica um botão do assistente do /criar (colaborador, tipo ou
+// dia do calendário) ao passo atual e devolve a conversa no passo seguinte.
+// Botões de passos já respondidos devolvem errStepAnswered.
+func wizardChoice(conv conversation, choice, value string) (conversation, error) {
+	switch {
+	case choice == "colaborador" && conv.step == stepEmployee:
+		name, ok := conv.candidates[value]
+		if !ok {
+			return conv, errors.New("Colaborador inválido.")
+		}
+		conv.entry.EmployeeID = value
+		conv.employeeName = name
+		conv.candidates = nil
+		conv.step = stepKind
+
+	case choice == "tipo" && conv.step == stepKind:
+		if value != "credito" && value != "debito" {
+			return conv, errors.New("Tipo inválido.")
+		}
+		conv.entry.Withdraw = value == "debito"
+		conv.step = stepAmount
+
+	case choice == "data" && conv.step == stepDate:
+		date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return conv, errors.New("Data inválida.")
+		}
+		conv.entry.Date = date.Format("02/01/2006")
+		conv.step = stepObservation
+
+	default:
+		return conv, errStepAnswered
+	}
+	return conv, nil
+}
+
+// handleWizardText trata as respostas digitadas no assistente do /criar
+func (b *Bot) handleWizardText(message *tgbotapi.Message, conv conversation) {
+	chatID := message.Chat.ID
+	b.setConversation(chatID, conv) // Qualquer resposta renova o prazo
+
+	if conv.step == stepEmployee {
+		b.offerEmployees(chatID, conv, strings.TrimSpace(message.Text))
+		return
+	}
+
+	next, err := wizardAnswer(conv, message.Text, time.Now())
+	if err != nil {
+		b.api.Send(tgbotapi.NewMessage(chatID, err.Error()))
+		return
+	}
+	switch next.step {
+	case stepDate:
+		b.api.Send(tgbotapi.NewMessage(chatID, "Quantidade: "+duration.Describe(next.entry.Amount)))
+		b.askDate(chatID, next)
+	case stepObservation:
+		b.askObservation(chatID, next)
+	case stepDone:
+		b.endConversation(chatID)
+		utils.Logger.Printf("Assistente do /criar concluído no chat ID: %d", chatID)
+		b.confirmEntry(chatID, "", next.entry)
+	}
+}
+
+// offerEmployees busca os colaboradores pelo texto digitado e oferece os
+// encontrados em botões
+func (b *Bot) offerEmployees(chatID int64, conv conversation, query string) {
+	employees, err := b.client.GetEmployees(b.ctx, pontomais.EmployeeFilter{})
+	if err != nil {
+		utils.Logger.Printf("Erro ao buscar colaboradores para o assistente: %v", err)
+		b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Erro ao buscar colaboradores: %s\n\nTente novamente ou use /cancelar.", describeError(err))))
+		return
+	}
+
+	matches := searchEmployees(employees, query)
+	if len(matches) == 0 {
+		b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Nenhum colaborador ativo encontrado para \"%s\". Digite outro nome ou o ID.", query)))
+		return
+	}
+
+	text := "Escolha o colaborador:"
+	if len(matches) > maxWizardCandidates {
+		text = fmt.Sprintf("Encontrados %d colaboradores; mostrando os %d primeiros. Escolha um ou digite um nome mais completo:", len(matches), maxWizardCandidates)
+		matches = matches[:maxWizardCandidates]
+	}
+
+	conv.candidates = make(map[string]string, len(matches))
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, employee := range matches {
+		id := strconv.Itoa(employee.ID)
+		conv.candidates[id] = employeeName(employee)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(employeeLabel(employee), callbackData(wizardAction, "colaborador", id)),
+		))
+	}
+	b.setConversation(chatID, conv)
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.api.Send(msg)
+}
+
+// handleWizardCallback trata os botões do assistente do /criar
+func (b *Bot) handleWizardCallback(query *tgbotapi.CallbackQuery, args []string) {
+	chatID := query.Message.Chat.ID
+	messageID := query.Message.MessageID
+	if len(args) == 0 || args[0] == "nada" {
+		b.answerCallback(query, "")
+		return
+	}
+	if len(args) != 2 {
+		b.answerCallback(query, "Botão inválido.")
+		return
+	}
+
+	conv, ok := b.activeConversation(chatID)
+	if !ok || conv.flow != flowCreate {
+		b.answerCallback(query, "O assistente não está mais ativo.")
+		b.editMessage(chatID, messageID, "Este assistente foi encerrado. Envie /criar para recomeçar.", nil)
+		return
+	}
+
+	// A navegação entre os meses do calendário não muda o passo
+	if args[0] == "mes" && conv.step == stepDate {
+		month, err := time.ParseInLocation("2006-01", args[1], time.Local)
+		if err != nil {
+			b.answerCallback(query, "Mês inválido.")
+			return
+		}
+		b.answerCallback(query, "")
+		b.setConversation(chatID, conv)
+		markup := calendarKeyboard(wizardAction, month, time.Now())
+		b.editMessage(chatID, messageID, query.Message.Text, &markup)
+		return
+	}
+
+	next, err := wizardChoice(conv, args[0], args[1])
+	if err != nil {
+		b.answerCallback(query, err.Error())
+		return
+	}
+	b.answerCallback(query, "")
+	switch next.step {
+	case stepKind:
+		b.editMessage(chatID, messageID, fmt.Sprintf("Colaborador: %s (ID %s)", next.employeeName, next.entry.EmployeeID), nil)
+		b.askKind(chatID, next)
+	case stepAmount:
+		kind := "Crédito"
+		if next.entry.Withdraw {
+			kind = "Débito (retirada)"
+		}
+		b.editMessage(chatID, messageID, "Tipo: "+kind, nil)
+		b.askAmount(chatID, next)
+	case stepObservation:
+		b.editMessage(chatID, messageID, "Data: "+next.entry.Date, nil)
+		b.askObservation(chatID, next)
+	}
+}
+
+// askKind pergunta se o lançamento é crédito ou débito
+func (b *Bot) askKind(chatID int64, conv conversation) {
+	conv.step = stepKind
+	b.setConversation(chatID, conv)
+	msg := tgbotapi.NewMessage(chatID, "2/5 - Tipo do lançamento:")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Crédito", callbackData(wizardAction, "tipo", "credito")),
+		tgbotapi.NewInlineKeyboardButtonData("Débito (retirada)", callbackData(wizardAction, "tipo", "debito")),
+	))
+	b.api.Send(msg)
+}
+
+// askAmount pede a quantidade do lançamento
+func (b *Bot) askAmount(chatID int64, conv conversation) {
+	conv.step = stepAmount
+	b.setConversation(chatID, conv)
+	b.api.Send(tgbotapi.NewMessage(chatID, "3/5 - Quantidade: digite, por exemplo, 2h30m, 02:30, 90min ou 7200 (segundos)."))
+}
+
+// askDate pede a data do lançamento com um calendário do mês atual
+func (b *Bot) askDate(chatID int64, conv conversation) {
+	conv.step = stepDate
+	b.setConversation(chatID, conv)
+	now := time.Now()
+	msg := tgbotapi.NewMessage(chatID, "4/5 - Data: escolha o dia ou digite a data (AAAA-MM-DD, DD/MM/AAAA ou DD/MM).")
+	msg.ReplyMarkup = calendarKeyboard(wizardAction, now, now)
+	b.api.Send(msg)
+}
+
+// askObservation pede a observação do lançamento
+func (b *Bot) askObservation(chatID int64, conv conversation) {
+	conv.step = stepObservation
+	b.setConversation(chatID, conv)
+	b.api.Send(tgbotapi.NewMessage(chatID, "5/5 - Observação: digite o motivo do lançamento."))
+}
@@ -0,0 +1,152 @@
+package telegram
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+func TestWizardFlow(t *testing.T) {
+	now := time.Date(2025, 3, 18, 10, 0, 0, 0, time.Local)
+	conv := conversation{flow: flowCreate, step: stepEmployee, candidates: map[string]string{"101": "Ana Souza", "102": "Bia Lima"}}
+
+	steps := []struct {
+		name   string
+		choice string // Botão escolhido; vazio quando a resposta é digitada
+		value  string
+		want   conversationStep
+	}{
+		{"colaborador", "colaborador", "102", stepKind},
+		{"tipo", "tipo", "debito", stepAmount},
+		{"quantidade", "", "2h30", stepDate},
+		{"data digitada", "", "ontem", stepObservation},
+		{"observação", "", "  Consulta médica ", stepDone},
+	}
+	for _, step := range steps {
+		var err error
+		if step.choice != "" {
+			conv, err = wizardChoice(conv, step.choice, step.value)
+		} else {
+			conv, err = wizardAnswer(conv, step.value, now)
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if conv.step != step.want {
+			t.Fatalf("%s: passo = %s, esperado %s", step.name, conv.step, step.want)
+		}
+	}
+
+	want := models.TimeBalanceEntry{EmployeeID: "102", Amount: 9000, Date: "17/03/2025", Observation: "Consulta médica", Withdraw: true}
+	if conv.entry != want {
+		t.Errorf("lançamento = %+v, esperado %+v", conv.entry, want)
+	}
+	if conv.employeeName != "Bia Lima" || conv.candidates != nil {
+		t.Errorf("colaborador = %q, candidatos = %v", conv.employeeName, conv.candidates)
+	}
+}
+
+func TestWizardCalendarDate(t *testing.T) {
+	conv, err := wizardChoice(conversation{flow: flowCreate, step: stepDate}, "data", "2025-02-28")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conv.step != stepObservation || conv.entry.Date != "28/02/2025" {
+		t.Errorf("passo = %s, data = %s; esperado %s, 28/02/2025", conv.step, conv.entry.Date, stepObservation)
+	}
+}
+
+func TestWizardInvalidInput(t *testing.T) {
+	now := time.Date(2025, 3, 18, 10, 0, 0, 0, time.Local)
+	candidates := map[string]string{"101": "Ana Souza"}
+
+	tests := []struct {
+		name    string
+		step    conversationStep
+		choice  string // Botão; vazio quando a resposta é digitada
+		value   string
+		wantErr string
+	}{
+		{"colaborador fora da busca", stepEmployee, "colaborador", "999", "Colaborador inválido"},
+		{"tipo digitado", stepKind, "", "crédito", "Escolha Crédito ou Débito"},
+		{"tipo desconhecido", stepKind, "tipo", "bonus", "Tipo inválido"},
+		{"quantidade ambígua", stepAmount, "", "2", "quantidade ambígua"},
+		{"quantidade inválida", stepAmount, "", "duas horas", "Digite a quantidade novamente"},
+		{"minutos acima de 59", stepAmount, "", "1h90", "Digite a quantidade novamente"},
+		{"data inválida", stepDate, "", "31/02/2025", "Data inválida"},
+		{"data do calendário inválida", stepDate, "data", "2025-13-01", "Data inválida"},
+		{"observação vazia", stepObservation, "", "   ", "Digite o motivo"},
+		{"botão de passo já respondido", stepAmount, "tipo", "credito", errStepAnswered.Error()},
+		{"colaborador depois da escolha", stepDate, "colaborador", "101", errStepAnswered.Error()},
+	}
+	for _, tt := range tests {
+		conv := conversation{flow: flowCreate, step: tt.step, candidates: candidates}
+		var (
+			next conversation
+			err  error
+		)
+		if tt.choice != "" {
+			next, err = wizardChoice(conv, tt.choice, tt.value)
+		} else {
+			next, err = wizardAnswer(conv, tt.value, now)
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: erro = %v, esperado %q", tt.name, err, tt.wantErr)
+			continue
+		}
+		if next.step != tt.step {
+			t.Errorf("%s: passo mudou para %s após entrada inválida", tt.name, next.step)
+		}
+	}
+
+	if _, err := wizardChoice(conversation{step: stepKind}, "tipo", "credito"); errors.Is(err, errStepAnswered) {
+		t.Error("o botão do passo atual foi tratado como já respondido")
+	}
+}
+
+func TestWizardTextMessages(t *testing.T) {
+	b, fake := newTestBot(t, nil)
+	b.setConversation(testChatID, conversation{flow: flowCreate, step: stepAmount, entry: models.TimeBalanceEntry{EmployeeID: "101"}})
+	send := func(text string) {
+		conv, ok := b.activeConversation(testChatID)
+		if !ok {
+			t.Fatalf("conversa encerrada antes de %q", text)
+		}
+		b.handleConversationMessage(&tgbotapi.Message{Text: text, Chat: &tgbotapi.Chat{ID: testChatID}}, conv)
+	}
+
+	send("abc")
+	if conv, _ := b.activeConversation(testChatID); conv.step != stepAmount {
+		t.Fatalf("passo após quantidade inválida = %s", conv.step)
+	}
+	send("90min")
+	send("18/03/2025")
+	send("Plantão")
+
+	if _, ok := b.activeConversation(testChatID); ok {
+		t.Error("conversa ativa após a observação, esperado encerrada")
+	}
+	texts := fake.texts()
+	for _, want := range []string{"Digite a quantidade novamente", "Quantidade: 01:30", "4/5 - Data", "5/5 - Observação"} {
+		if !containsText(texts, want) {
+			t.Errorf("mensagem %q não enviada: %q", want, texts)
+		}
+	}
+	if len(b.pendingEntries) != 1 {
+		t.Errorf("%d lançamentos aguardando confirmação, esperado 1", len(b.pendingEntries))
+	}
+}
+
+// containsText indica se alguma das mensagens contém o trecho
+func containsText(texts []string, part string) bool {
+	for _, text := range texts {
+		if strings.Contains(text, part) {
+			return true
+		}
+	}
+	return false
+}
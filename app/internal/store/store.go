@@ -0,0 +1,84 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONFile persiste um valor serializado em JSON em um arquivo local. A
+// gravação é atômica (arquivo temporário + rename), de forma que uma queda do
+// processo no meio da escrita não corrompe os dados já salvos.
+type JSONFile struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewJSONFile cria um JSONFile no caminho informado, criando o diretório se necessário
+func NewJSONFile(path string) (*JSONFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("erro ao criar o diretório de dados: %w", err)
+	}
+	return &JSONFile{path: path}, nil
+}
+
+// Path devolve o caminho do arquivo
+func (f *JSONFile) Path() string {
+	return f.path
+}
+
+// Load lê o arquivo para v. Se o arquivo ainda não existir, v não é alterado e
+// nenhum erro é retornado.
+func (f *JSONFile) Load(v interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao ler %s: %w", f.path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("erro ao deserializar %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// Save grava v no arquivo de forma atômica
+func (f *JSONFile) Save(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar %s: %w", f.path, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("erro ao criar arquivo temporário: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("erro ao gravar %s: %w", f.path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("erro ao gravar %s: %w", f.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("erro ao gravar %s: %w", f.path, err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("erro ao substituir %s: %w", f.path, err)
+	}
+	return nil
+}
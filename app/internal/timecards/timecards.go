@@ -0,0 +1,209 @@
+package timecards
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/duration"
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// IssueKind identifica o tipo de ocorrência encontrada nas batidas de um dia
+type IssueKind string
+
+// Ocorrências detectadas por Analyze
+const (
+	IssueNoPunches  IssueKind = "sem_batidas"        // Dia útil sem nenhuma batida
+	IssueOddPunches IssueKind = "batida_faltando"    // Quantidade ímpar de batidas
+	IssueNoBreak    IssueKind = "sem_intervalo"      // Trabalho contínuo acima do limite
+	IssueOvertime   IssueKind = "hora_extra"         // Trabalhou além da jornada
+	IssueUndertime  IssueKind = "jornada_incompleta" // Trabalhou menos que a jornada
+)
+
+// IssueKinds lista todas as ocorrências, na ordem em que aparecem nos relatórios
+var IssueKinds = []IssueKind{IssueOddPunches, IssueNoPunches, IssueNoBreak, IssueOvertime, IssueUndertime}
+
+// issueLabels são os nomes das ocorrências mostrados nos relatórios
+var issueLabels = map[IssueKind]string{
+	IssueNoPunches:  "Nenhuma batida",
+	IssueOddPunches: "Batida faltando",
+	IssueNoBreak:    "Sem intervalo",
+	IssueOvertime:   "Hora extra",
+	IssueUndertime:  "Jornada incompleta",
+}
+
+// Label devolve o nome da ocorrência mostrado nos relatórios
+func (k IssueKind) Label() string {
+	if label, ok := issueLabels[k]; ok {
+		return label
+	}
+	return string(k)
+}
+
+// ParseIssueKinds interpreta uma lista de ocorrências separadas por vírgula,
+// ex.: "batida_faltando,sem_batidas,hora_extra"
+func ParseIssueKinds(list string) ([]IssueKind, error) {
+	var kinds []IssueKind
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		kind := IssueKind(name)
+		if _, ok := issueLabels[kind]; !ok {
+			valid := make([]string, len(IssueKinds))
+			for i, k := range IssueKinds {
+				valid[i] = string(k)
+			}
+			return nil, fmt.Errorf("ocorrência desconhecida '%s': use %s", name, strings.Join(valid, ", "))
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
+
+// Issue é uma ocorrência do dia, com a descrição mostrada ao usuário
+type Issue struct {
+	Kind    IssueKind
+	Message string
+}
+
+// Rules define os critérios usados por Analyze
+type Rules struct {
+	Tolerance     time.Duration  // Diferença entre o trabalhado e a jornada que não gera ocorrência
+	MaxContinuous time.Duration  // Trabalho contínuo máximo sem intervalo; zero não verifica
+	Workdays      []time.Weekday // Dias em que a falta de batidas é uma ocorrência
+}
+
+// DefaultRules são os critérios padrão: 10 minutos de tolerância, intervalo
+// obrigatório após 6 horas contínuas e dias úteis de segunda a sexta
+var DefaultRules = Rules{
+	Tolerance:     10 * time.Minute,
+	MaxContinuous: 6 * time.Hour,
+	Workdays:      []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+}
+
+// IsWorkday indica se a data cai em um dos dias úteis das regras
+func (r Rules) IsWorkday(date time.Time) bool {
+	for _, day := range r.Workdays {
+		if date.Weekday() == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Interval é um período trabalhado, entre uma entrada e a saída seguinte
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Day é o resultado da análise das batidas de um colaborador em um dia
+type Day struct {
+	Date      time.Time
+	Punches   []time.Time   // Batidas do dia, em ordem
+	Intervals []Interval    // Pares de entrada e saída; uma entrada sem saída fica de fora
+	Worked    time.Duration // Soma dos intervalos
+	Expected  time.Duration // Jornada esperada; zero quando desconhecida ou fora dos dias úteis
+	Invalid   int           // Batidas ignoradas por terem data ou hora inválida
+	Issues    []Issue
+}
+
+// Has indica se o dia tem uma ocorrência do tipo informado
+func (d Day) Has(kind IssueKind) bool {
+	for _, issue := range d.Issues {
+		if issue.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Difference devolve o trabalhado menos a jornada esperada
+func (d Day) Difference() time.Duration {
+	return d.Worked - d.Expected
+}
+
+// Analyze organiza as batidas do dia em intervalos de trabalho e aponta as
+// ocorrências. Batidas de outros dias são ignoradas; expected é a jornada
+// esperada nos dias úteis, zero quando desconhecida. Fora dos dias úteis a
+// jornada é zero e todo o trabalho conta como hora extra.
+func Analyze(cards []models.TimeCard, date time.Time, expected time.Duration, rules Rules) Day {
+	workday := rules.IsWorkday(date)
+	if !workday {
+		expected = 0
+	}
+	day := Day{Date: date, Expected: expected}
+	year, month, dayOfMonth := date.Date()
+	for _, card := range cards {
+		punch, err := card.ParsedTime()
+		if err != nil {
+			day.Invalid++
+			continue
+		}
+		if y, m, d := punch.Date(); y == year && m == month && d == dayOfMonth {
+			day.Punches = append(day.Punches, punch)
+		}
+	}
+	sort.Slice(day.Punches, func(i, j int) bool { return day.Punches[i].Before(day.Punches[j]) })
+
+	for i := 0; i+1 < len(day.Punches); i += 2 {
+		interval := Interval{Start: day.Punches[i], End: day.Punches[i+1]}
+		day.Intervals = append(day.Intervals, interval)
+		day.Worked += interval.End.Sub(interval.Start)
+	}
+
+	switch {
+	case len(day.Punches) == 0:
+		if workday {
+			day.addIssue(IssueNoPunches, "nenhuma batida em dia útil")
+		}
+		return day
+	case len(day.Punches)%2 != 0:
+		last := day.Punches[len(day.Punches)-1]
+		day.addIssue(IssueOddPunches, fmt.Sprintf("quantidade ímpar de batidas (%d): falta a saída após %s", len(day.Punches), last.Format("15:04")))
+	}
+
+	if rules.MaxContinuous > 0 {
+		for _, interval := range day.Intervals {
+			if worked := interval.End.Sub(interval.Start); worked > rules.MaxContinuous {
+				day.addIssue(IssueNoBreak, fmt.Sprintf("%s contínuas sem intervalo, de %s a %s",
+					duration.Format(worked.Seconds()), interval.Start.Format("15:04"), interval.End.Format("15:04")))
+			}
+		}
+	}
+
+	// Com batida faltando, o total trabalhado é parcial e não é comparado à jornada
+	if (expected > 0 || !workday) && !day.Has(IssueOddPunches) {
+		switch difference := day.Difference(); {
+		case difference > rules.Tolerance:
+			day.addIssue(IssueOvertime, fmt.Sprintf("trabalhou %s além da jornada", duration.Format(difference.Seconds())))
+		case difference < -rules.Tolerance:
+			day.addIssue(IssueUndertime, fmt.Sprintf("trabalhou %s a menos que a jornada", duration.Format(-difference.Seconds())))
+		}
+	}
+	return day
+}
+
+// addIssue registra uma ocorrência do dia
+func (d *Day) addIssue(kind IssueKind, message string) {
+	d.Issues = append(d.Issues, Issue{Kind: kind, Message: message})
+}
+
+// ExpectedWork interpreta a jornada diária do cadastro do colaborador
+// (models.Employee.WorkHours), ex.: "08:00", "8h48m". Devolve false quando a
+// jornada não está preenchida ou não é uma quantidade de horas de um dia.
+func ExpectedWork(workHours string) (time.Duration, bool) {
+	workHours = strings.TrimSpace(workHours)
+	if workHours == "" {
+		return 0, false
+	}
+	seconds, err := duration.Parse(workHours)
+	if err != nil || seconds > 24*60*60 {
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
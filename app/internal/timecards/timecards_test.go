@@ -0,0 +1,112 @@
+package timecards
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jeffemart/PontoGo/app/internal/models"
+)
+
+// punches monta as batidas de um dia a partir dos horários
+func punches(date string, times ...string) []models.TimeCard {
+	cards := make([]models.TimeCard, len(times))
+	for i, t := range times {
+		cards[i] = models.TimeCard{ID: i + 1, Date: date, Time: t}
+	}
+	return cards
+}
+
+func TestAnalyze(t *testing.T) {
+	tuesday := time.Date(2025, 3, 18, 0, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, 3, 22, 0, 0, 0, 0, time.UTC)
+	noBreakCheck := DefaultRules
+	noBreakCheck.MaxContinuous = 0
+
+	tests := []struct {
+		name     string
+		cards    []models.TimeCard
+		date     time.Time
+		expected time.Duration
+		rules    Rules
+		worked   time.Duration
+		invalid  int
+		issues   []IssueKind
+	}{
+		{"jornada completa", punches("2025-03-18", "08:00", "12:00", "13:00", "17:00"), tuesday, 8 * time.Hour, DefaultRules, 8 * time.Hour, 0, nil},
+		{"dentro da tolerância", punches("2025-03-18", "08:00", "12:00", "13:00", "17:10"), tuesday, 8 * time.Hour, DefaultRules, 8*time.Hour + 10*time.Minute, 0, nil},
+		{"hora extra", punches("2025-03-18", "08:00", "12:00", "13:00", "18:00"), tuesday, 8 * time.Hour, DefaultRules, 9 * time.Hour, 0, []IssueKind{IssueOvertime}},
+		{"jornada incompleta", punches("2025-03-18", "08:00", "12:00"), tuesday, 8 * time.Hour, DefaultRules, 4 * time.Hour, 0, []IssueKind{IssueUndertime}},
+		{"batida faltando não compara a jornada", punches("2025-03-18", "08:00", "12:00", "13:00"), tuesday, 8 * time.Hour, DefaultRules, 4 * time.Hour, 0, []IssueKind{IssueOddPunches}},
+		{"sem batidas em dia útil", nil, tuesday, 8 * time.Hour, DefaultRules, 0, 0, []IssueKind{IssueNoPunches}},
+		{"sem batidas no sábado", nil, saturday, 8 * time.Hour, DefaultRules, 0, 0, nil},
+		{"trabalho no sábado é hora extra", punches("2025-03-22", "09:00", "11:00"), saturday, 8 * time.Hour, DefaultRules, 2 * time.Hour, 0, []IssueKind{IssueOvertime}},
+		{"sem intervalo", punches("2025-03-18", "08:00", "15:00"), tuesday, 7 * time.Hour, DefaultRules, 7 * time.Hour, 0, []IssueKind{IssueNoBreak}},
+		{"sem intervalo não verificado", punches("2025-03-18", "08:00", "15:00"), tuesday, 7 * time.Hour, noBreakCheck, 7 * time.Hour, 0, nil},
+		{"jornada desconhecida", punches("2025-03-18", "08:00", "12:00"), tuesday, 0, DefaultRules, 4 * time.Hour, 0, nil},
+		{
+			"fora de ordem, de outro dia e inválidas",
+			append(punches("2025-03-18", "17:00", "13:00", "12:00", "08:00"),
+				models.TimeCard{Date: "2025-03-17", Time: "22:00"},
+				models.TimeCard{Date: "18/03/2025", Time: "25:00"},
+				models.TimeCard{Date: "ontem", Time: "08:00"}),
+			tuesday, 8 * time.Hour, DefaultRules, 8 * time.Hour, 2, nil,
+		},
+		{"hora em RFC 3339", []models.TimeCard{{Time: "2025-03-18T08:00:00Z"}, {Time: "2025-03-18T16:00:00Z"}}, tuesday, 8 * time.Hour, DefaultRules, 8 * time.Hour, 0, []IssueKind{IssueNoBreak}},
+	}
+	for _, tt := range tests {
+		day := Analyze(tt.cards, tt.date, tt.expected, tt.rules)
+		if day.Worked != tt.worked || day.Invalid != tt.invalid {
+			t.Errorf("%s: trabalhado = %s, inválidas = %d; esperado %s e %d", tt.name, day.Worked, day.Invalid, tt.worked, tt.invalid)
+		}
+		var kinds []IssueKind
+		for _, issue := range day.Issues {
+			kinds = append(kinds, issue.Kind)
+			if issue.Message == "" {
+				t.Errorf("%s: ocorrência %s sem descrição", tt.name, issue.Kind)
+			}
+		}
+		if !reflect.DeepEqual(kinds, tt.issues) {
+			t.Errorf("%s: ocorrências = %v, esperado %v", tt.name, kinds, tt.issues)
+		}
+		for i := 1; i < len(day.Punches); i++ {
+			if day.Punches[i].Before(day.Punches[i-1]) {
+				t.Errorf("%s: batidas fora de ordem", tt.name)
+			}
+		}
+		if len(day.Intervals) != len(day.Punches)/2 {
+			t.Errorf("%s: %d intervalos para %d batidas", tt.name, len(day.Intervals), len(day.Punches))
+		}
+	}
+}
+
+func TestExpectedWork(t *testing.T) {
+	tests := []struct {
+		workHours string
+		want      time.Duration
+		ok        bool
+	}{
+		{"08:00", 8 * time.Hour, true},
+		{"8h48m", 8*time.Hour + 48*time.Minute, true},
+		{" 06:00 ", 6 * time.Hour, true},
+		{"", 0, false},
+		{"44:00", 0, false},
+		{"integral", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ExpectedWork(tt.workHours)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ExpectedWork(%q) = %s, %v; esperado %s, %v", tt.workHours, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseIssueKinds(t *testing.T) {
+	kinds, err := ParseIssueKinds(" Batida_Faltando, ,hora_extra")
+	if err != nil || !reflect.DeepEqual(kinds, []IssueKind{IssueOddPunches, IssueOvertime}) {
+		t.Errorf("ParseIssueKinds = %v, %v", kinds, err)
+	}
+	if _, err := ParseIssueKinds("batida_faltando,atraso"); err == nil {
+		t.Error("ocorrência desconhecida aceita")
+	}
+}